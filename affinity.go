@@ -0,0 +1,111 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAffinityConflict is returned when a prefix is already claimed by another
+// affinity group
+var ErrAffinityConflict = errors.New("prefix belongs to another affinity group")
+
+// SetAffinityGroup declares that keys starting with any of prefixes live on
+// the same node, for workflows that need cross-key transactions on one shard.
+// Grouped keys are placed as if they were the group name, so the group moves
+// as a unit on membership changes. When prefixes overlap the longest match
+// wins. Calling it again replaces the group's prefixes. Regrouping moves
+// keys, so it advances the ring generation like a membership change.
+func (hr *HashRing) SetAffinityGroup(name string, prefixes ...string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("affinity group name cannot be empty")
+	}
+	if len(prefixes) == 0 {
+		return errors.New("affinity group needs at least one prefix")
+	}
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			return errors.New("affinity prefix cannot be empty")
+		}
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	for _, prefix := range prefixes {
+		if group, exists := hr.affinity[prefix]; exists && group != name {
+			return fmt.Errorf("%w: %q is in group %s", ErrAffinityConflict, prefix, group)
+		}
+	}
+
+	hr.removeAffinityLocked(name)
+	if hr.affinity == nil {
+		hr.affinity = make(map[string]string, len(prefixes))
+	}
+	for _, prefix := range prefixes {
+		hr.affinity[prefix] = name
+	}
+	hr.advanceGenerationLocked()
+	return nil
+}
+
+// RemoveAffinityGroup drops a group, returning its keys to normal placement.
+// It reports whether the group existed.
+func (hr *HashRing) RemoveAffinityGroup(name string) bool {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	if !hr.removeAffinityLocked(name) {
+		return false
+	}
+	hr.advanceGenerationLocked()
+	return true
+}
+
+// AffinityGroup returns the group a key belongs to, if any
+func (hr *HashRing) AffinityGroup(key string) (string, bool) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	group := hr.routingKeyLocked(key)
+	return group, len(hr.affinity) > 0 && group != key
+}
+
+func (hr *HashRing) removeAffinityLocked(name string) bool {
+	found := false
+	for prefix, group := range hr.affinity {
+		if group == name {
+			delete(hr.affinity, prefix)
+			found = true
+		}
+	}
+	return found
+}
+
+// routingKeyLocked returns the key lookups place: the key's affinity group
+// name, or the key itself. Callers must hold hr.mu.
+func (hr *HashRing) routingKeyLocked(key string) string {
+	return routingKey(hr.affinity, key)
+}
+
+// routingKey resolves key against a prefix to group mapping, preferring the
+// longest matching prefix
+func routingKey(affinity map[string]string, key string) string {
+	if len(affinity) == 0 {
+		return key
+	}
+
+	group, longest := key, -1
+	for prefix, name := range affinity {
+		if len(prefix) > longest && strings.HasPrefix(key, prefix) {
+			group, longest = name, len(prefix)
+		}
+	}
+	return group
+}
+
+// keyHashLocked returns the ring position lookups use for key, honouring
+// affinity groups. Callers must hold hr.mu.
+func (hr *HashRing) keyHashLocked(key string) uint64 {
+	return hr.hash(hr.routingKeyLocked(key))
+}
@@ -0,0 +1,101 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAffinityGroups(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 8; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	if err := ring.SetAffinityGroup("order:42", "order:42:", "invoice:42:", "payment:42:"); err != nil {
+		t.Fatalf("Failed to set affinity group: %v", err)
+	}
+
+	keys := []string{"order:42:items", "invoice:42:pdf", "payment:42:card", "order:42:"}
+	assertColocated := func() string {
+		t.Helper()
+		owner, _ := ring.GetOwnerID(keys[0])
+		for _, key := range keys[1:] {
+			if id, _ := ring.GetOwnerID(key); id != owner {
+				t.Fatalf("Expected %q on %s, got %s", key, owner, id)
+			}
+			if node, _ := ring.GetNode(key); node.ID != owner {
+				t.Fatalf("GetNode disagrees for %q", key)
+			}
+			if nodes, _ := ring.GetNodes(key, 2); nodes[0].ID != owner {
+				t.Fatalf("GetNodes disagrees for %q", key)
+			}
+		}
+		return owner
+	}
+
+	// Co-location survives membership changes
+	owner := assertColocated()
+	ring.RemoveNode(owner)
+	assertColocated()
+	ring.AddNode(&Node{ID: "node9", Host: "localhost", Port: 8099})
+	assertColocated()
+
+	if group, ok := ring.AffinityGroup("invoice:42:pdf"); !ok || group != "order:42" {
+		t.Errorf("Expected group order:42, got %q %v", group, ok)
+	}
+	if _, ok := ring.AffinityGroup("invoice:43:pdf"); ok {
+		t.Error("Expected ungrouped key")
+	}
+	if ring.HashKey("payment:42:card") != ring.HashKey("order:42:items") {
+		t.Error("Expected grouped keys to share a ring position")
+	}
+
+	// Removing the group restores hash placement
+	generation := ring.Generation()
+	if !ring.RemoveAffinityGroup("order:42") || ring.Generation() != generation+1 {
+		t.Error("Expected the removal to advance the generation")
+	}
+	if ring.HashKey("payment:42:card") != ring.hash("payment:42:card") {
+		t.Error("Expected normal placement after removing the group")
+	}
+	if ring.RemoveAffinityGroup("order:42") {
+		t.Error("Expected removing a missing group to report false")
+	}
+}
+
+func TestAffinityGroupPrefixes(t *testing.T) {
+	ring, _ := NewHashRing(10)
+
+	ring.SetAffinityGroup("tenant", "t:")
+	ring.SetAffinityGroup("vip", "t:vip:")
+	if group, _ := ring.AffinityGroup("t:vip:1"); group != "vip" {
+		t.Errorf("Expected the longest prefix to win, got %q", group)
+	}
+
+	if err := ring.SetAffinityGroup("other", "t:"); !errors.Is(err, ErrAffinityConflict) {
+		t.Errorf("Expected ErrAffinityConflict, got %v", err)
+	}
+
+	// Redefining a group replaces its prefixes
+	ring.SetAffinityGroup("tenant", "u:")
+	if _, ok := ring.AffinityGroup("t:1"); ok {
+		t.Error("Expected the old prefix to be released")
+	}
+	if err := ring.SetAffinityGroup("other", "t:"); err != nil {
+		t.Errorf("Expected the released prefix to be free, got %v", err)
+	}
+
+	if err := ring.SetAffinityGroup("", "x:"); err == nil {
+		t.Error("Expected error for empty group name")
+	}
+	if err := ring.SetAffinityGroup("x"); err == nil {
+		t.Error("Expected error for a group without prefixes")
+	}
+	if err := ring.SetAffinityGroup("x", ""); err == nil {
+		t.Error("Expected error for an empty prefix")
+	}
+}
@@ -0,0 +1,127 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// Fingerprint returns a hash of everything that decides routing: the position
+// and owner of every virtual node and each owner's address. Routers that
+// report the same fingerprint route every key to the same node.
+func (hr *HashRing) Fingerprint() uint64 {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.fingerprintLocked()
+}
+
+// fingerprintLocked is Fingerprint for callers that hold hr.mu
+func (hr *HashRing) fingerprintLocked() uint64 {
+	h := uint64(fnvOffset64)
+	write := func(b []byte) {
+		for _, c := range b {
+			h ^= uint64(c)
+			h *= fnvPrime64
+		}
+	}
+
+	var buf []byte
+	for _, vnode := range hr.virtualNodes {
+		buf = strconv.AppendUint(buf[:0], vnode.Hash, 16)
+		buf = append(buf, ':')
+		buf = append(buf, vnode.Node.ID...)
+		buf = append(buf, '@')
+		buf = append(buf, vnode.Node.Host...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(vnode.Node.Port), 10)
+		buf = append(buf, ';')
+		write(buf)
+	}
+
+	return h
+}
+
+// RingState identifies the ring a router is using
+type RingState struct {
+	Peer        string `json:"peer"`
+	Fingerprint uint64 `json:"fingerprint"`
+	Generation  uint64 `json:"generation"`
+}
+
+// State returns the ring's fingerprint and generation, read atomically, for
+// sharing with peers
+func (hr *HashRing) State() RingState {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return RingState{Fingerprint: hr.fingerprintLocked(), Generation: hr.generation}
+}
+
+// StateTransport fetches the ring state of a peer. It is implemented by the
+// caller over whatever channel the routers already share, such as an HTTP
+// endpoint serving State as JSON.
+type StateTransport interface {
+	FetchState(ctx context.Context, peer string) (RingState, error)
+}
+
+// StateTransportFunc adapts a function to the StateTransport interface
+type StateTransportFunc func(ctx context.Context, peer string) (RingState, error)
+
+// FetchState calls f
+func (f StateTransportFunc) FetchState(ctx context.Context, peer string) (RingState, error) {
+	return f(ctx, peer)
+}
+
+// AgreementReport is the outcome of CheckAgreement
+type AgreementReport struct {
+	Local       RingState
+	Peers       []RingState      // States of the reachable peers, in request order
+	Diverged    []string         // Peers whose fingerprint differs from the local ring, sorted
+	Unreachable map[string]error // Peers whose state could not be fetched
+}
+
+// Agreed reports whether every peer was reached and uses the same ring
+func (r AgreementReport) Agreed() bool {
+	return len(r.Diverged) == 0 && len(r.Unreachable) == 0
+}
+
+// CheckAgreement fetches the state of each peer and compares its fingerprint
+// with the local ring's. Generations are reported but not compared, since
+// routers that reached the same membership along different paths count
+// changes differently.
+func CheckAgreement(ctx context.Context, ring *HashRing, transport StateTransport, peers []string) (AgreementReport, error) {
+	if ring == nil {
+		return AgreementReport{}, errors.New("ring cannot be nil")
+	}
+	if transport == nil {
+		return AgreementReport{}, errors.New("transport cannot be nil")
+	}
+
+	report := AgreementReport{
+		Local:       ring.State(),
+		Unreachable: make(map[string]error),
+	}
+
+	for _, peer := range peers {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		state, err := transport.FetchState(ctx, peer)
+		if err != nil {
+			report.Unreachable[peer] = err
+			continue
+		}
+
+		state.Peer = peer
+		report.Peers = append(report.Peers, state)
+		if state.Fingerprint != report.Local.Fingerprint {
+			report.Diverged = append(report.Diverged, peer)
+		}
+	}
+	sort.Strings(report.Diverged)
+
+	return report, nil
+}
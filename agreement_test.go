@@ -0,0 +1,86 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newAgreementTestRing(t *testing.T, nodes int) *HashRing {
+	t.Helper()
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < nodes; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	return ring
+}
+
+func TestFingerprint(t *testing.T) {
+	a := newAgreementTestRing(t, 3)
+	b := newAgreementTestRing(t, 3)
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("Expected identical rings to have the same fingerprint")
+	}
+
+	// Same membership reached along a different path
+	b.AddNode(&Node{ID: "extra", Host: "localhost", Port: 9000})
+	b.RemoveNode("extra")
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("Expected fingerprint to depend only on the current membership")
+	}
+	if a.Generation() == b.Generation() {
+		t.Error("Expected generations to differ")
+	}
+
+	// An address change reroutes traffic even though positions are unchanged
+	b.RemoveNode("node1")
+	b.AddNode(&Node{ID: "node1", Host: "otherhost", Port: 8081})
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Error("Expected fingerprint to change with a node's address")
+	}
+}
+
+func TestCheckAgreement(t *testing.T) {
+	local := newAgreementTestRing(t, 3)
+	peers := map[string]*HashRing{
+		"same":     newAgreementTestRing(t, 3),
+		"diverged": newAgreementTestRing(t, 4),
+	}
+	transport := StateTransportFunc(func(ctx context.Context, peer string) (RingState, error) {
+		ring, ok := peers[peer]
+		if !ok {
+			return RingState{}, errors.New("connection refused")
+		}
+		return ring.State(), nil
+	})
+
+	if _, err := CheckAgreement(context.Background(), local, nil, nil); err == nil {
+		t.Error("Expected error for nil transport")
+	}
+
+	report, err := CheckAgreement(context.Background(), local, transport, []string{"same", "diverged", "down"})
+	if err != nil {
+		t.Fatalf("Failed to check agreement: %v", err)
+	}
+	if report.Agreed() {
+		t.Error("Expected disagreement")
+	}
+	if len(report.Diverged) != 1 || report.Diverged[0] != "diverged" {
+		t.Errorf("Expected only the diverged peer to be reported, got %v", report.Diverged)
+	}
+	if _, ok := report.Unreachable["down"]; !ok || len(report.Unreachable) != 1 {
+		t.Errorf("Expected the down peer to be unreachable, got %v", report.Unreachable)
+	}
+	if len(report.Peers) != 2 || report.Peers[0].Peer != "same" {
+		t.Errorf("Expected peer states in request order, got %+v", report.Peers)
+	}
+
+	report, _ = CheckAgreement(context.Background(), local, transport, []string{"same"})
+	if !report.Agreed() {
+		t.Errorf("Expected agreement, got %+v", report)
+	}
+}
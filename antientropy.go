@@ -0,0 +1,185 @@
+package consistenthashing
+
+import (
+	"slices"
+	"sort"
+)
+
+// Membership is what routers exchange for anti-entropy: the fingerprint to
+// detect divergence cheaply and the members to locate it
+type Membership struct {
+	Fingerprint uint64 `json:"fingerprint"`
+	Generation  uint64 `json:"generation"`
+	Nodes       []Node `json:"nodes"` // Sorted by ID
+}
+
+// Membership returns the ring's fingerprint and members, read atomically, for
+// sending to peers
+func (hr *HashRing) Membership() Membership {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	nodes := make([]Node, 0, len(hr.nodes))
+	for _, node := range hr.sortedNodesLocked() {
+		nodes = append(nodes, node.clone())
+	}
+	return Membership{
+		Fingerprint: hr.fingerprintLocked(),
+		Generation:  hr.generation,
+		Nodes:       nodes,
+	}
+}
+
+// DivergentRange is a hash range routed to different nodes by two rings. An
+// empty owner means that ring has no nodes.
+type DivergentRange struct {
+	Range  HashRange
+	Local  string
+	Remote string
+}
+
+// Divergence is the difference between the local ring and a peer's
+type Divergence struct {
+	Missing []string         // IDs only the peer has, sorted
+	Extra   []string         // IDs only the local ring has, sorted
+	Changed []string         // IDs both have with different fields, sorted
+	Ranges  []DivergentRange // Ranges routed differently, clockwise from zero
+}
+
+// Converged reports whether the two rings route every key alike
+func (d Divergence) Converged() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0 && len(d.Ranges) == 0
+}
+
+// CompareMembership returns exactly what differs between the local ring and a
+// peer's Membership: the nodes to reconcile and the hash ranges whose keys
+// are routed differently. The peer's members are placed with the local
+// ring's settings, so both routers must share them. Matching fingerprints
+// short-circuit to an empty result.
+func (hr *HashRing) CompareMembership(remote Membership) (Divergence, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	var d Divergence
+	if remote.Fingerprint == hr.fingerprintLocked() {
+		return d, nil
+	}
+
+	members := make([]*Node, len(remote.Nodes))
+	seen := make(map[string]bool, len(remote.Nodes))
+	for i := range remote.Nodes {
+		node := &remote.Nodes[i]
+		members[i] = node
+		seen[node.ID] = true
+
+		local, exists := hr.nodes[node.ID]
+		switch {
+		case !exists:
+			d.Missing = append(d.Missing, node.ID)
+		case !local.Equal(node):
+			d.Changed = append(d.Changed, node.ID)
+		}
+	}
+	for id := range hr.nodes {
+		if !seen[id] {
+			d.Extra = append(d.Extra, id)
+		}
+	}
+	sort.Strings(d.Missing)
+	sort.Strings(d.Extra)
+	sort.Strings(d.Changed)
+
+	placed, err := hr.placeLocked(members)
+	if err != nil {
+		return Divergence{}, err
+	}
+	d.Ranges = divergentRanges(hr.virtualNodes, placed)
+	return d, nil
+}
+
+// placeLocked returns the sorted virtual nodes the ring would have with nodes
+// as its members, without changing the ring. The virtual node cap is applied
+// to nodes as a whole.
+func (hr *HashRing) placeLocked(nodes []*Node) ([]VirtualNode, error) {
+	counts := make([]int, len(nodes))
+	total := 0
+	for i, node := range nodes {
+		if err := node.Validate(); err != nil {
+			return nil, err
+		}
+		count, err := hr.curveCount(node)
+		if err != nil {
+			return nil, err
+		}
+		counts[i] = count
+		total += count
+	}
+	total = hr.overCap(total)
+
+	var vnodes []VirtualNode
+	var buf []byte
+	for i, node := range nodes {
+		count := scaleCount(counts[i], total, hr.maxVirtualNodes)
+		for j := 0; j < count; j++ {
+			buf = hr.appendVirtualKey(buf[:0], node.ID, j)
+			vnodes = append(vnodes, VirtualNode{Hash: hr.hashBytes(buf), Node: node})
+		}
+	}
+	sort.SliceStable(vnodes, func(i, j int) bool {
+		return vnodes[i].Hash < vnodes[j].Hash
+	})
+	return vnodes, nil
+}
+
+// divergentRanges returns the ranges whose owner differs between two sorted
+// sets of virtual nodes, merging adjacent ranges with the same owners. Owners
+// are compared by ID, as in movedFraction.
+func divergentRanges(local, remote []VirtualNode) []DivergentRange {
+	switch {
+	case len(local) == 0 && len(remote) == 0:
+		return nil
+	case len(local) == 0:
+		return []DivergentRange{{Remote: remote[0].Node.ID}}
+	case len(remote) == 0:
+		return []DivergentRange{{Local: local[0].Node.ID}}
+	}
+
+	bounds := make([]uint64, 0, len(local)+len(remote))
+	for _, vnode := range local {
+		bounds = append(bounds, vnode.Hash)
+	}
+	for _, vnode := range remote {
+		bounds = append(bounds, vnode.Hash)
+	}
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	var ranges []DivergentRange
+	for i, end := range bounds {
+		localOwner := local[searchVirtualNodes(local, end)].Node.ID
+		remoteOwner := remote[searchVirtualNodes(remote, end)].Node.ID
+		if localOwner == remoteOwner {
+			continue
+		}
+
+		start := bounds[(i+len(bounds)-1)%len(bounds)]
+		if n := len(ranges); n > 0 && ranges[n-1].Range.End == start &&
+			ranges[n-1].Local == localOwner && ranges[n-1].Remote == remoteOwner {
+			ranges[n-1].Range.End = end
+			continue
+		}
+		ranges = append(ranges, DivergentRange{
+			Range:  HashRange{Start: start, End: end},
+			Local:  localOwner,
+			Remote: remoteOwner,
+		})
+	}
+
+	// The first range may continue the last one across zero
+	if n := len(ranges); n > 1 && ranges[n-1].Range.End == ranges[0].Range.Start &&
+		ranges[n-1].Local == ranges[0].Local && ranges[n-1].Remote == ranges[0].Remote {
+		ranges[0].Range.Start = ranges[n-1].Range.Start
+		ranges = ranges[:n-1]
+	}
+	return ranges
+}
@@ -0,0 +1,93 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestCompareMembership(t *testing.T) {
+	local, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	remote, _ := NewHashRing(20)
+	for i := 1; i <= 3; i++ {
+		local.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	remote.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8081})
+	remote.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8082, Weight: 2})
+	remote.AddNode(&Node{ID: "node4", Host: "localhost", Port: 8084})
+
+	d, err := local.CompareMembership(remote.Membership())
+	if err != nil {
+		t.Fatalf("Failed to compare: %v", err)
+	}
+	if fmt.Sprint(d.Missing) != "[node4]" || fmt.Sprint(d.Extra) != "[node3]" || fmt.Sprint(d.Changed) != "[node2]" {
+		t.Errorf("Unexpected node divergence: missing %v, extra %v, changed %v", d.Missing, d.Extra, d.Changed)
+	}
+	if d.Converged() {
+		t.Fatal("Expected divergent rings not to be converged")
+	}
+
+	// The ranges cover exactly the keys routed differently
+	total := 0.0
+	for _, r := range d.Ranges {
+		total += r.Range.Fraction()
+		if r.Local == r.Remote {
+			t.Errorf("Range %v has the same owner on both sides", r.Range)
+		}
+	}
+	expected := movedFraction(local.virtualNodes, remote.virtualNodes)
+	if math.Abs(total-expected) > 1e-9 {
+		t.Errorf("Expected ranges to cover %.6f of the ring, got %.6f", expected, total)
+	}
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		a, _ := local.GetNode(key)
+		b, _ := remote.GetNode(key)
+		hash := local.HashKey(key)
+
+		var found *DivergentRange
+		for j := range d.Ranges {
+			if d.Ranges[j].Range.Contains(hash) {
+				found = &d.Ranges[j]
+			}
+		}
+		switch {
+		case a.ID == b.ID && found != nil:
+			t.Errorf("Key %s routes alike but lies in divergent range %v", key, found.Range)
+		case a.ID != b.ID && (found == nil || found.Local != a.ID || found.Remote != b.ID):
+			t.Errorf("Key %s routes %s/%s but its range is %v", key, a.ID, b.ID, found)
+		}
+	}
+
+	// Identical rings short-circuit on the fingerprint
+	d, err = local.CompareMembership(local.Membership())
+	if err != nil || !d.Converged() {
+		t.Errorf("Expected a ring to converge with itself, got %+v, %v", d, err)
+	}
+}
+
+func TestCompareMembershipEmpty(t *testing.T) {
+	local, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	local.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	empty, _ := NewHashRing(10)
+
+	d, err := local.CompareMembership(empty.Membership())
+	if err != nil {
+		t.Fatalf("Failed to compare: %v", err)
+	}
+	if len(d.Ranges) != 1 || d.Ranges[0].Range.Fraction() != 1 || d.Ranges[0].Local != "node1" || d.Ranges[0].Remote != "" {
+		t.Errorf("Expected the whole ring to diverge, got %+v", d.Ranges)
+	}
+
+	// Invalid peer members are rejected
+	bad := Membership{Nodes: []Node{{ID: "x", Host: "localhost"}}}
+	if _, err := local.CompareMembership(bad); err == nil {
+		t.Error("Expected an invalid peer node to be rejected")
+	}
+}
@@ -0,0 +1,154 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// BalanceStats compares each node's share of the hash space with the share
+// its weight entitles it to, through the ring's weight curve. A skew of 1 is
+// a perfect fit; 1.5 means a node gets 50% more keys than its weight calls
+// for.
+type BalanceStats struct {
+	Generation  uint64
+	MaxSkew     float64
+	MinSkew     float64
+	MostLoaded  string // ID of the node with MaxSkew
+	LeastLoaded string // ID of the node with MinSkew
+}
+
+// BalanceStats computes the current balance of the ring. An empty ring has
+// zero skews.
+func (hr *HashRing) BalanceStats() BalanceStats {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	stats := BalanceStats{Generation: hr.generation}
+	if len(hr.nodes) == 0 {
+		return stats
+	}
+
+	entitled := hr.entitlementsLocked()
+	// Visit nodes in ID order so ties resolve deterministically
+	ownership := hr.ownershipLocked()
+	ids := make([]string, 0, len(ownership))
+	for id := range ownership {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for i, id := range ids {
+		skew := ownership[id] / entitled[id]
+		if i == 0 || skew > stats.MaxSkew {
+			stats.MaxSkew, stats.MostLoaded = skew, id
+		}
+		if i == 0 || skew < stats.MinSkew {
+			stats.MinSkew, stats.LeastLoaded = skew, id
+		}
+	}
+
+	return stats
+}
+
+// BalanceScore condenses the ring's balance into a single number between 0
+// and 1: the share of the hash space owned in line with the weights, through
+// the ring's weight curve. 1 means every node owns exactly its entitled share;
+// lower scores mean more of the space sits on over-loaded nodes. It is
+// computed from the ring's ranges rather than sampled keys, so it is stable
+// enough for alerting thresholds and autoscaler inputs. An empty ring scores 1.
+func (hr *HashRing) BalanceScore() float64 {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.nodes) == 0 {
+		return 1
+	}
+
+	entitled := hr.entitlementsLocked()
+	score := 0.0
+	for id, share := range hr.ownershipLocked() {
+		score += min(share, entitled[id])
+	}
+	return min(score, 1)
+}
+
+// Entitlements returns the share of the hash space each node is entitled to,
+// by node ID: its weight, or its capacity class's weight, through the ring's
+// weight curve. The shares sum to 1; an empty ring has none.
+func (hr *HashRing) Entitlements() map[string]float64 {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.entitlementsLocked()
+}
+
+// entitlementsLocked returns the share of the hash space each node's weight
+// entitles it to. Entitlements follow virtual node counts, which are
+// proportional to weight unless a weight curve says otherwise.
+func (hr *HashRing) entitlementsLocked() map[string]float64 {
+	counts := make(map[string]int, len(hr.nodes))
+	total := 0
+	for id, node := range hr.nodes {
+		counts[id], _ = hr.virtualCount(node)
+		total += counts[id]
+	}
+
+	entitled := make(map[string]float64, len(counts))
+	for id, count := range counts {
+		entitled[id] = float64(count) / float64(total)
+	}
+	return entitled
+}
+
+// BalanceMonitorConfig configures MonitorBalance
+type BalanceMonitorConfig struct {
+	Interval  time.Duration      // Time between checks, defaults to one minute
+	Threshold float64            // MaxSkew above which the ring counts as drifted, e.g. 1.25
+	OnDrift   func(BalanceStats) // Called when MaxSkew rises above Threshold
+	OnRecover func(BalanceStats) // Optional, called when MaxSkew falls back to Threshold or below
+}
+
+// MonitorBalance checks the ring's balance every interval and calls OnDrift
+// when the skew crosses the threshold, so degenerate ring states can raise
+// alerts. Callbacks fire on crossings, not on every check, and the balance is
+// only recomputed when the membership changed. It blocks until ctx is done.
+func (hr *HashRing) MonitorBalance(ctx context.Context, config BalanceMonitorConfig) error {
+	if config.Threshold < 1 {
+		return errors.New("balance threshold must be at least 1")
+	}
+	if config.OnDrift == nil {
+		return errors.New("drift callback cannot be nil")
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+
+	ticker := hr.clock.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	drifted := false
+	checked := false
+	var generation uint64
+	for {
+		if current := hr.Generation(); !checked || current != generation {
+			stats := hr.BalanceStats()
+			checked, generation = true, stats.Generation
+
+			switch over := stats.MaxSkew > config.Threshold; {
+			case over && !drifted:
+				config.OnDrift(stats)
+			case !over && drifted && config.OnRecover != nil:
+				config.OnRecover(stats)
+			}
+			drifted = stats.MaxSkew > config.Threshold
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package consistenthashing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBalanceStats(t *testing.T) {
+	ring, err := NewHashRing(100)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if stats := ring.BalanceStats(); stats.MaxSkew != 0 {
+		t.Errorf("Expected zero skew for an empty ring, got %+v", stats)
+	}
+
+	ring.AddNode(&Node{ID: "solo", Host: "localhost", Port: 8080})
+	if stats := ring.BalanceStats(); stats.MaxSkew != 1 || stats.MinSkew != 1 {
+		t.Errorf("Expected a single node to be perfectly balanced, got %+v", stats)
+	}
+
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 9000 + i, Weight: i + 1})
+	}
+	stats := ring.BalanceStats()
+	if stats.MaxSkew < 1 || stats.MinSkew > 1 || stats.MostLoaded == stats.LeastLoaded {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// The reported skew matches the node's ownership over its weight share
+	ownership, _ := ring.EstimateKeys(1 << 30)
+	node, _ := ring.GetNodeByID(stats.MostLoaded)
+	expected := float64(ownership[stats.MostLoaded]) / (1 << 30) / (float64(nodeWeight(node)) / 16)
+	if math.Abs(expected-stats.MaxSkew) > 1e-6 {
+		t.Errorf("Expected max skew %f, got %f", expected, stats.MaxSkew)
+	}
+}
+
+func TestEntitlements(t *testing.T) {
+	ring, err := NewHashRing(20, WithCapacityClasses(map[string]int{"big": 3}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if entitled := ring.Entitlements(); len(entitled) != 0 {
+		t.Errorf("Expected no entitlements for an empty ring, got %v", entitled)
+	}
+
+	ring.AddNode(&Node{ID: "small", Host: "localhost", Port: 8080, Weight: 1})
+	ring.AddNode(&Node{ID: "heavy", Host: "localhost", Port: 8081, Weight: 3})
+	ring.AddNode(&Node{ID: "big", Host: "localhost", Port: 8082, Weight: 1, Class: "big"})
+	entitled := ring.Entitlements()
+	for id, want := range map[string]float64{"small": 1.0 / 7, "heavy": 3.0 / 7, "big": 3.0 / 7} {
+		if math.Abs(entitled[id]-want) > 1e-9 {
+			t.Errorf("Expected %s entitled to %f, got %f", id, want, entitled[id])
+		}
+	}
+}
+
+func TestBalanceScore(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if score := ring.BalanceScore(); score != 1 {
+		t.Errorf("Expected an empty ring to score 1, got %f", score)
+	}
+
+	ring.AddNode(&Node{ID: "solo", Host: "localhost", Port: 8080})
+	if score := ring.BalanceScore(); math.Abs(score-1) > 1e-9 {
+		t.Errorf("Expected a single node to score 1, got %f", score)
+	}
+
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 9000 + i, Weight: i + 1})
+	}
+	coarse := ring.BalanceScore()
+	if coarse <= 0 || coarse >= 1 {
+		t.Fatalf("Expected a score between 0 and 1, got %f", coarse)
+	}
+
+	// More virtual nodes track the weights more closely
+	fine, _, err := ring.Rebuild(WithVirtualReplicas(1000))
+	if err != nil {
+		t.Fatalf("Failed to rebuild ring: %v", err)
+	}
+	if score := fine.BalanceScore(); score <= coarse {
+		t.Errorf("Expected more replicas to score above %f, got %f", coarse, score)
+	}
+}
+
+func TestMonitorBalance(t *testing.T) {
+	ring, _ := NewHashRing(2)
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+
+	if err := ring.MonitorBalance(context.Background(), BalanceMonitorConfig{Threshold: 0.5, OnDrift: func(BalanceStats) {}}); err == nil {
+		t.Error("Expected error for a threshold below 1")
+	}
+
+	drifts := make(chan BalanceStats, 4)
+	recoveries := make(chan BalanceStats, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- ring.MonitorBalance(ctx, BalanceMonitorConfig{
+			Interval:  time.Millisecond,
+			Threshold: 1.0001,
+			OnDrift:   func(s BalanceStats) { drifts <- s },
+			OnRecover: func(s BalanceStats) { recoveries <- s },
+		})
+	}()
+
+	// Two virtual nodes per node can't split the ring evenly
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8081})
+	select {
+	case stats := <-drifts:
+		if stats.MaxSkew <= 1.0001 {
+			t.Errorf("Expected drift above the threshold, got %+v", stats)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for drift")
+	}
+
+	ring.RemoveNode("node1")
+	select {
+	case <-recoveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for recovery")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("Expected a single drift callback, got %d more", len(drifts))
+	}
+}
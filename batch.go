@@ -0,0 +1,116 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyError records the failure of one key in a batch or replicated operation,
+// or of one node in a broadcast, where Key is empty
+type KeyError struct {
+	Key    string
+	NodeID string // Node involved in the failure, if any
+	Err    error
+}
+
+func (e *KeyError) Error() string {
+	if e.Key == "" && e.NodeID != "" {
+		return fmt.Sprintf("node %s: %v", e.NodeID, e.Err)
+	}
+	if e.NodeID != "" {
+		return fmt.Sprintf("key %q on node %s: %v", e.Key, e.NodeID, e.Err)
+	}
+	return fmt.Sprintf("key %q: %v", e.Key, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError is returned alongside partial results by operations that keep
+// going when some keys or nodes fail. errors.Is and errors.As look through
+// every failure.
+type MultiError struct {
+	Failures []*KeyError
+}
+
+func (m *MultiError) Error() string {
+	switch len(m.Failures) {
+	case 0:
+		return "no failures"
+	case 1:
+		return m.Failures[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d failures: %v", len(m.Failures), m.Failures[0])
+	if len(m.Failures) > 2 {
+		fmt.Fprintf(&b, " (and %d more)", len(m.Failures)-1)
+	} else {
+		fmt.Fprintf(&b, "; %v", m.Failures[1])
+	}
+	return b.String()
+}
+
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, len(m.Failures))
+	for i, failure := range m.Failures {
+		errs[i] = failure
+	}
+	return errs
+}
+
+// add records a failure
+func (m *MultiError) add(key, nodeID string, err error) {
+	m.Failures = append(m.Failures, &KeyError{Key: key, NodeID: nodeID, Err: err})
+}
+
+// errOrNil returns m as an error when it holds failures
+func (m *MultiError) errOrNil() error {
+	if len(m.Failures) == 0 {
+		return nil
+	}
+	return m
+}
+
+// GetNodeBatch looks up many keys under a single lock acquisition. Keys that
+// cannot be routed are reported in a *MultiError while the others are still
+// returned; an empty ring fails the whole batch with ErrEmptyRing unless it
+// has a fallback node.
+func (hr *HashRing) GetNodeBatch(keys []string) (map[string]*Node, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	empty := len(hr.virtualNodes) == 0
+	if empty && hr.fallback == nil {
+		return nil, ErrEmptyRing
+	}
+
+	nodes := make(map[string]*Node, len(keys))
+	var failures MultiError
+	for _, key := range keys {
+		if key == "" {
+			if node, handled, err := hr.routeEmptyKey(); handled {
+				if err != nil {
+					failures.add(key, "", err)
+				} else {
+					nodes[key] = node
+				}
+				continue
+			}
+		}
+
+		if empty {
+			nodes[key] = hr.fallback
+			continue
+		}
+
+		node := hr.virtualNodes[hr.search(hr.keyHashLocked(key))].Node
+		if hr.sampler != nil {
+			hr.sampler.record(node.ID, key)
+		}
+		nodes[key] = node
+	}
+
+	return nodes, failures.errOrNil()
+}
@@ -0,0 +1,71 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGetNodeBatch(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if _, err := ring.GetNodeBatch([]string{"a"}); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	keys := []string{"user:1", "user:2", "user:3"}
+	nodes, err := ring.GetNodeBatch(keys)
+	if err != nil {
+		t.Fatalf("Failed to look up batch: %v", err)
+	}
+	for _, key := range keys {
+		expected, _ := ring.GetNode(key)
+		if nodes[key] != expected {
+			t.Errorf("Key %q: expected %s, got %v", key, expected.ID, nodes[key])
+		}
+	}
+
+	// Failed keys don't hide the successes
+	nodes, err = ring.GetNodeBatch([]string{"user:1", "", "user:2"})
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Failures) != 1 || multi.Failures[0].Key != "" {
+		t.Fatalf("Expected one failure for the empty key, got %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Errorf("Expected 2 successful lookups, got %d", len(nodes))
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	sentinel := errors.New("node unreachable")
+	var m MultiError
+	if m.errOrNil() != nil {
+		t.Error("Expected nil without failures")
+	}
+
+	m.add("a", "node1", sentinel)
+	if m.Error() != `key "a" on node node1: node unreachable` {
+		t.Errorf("Unexpected message %q", m.Error())
+	}
+
+	m.add("b", "", ErrEmptyRing)
+	m.add("c", "", ErrEmptyRing)
+	err := m.errOrNil()
+	if !errors.Is(err, sentinel) || !errors.Is(err, ErrEmptyRing) {
+		t.Error("Expected errors.Is to see every failure")
+	}
+	var keyErr *KeyError
+	if !errors.As(err, &keyErr) || keyErr.Key != "a" {
+		t.Errorf("Expected errors.As to find the first KeyError, got %v", keyErr)
+	}
+	if !strings.HasPrefix(err.Error(), "3 failures: ") || !strings.HasSuffix(err.Error(), "(and 2 more)") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
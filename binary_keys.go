@@ -0,0 +1,73 @@
+package consistenthashing
+
+import "encoding/binary"
+
+// GetNodeUint64 returns the node responsible for an integer key. The key is
+// hashed as its 8-byte big-endian encoding, so no string formatting happens on
+// the lookup path. Fast-path lookups are not recorded by key sampling.
+func (hr *HashRing) GetNodeUint64(key uint64) (*Node, error) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], key)
+
+	return hr.getNodeByHash(hr.hashBytes(buf[:]))
+}
+
+// GetNodeUUID returns the node responsible for a UUID key, hashed as its raw
+// 16 bytes. Fast-path lookups are not recorded by key sampling.
+func (hr *HashRing) GetNodeUUID(key [16]byte) (*Node, error) {
+	return hr.getNodeByHash(hr.hashBytes(key[:]))
+}
+
+// GetNodeBytes returns the node responsible for a key held as bytes, routing
+// it exactly as GetNode(string(key)) would. The key is hashed in place, so
+// lookups don't allocate unless hooks, key sampling or affinity groups need
+// it as a string.
+func (hr *HashRing) GetNodeBytes(key []byte) (*Node, error) {
+	if hr.hooks != nil {
+		return hr.GetNode(string(key))
+	}
+
+	if len(key) == 0 {
+		if node, handled, err := hr.routeEmptyKey(); handled {
+			return node, err
+		}
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		if hr.fallback != nil {
+			return hr.fallback, nil
+		}
+		return nil, ErrEmptyRing
+	}
+
+	var hash uint64
+	if len(hr.affinity) > 0 {
+		hash = hr.keyHashLocked(string(key))
+	} else {
+		hash = hr.hashBytes(key)
+	}
+	node := hr.virtualNodes[hr.search(hash)].Node
+	if hr.sampler != nil {
+		hr.sampler.record(node.ID, string(key))
+	}
+
+	return node, nil
+}
+
+// getNodeByHash returns the node responsible for an already computed hash
+func (hr *HashRing) getNodeByHash(hash uint64) (*Node, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		if hr.fallback != nil {
+			return hr.fallback, nil
+		}
+		return nil, ErrEmptyRing
+	}
+
+	return hr.virtualNodes[hr.search(hash)].Node, nil
+}
@@ -0,0 +1,182 @@
+package consistenthashing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"testing"
+)
+
+func TestFNVHasherMatchesStdlib(t *testing.T) {
+	hasher := &FNVHasher{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key_%d", i)
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		if hasher.Hash(key) != h.Sum64() {
+			t.Fatalf("FNV hash of %s differs from hash/fnv", key)
+		}
+		if hasher.HashBytes([]byte(key)) != h.Sum64() {
+			t.Fatalf("FNV byte hash of %s differs from hash/fnv", key)
+		}
+	}
+}
+
+func TestGetNodeUint64AndUUID(t *testing.T) {
+	for _, hasher := range []HashFunction{&FNVHasher{}, &SHA256Hasher{}} {
+		ring, err := NewHashRing(10, WithHashFunction(hasher))
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+
+		if _, err := ring.GetNodeUint64(1); err != ErrEmptyRing {
+			t.Errorf("Expected ErrEmptyRing, got %v", err)
+		}
+
+		ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+		ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+		ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 8082})
+
+		// The fast paths agree with looking up the encoded key as a string
+		for i := uint64(0); i < 100; i++ {
+			var buf [8]byte
+			binary.BigEndian.PutUint64(buf[:], i)
+
+			fast, err := ring.GetNodeUint64(i)
+			if err != nil {
+				t.Fatalf("Failed to get node: %v", err)
+			}
+			slow, _ := ring.GetNode(string(buf[:]))
+			if fast != slow {
+				t.Errorf("GetNodeUint64(%d) returned %s, expected %s", i, fast.ID, slow.ID)
+			}
+
+			var uuid [16]byte
+			copy(uuid[8:], buf[:])
+			fast, _ = ring.GetNodeUUID(uuid)
+			slow, _ = ring.GetNode(string(uuid[:]))
+			if fast != slow {
+				t.Errorf("GetNodeUUID returned %s, expected %s", fast.ID, slow.ID)
+			}
+		}
+	}
+}
+
+func TestGetNodeUint64DoesNotAllocate(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		ring.GetNodeUint64(42)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f", allocs)
+	}
+}
+
+func TestGetNodeBytes(t *testing.T) {
+	hashers := []HashFunction{
+		&FNVHasher{}, &XXHasher{}, &Murmur3Hasher{}, &CRC32Hasher{},
+		&SHA256Hasher{}, &MD5Hasher{}, &SHA1Hasher{}, &SipHasher{Key: [16]byte{1}},
+		&CRC32Hasher{Seed: 7}, &SHA256Hasher{Seed: 7}, &MD5Hasher{Seed: 7}, &SHA1Hasher{Seed: 7},
+	}
+	for _, hasher := range hashers {
+		ring, err := NewHashRing(10, WithHashFunction(hasher), WithKeySampling(10))
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+
+		if _, err := ring.GetNodeBytes([]byte("key")); err != ErrEmptyRing {
+			t.Errorf("Expected ErrEmptyRing, got %v", err)
+		}
+		if _, err := ring.GetNodeBytes(nil); err != ErrEmptyKey {
+			t.Errorf("Expected ErrEmptyKey, got %v", err)
+		}
+
+		ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+		ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+		ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 8082})
+		if err := ring.SetAffinityGroup("tenant", "tenant:"); err != nil {
+			t.Fatalf("Failed to set affinity group: %v", err)
+		}
+
+		// Byte keys route like their string form, affinity groups included
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("user:%d", i)
+			if i%2 == 0 {
+				key = fmt.Sprintf("tenant:%d", i)
+			}
+			fast, err := ring.GetNodeBytes([]byte(key))
+			if err != nil {
+				t.Fatalf("Failed to get node: %v", err)
+			}
+			slow, _ := ring.GetNode(key)
+			if fast != slow {
+				t.Errorf("GetNodeBytes(%q) returned %s, expected %s", key, fast.ID, slow.ID)
+			}
+		}
+
+		samples, _ := ring.SampledKeys()
+		sampled := 0
+		for _, keys := range samples {
+			sampled += len(keys)
+		}
+		if sampled == 0 {
+			t.Error("Expected byte key lookups to be sampled")
+		}
+	}
+}
+
+func TestGetNodeBytesDoesNotAllocate(t *testing.T) {
+	key := []byte("user:12345")
+	hashers := []HashFunction{
+		&FNVHasher{}, &XXHasher{}, &Murmur3Hasher{}, &CRC32Hasher{}, &SipHasher{Key: [16]byte{1}},
+		&SHA256Hasher{}, &MD5Hasher{}, &SHA1Hasher{}, &SHA256Hasher{Seed: 7},
+	}
+	for _, hasher := range hashers {
+		ring, _ := NewHashRing(10, WithHashFunction(hasher))
+		ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+
+		allocs := testing.AllocsPerRun(100, func() {
+			ring.GetNodeBytes(key)
+		})
+		if allocs != 0 {
+			t.Errorf("%s: expected no allocations, got %.1f", hasherName(hasher), allocs)
+		}
+	}
+}
+
+func BenchmarkGetNodeUint64(b *testing.B) {
+	ring, _ := NewHashRing(100)
+
+	// Add nodes
+	for i := 0; i < 10; i++ {
+		node := &Node{
+			ID:   fmt.Sprintf("node%d", i),
+			Host: "localhost",
+			Port: 8080 + i,
+		}
+		ring.AddNode(node)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ring.GetNodeUint64(uint64(i % 1000))
+	}
+}
+
+func BenchmarkGetNodeBytes(b *testing.B) {
+	ring, _ := NewHashRing(100)
+	for i := 0; i < 10; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	keys := make([][]byte, 1000)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("key_%d", i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ring.GetNodeBytes(keys[i%len(keys)])
+	}
+}
@@ -0,0 +1,190 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultSpilloverCandidates is the number of nodes SpillLeastLoaded chooses
+// from when WithSpilloverCandidates isn't given
+const DefaultSpilloverCandidates = 2
+
+// ErrNodesAtBound is returned by GetNodeBounded when no acceptable node is
+// under its load bound
+var ErrNodesAtBound = errors.New("no node is under its load bound")
+
+// SpilloverPolicy decides where GetNodeBounded sends a key whose owner is at
+// its load bound
+type SpilloverPolicy int
+
+const (
+	// SpillNext sends the key to the next node clockwise that is under its
+	// bound. Spilled keys land where they would after the owner is removed,
+	// which keeps assignments the most stable. This is the default.
+	SpillNext SpilloverPolicy = iota
+	// SpillLeastLoaded sends the key to the least loaded, relative to its
+	// bound, of the next nodes clockwise, trading stability for balance.
+	// WithSpilloverCandidates sets how many nodes are compared; if all of
+	// them are at their bound the walk continues as with SpillNext.
+	SpillLeastLoaded
+	// SpillReject fails with ErrNodesAtBound instead of spilling, for
+	// workloads where a key must only ever be served by its owner
+	SpillReject
+)
+
+// WithBoundedLoads bounds each node's load at factor times its weighted share
+// of the total tracked load, rounded up, as in consistent hashing with
+// bounded loads. A factor of 1.25 lets a node carry 25% more than its share
+// before GetNodeBounded spills its keys. Capacities set with SetNodeCapacity
+// still apply, whichever bound is lower. Load tracking must be enabled with
+// WithLoadTracking.
+func WithBoundedLoads(factor float64) Option {
+	return func(hr *HashRing) {
+		hr.loadBound = factor
+	}
+}
+
+// WithSpilloverPolicy sets where GetNodeBounded sends the keys of nodes at
+// their load bound
+func WithSpilloverPolicy(policy SpilloverPolicy) Option {
+	return func(hr *HashRing) {
+		hr.spillover = policy
+	}
+}
+
+// WithSpilloverCandidates sets how many nodes clockwise of a full owner
+// SpillLeastLoaded compares
+func WithSpilloverCandidates(k int) Option {
+	return func(hr *HashRing) {
+		hr.spillCandidates = k
+	}
+}
+
+// validateBoundedLoads checks the settings made by the bounded load options
+func (hr *HashRing) validateBoundedLoads() error {
+	if hr.loadBound != 0 && !(hr.loadBound >= 1) {
+		return fmt.Errorf("load bound factor must be at least 1, got %g", hr.loadBound)
+	}
+	if hr.spillCandidates < 0 {
+		return ErrInvalidCount
+	}
+	switch hr.spillover {
+	case SpillNext, SpillLeastLoaded, SpillReject:
+		return nil
+	default:
+		return fmt.Errorf("invalid spillover policy %d", int(hr.spillover))
+	}
+}
+
+// GetNodeBounded returns the node for key like GetNode, unless that node is
+// at its load bound, in which case the ring's SpilloverPolicy picks another.
+// Bounds come from WithBoundedLoads and SetNodeCapacity; a node with neither
+// is never full. The lookup doesn't add load itself, so callers record the
+// work they send with RecordLoad. Computing the weighted bound visits every
+// member, so lookups are O(n) with WithBoundedLoads.
+func (hr *HashRing) GetNodeBounded(key string) (*Node, error) {
+	if hr.loads == nil {
+		return nil, ErrLoadTrackingDisabled
+	}
+	if key == "" {
+		if node, handled, err := hr.routeEmptyKey(); handled {
+			return node, err
+		}
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		if hr.fallback != nil {
+			return hr.fallback, nil
+		}
+		return nil, ErrEmptyRing
+	}
+
+	hr.loads.mu.Lock()
+	defer hr.loads.mu.Unlock()
+
+	// Total load and weight for the weighted bound
+	var total float64
+	var weights int
+	if hr.loadBound > 0 {
+		for id, node := range hr.nodes {
+			total += hr.loads.valueLocked(id)
+			weights += hr.weightLocked(node)
+		}
+	}
+	// usage returns the node's load relative to its bound; 1 or more is full
+	usage := func(node *Node) float64 {
+		bound := hr.loads.capacity[node.ID]
+		if hr.loadBound > 0 {
+			share := float64(hr.weightLocked(node)) / float64(weights)
+			weighted := int64(math.Ceil(hr.loadBound * (total + 1) * share))
+			if bound == 0 || weighted < bound {
+				bound = weighted
+			}
+		}
+		if bound == 0 {
+			return 0
+		}
+		return hr.loads.valueLocked(node.ID) / float64(bound)
+	}
+
+	idx := hr.search(hr.keyHashLocked(key))
+	owner := hr.virtualNodes[idx].Node
+	node, ok := owner, usage(owner) < 1
+	if !ok && hr.spillover != SpillReject {
+		node, ok = hr.spillLocked(idx, owner, usage)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNodesAtBound, owner.ID)
+	}
+
+	if hr.sampler != nil {
+		hr.sampler.record(node.ID, key)
+	}
+	return node, nil
+}
+
+// spillLocked walks clockwise from a full owner at idx and picks a node under
+// its bound according to the spillover policy. Each node is checked once.
+func (hr *HashRing) spillLocked(idx int, owner *Node, usage func(*Node) float64) (*Node, bool) {
+	candidates := 0
+	if hr.spillover == SpillLeastLoaded {
+		candidates = hr.spillCandidates
+		if candidates == 0 {
+			candidates = DefaultSpilloverCandidates
+		}
+	}
+
+	checked := map[*Node]bool{owner: true}
+	var best *Node
+	bestUsage := 1.0
+	for i := 1; i < len(hr.virtualNodes) && len(checked) < len(hr.nodes); i++ {
+		node := hr.virtualNodes[(idx+i)%len(hr.virtualNodes)].Node
+		if checked[node] {
+			continue
+		}
+		checked[node] = true
+
+		u := usage(node)
+		if candidates == 0 {
+			if u < 1 {
+				return node, true
+			}
+			continue
+		}
+		if u < bestUsage {
+			best, bestUsage = node, u
+		}
+		// All candidates compared; keep walking only if all were full
+		if len(checked)-1 == candidates {
+			if best != nil {
+				return best, true
+			}
+			candidates = 0
+		}
+	}
+	return best, best != nil
+}
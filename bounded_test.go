@@ -0,0 +1,130 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// newBoundedRing returns a ring of count equal nodes with load tracking
+func newBoundedRing(t *testing.T, count int, opts ...Option) *HashRing {
+	t.Helper()
+	opts = append([]Option{WithHashFunction(&SHA256Hasher{}), WithLoadTracking(nil)}, opts...)
+	ring, err := NewHashRing(50, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < count; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	return ring
+}
+
+func TestGetNodeBoundedSpillover(t *testing.T) {
+	key := "user:42"
+
+	ring := newBoundedRing(t, 4)
+	order, _ := ring.GetNodes(key, 4)
+	if node, _ := ring.GetNodeBounded(key); node.ID != order[0].ID {
+		t.Errorf("Expected the owner %s while under its bound, got %s", order[0].ID, node.ID)
+	}
+
+	// A full owner spills to the next node clockwise
+	ring.SetNodeCapacity(order[0].ID, 1)
+	ring.RecordLoad(order[0].ID, 1)
+	ring.SetNodeCapacity(order[1].ID, 10)
+	ring.RecordLoad(order[1].ID, 5)
+	if node, _ := ring.GetNodeBounded(key); node.ID != order[1].ID {
+		t.Errorf("Expected spillover to %s, got %s", order[1].ID, node.ID)
+	}
+
+	// The least loaded of the next two takes it instead
+	ring = newBoundedRing(t, 4, WithSpilloverPolicy(SpillLeastLoaded))
+	ring.SetNodeCapacity(order[0].ID, 1)
+	ring.RecordLoad(order[0].ID, 1)
+	ring.SetNodeCapacity(order[1].ID, 10)
+	ring.RecordLoad(order[1].ID, 5)
+	ring.SetNodeCapacity(order[2].ID, 10)
+	ring.RecordLoad(order[2].ID, 1)
+	if node, _ := ring.GetNodeBounded(key); node.ID != order[2].ID {
+		t.Errorf("Expected the least loaded %s, got %s", order[2].ID, node.ID)
+	}
+
+	// With both candidates full the walk continues
+	ring.RecordLoad(order[1].ID, 5)
+	ring.RecordLoad(order[2].ID, 9)
+	if node, _ := ring.GetNodeBounded(key); node.ID != order[3].ID {
+		t.Errorf("Expected %s past the full candidates, got %s", order[3].ID, node.ID)
+	}
+	ring.SetNodeCapacity(order[3].ID, 1)
+	ring.RecordLoad(order[3].ID, 1)
+	if _, err := ring.GetNodeBounded(key); !errors.Is(err, ErrNodesAtBound) {
+		t.Errorf("Expected ErrNodesAtBound with every node full, got %v", err)
+	}
+
+	// Rejecting never spills
+	ring = newBoundedRing(t, 4, WithSpilloverPolicy(SpillReject))
+	ring.SetNodeCapacity(order[0].ID, 1)
+	ring.RecordLoad(order[0].ID, 1)
+	if _, err := ring.GetNodeBounded(key); !errors.Is(err, ErrNodesAtBound) {
+		t.Errorf("Expected ErrNodesAtBound, got %v", err)
+	}
+}
+
+func TestGetNodeBoundedWeighted(t *testing.T) {
+	ring := newBoundedRing(t, 4, WithBoundedLoads(1.25))
+	ring.AddNode(&Node{ID: "big", Host: "localhost", Port: 9000, Weight: 4})
+
+	// Even a single hot key is spread once its owner reaches the bound
+	keys := 1600
+	for i := 0; i < keys; i++ {
+		key := "hot"
+		if i%2 == 0 {
+			key = fmt.Sprintf("key%d", i)
+		}
+		node, err := ring.GetNodeBounded(key)
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		ring.RecordLoad(node.ID, 1)
+	}
+
+	for _, node := range ring.GetAllNodes() {
+		load, _ := ring.NodeLoad(node.ID)
+		bound := int64(1.25*float64(keys)*float64(nodeWeight(node))/8) + 1
+		if load > bound {
+			t.Errorf("Node %s: expected load at most %d, got %d", node.ID, bound, load)
+		}
+	}
+}
+
+func TestGetNodeBoundedOptions(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+	if _, err := ring.GetNodeBounded("key"); !errors.Is(err, ErrLoadTrackingDisabled) {
+		t.Errorf("Expected ErrLoadTrackingDisabled, got %v", err)
+	}
+
+	for _, opt := range []Option{
+		WithBoundedLoads(0.5),
+		WithSpilloverPolicy(SpilloverPolicy(42)),
+		WithSpilloverCandidates(-1),
+	} {
+		if _, err := NewHashRing(10, WithLoadTracking(nil), opt); err == nil {
+			t.Error("Expected an invalid bounded load setting to be rejected")
+		}
+	}
+
+	// The settings carry over to rebuilt rings
+	ring = newBoundedRing(t, 2, WithSpilloverPolicy(SpillReject))
+	rebuilt, _, err := ring.Rebuild(WithLoadTracking(nil))
+	if err != nil {
+		t.Fatalf("Failed to rebuild ring: %v", err)
+	}
+	owner, _ := rebuilt.GetNode("key")
+	rebuilt.SetNodeCapacity(owner.ID, 1)
+	rebuilt.RecordLoad(owner.ID, 1)
+	if _, err := rebuilt.GetNodeBounded("key"); !errors.Is(err, ErrNodesAtBound) {
+		t.Errorf("Expected the rebuilt ring to reject, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package consistenthashing
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// ForAllNodes calls fn on every member, with at most parallelism calls in
+// flight, for operations such as flushing or invalidating everywhere. The
+// members are read once when the call starts. A non-positive parallelism
+// runs every call at once.
+//
+// Failed nodes are reported in a *MultiError, sorted by node ID. Once ctx is
+// done no further calls start, and the nodes never reached fail with the
+// context's error.
+func (hr *HashRing) ForAllNodes(ctx context.Context, parallelism int, fn func(ctx context.Context, node *Node) error) error {
+	nodes := hr.GetAllNodes()
+	if parallelism <= 0 || parallelism > len(nodes) {
+		parallelism = len(nodes)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures MultiError
+	)
+	fail := func(node *Node, err error) {
+		mu.Lock()
+		failures.add("", node.ID, err)
+		mu.Unlock()
+	}
+
+	sem := make(chan struct{}, parallelism)
+	for _, node := range nodes {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if err := ctx.Err(); err != nil {
+			fail(node, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(node *Node) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, node); err != nil {
+				fail(node, err)
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	sort.Slice(failures.Failures, func(i, j int) bool {
+		return failures.Failures[i].NodeID < failures.Failures[j].NodeID
+	})
+	return failures.errOrNil()
+}
@@ -0,0 +1,95 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForAllNodes(t *testing.T) {
+	hr, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	var (
+		mu       sync.Mutex
+		visited  = make(map[string]bool)
+		inFlight atomic.Int32
+		peak     atomic.Int32
+	)
+	flushErr := errors.New("flush failed")
+	err = hr.ForAllNodes(context.Background(), 3, func(ctx context.Context, node *Node) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		mu.Lock()
+		visited[node.ID] = true
+		mu.Unlock()
+		if node.ID == "node7" || node.ID == "node2" {
+			return flushErr
+		}
+		return nil
+	})
+
+	if len(visited) != 10 {
+		t.Errorf("Expected every node to be visited, got %d", len(visited))
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("Expected at most 3 calls in flight, saw %d", p)
+	}
+
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %v", err)
+	}
+	if multi.Failures[0].NodeID != "node2" || multi.Failures[1].NodeID != "node7" {
+		t.Errorf("Expected failures sorted by node ID, got %v", multi)
+	}
+	if !errors.Is(err, flushErr) {
+		t.Error("Expected the failures to wrap the operation's error")
+	}
+	if got := multi.Failures[0].Error(); got != "node node2: flush failed" {
+		t.Errorf("Unexpected failure message %q", got)
+	}
+}
+
+func TestForAllNodesCanceled(t *testing.T) {
+	hr, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	err = hr.ForAllNodes(ctx, 1, func(ctx context.Context, node *Node) error {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	var multi *MultiError
+	errors.As(err, &multi)
+	if got := int(calls.Load()) + len(multi.Failures); got != 5 {
+		t.Errorf("Expected every node to be called or failed, got %d", got)
+	}
+}
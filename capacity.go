@@ -0,0 +1,130 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+)
+
+// ErrUnknownClass is returned for nodes that reference a capacity class the
+// ring doesn't have
+var ErrUnknownClass = errors.New("unknown capacity class")
+
+// WithCapacityClasses registers named capacity classes, such as
+// {"small": 1, "large": 4}. A node whose Class names a registered class takes
+// its weight from the class instead of its Weight field, so a fleet can be
+// re-tuned per class with SetClassWeight.
+func WithCapacityClasses(classes map[string]int) Option {
+	return func(hr *HashRing) {
+		hr.classes = maps.Clone(classes)
+	}
+}
+
+// validateClasses checks class weights the way Node.Validate checks weights
+func validateClasses(classes map[string]int) error {
+	for name, weight := range classes {
+		if name == "" {
+			return errors.New("capacity class name cannot be empty")
+		}
+		if weight <= 0 || weight > MaxNodeWeight {
+			return fmt.Errorf("class %s: %w", name, ErrInvalidNodeWeight)
+		}
+	}
+	return nil
+}
+
+// weightLocked returns a node's effective weight, taking the weight of its
+// capacity class when it has one
+func (hr *HashRing) weightLocked(node *Node) int {
+	if weight, ok := hr.classes[node.Class]; ok && node.Class != "" {
+		return weight
+	}
+	return nodeWeight(node)
+}
+
+// ClassWeight returns the weight of a capacity class
+func (hr *HashRing) ClassWeight(class string) (int, bool) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	weight, ok := hr.classes[class]
+	return weight, ok
+}
+
+// Classes returns the registered capacity classes and their weights
+func (hr *HashRing) Classes() map[string]int {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return maps.Clone(hr.classes)
+}
+
+// SetClassWeight registers a capacity class or changes its weight. Every
+// member of the class is resized in a single generation, moving only the keys
+// of the replicas added or dropped, as with UpdateNodeWeight. Updated nodes
+// are reported as EventNodeUpdated.
+func (hr *HashRing) SetClassWeight(class string, weight int) error {
+	if err := validateClasses(map[string]int{class: weight}); err != nil {
+		return err
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	old, exists := hr.classes[class]
+	if exists && old == weight {
+		return nil
+	}
+
+	// Resize in ID order so events are deterministic
+	var members []*Node
+	for _, node := range hr.nodes {
+		if node.Class == class {
+			members = append(members, node)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+
+	oldCounts := make([]int, len(members))
+	for i, node := range members {
+		oldCounts[i], _ = hr.virtualCount(node)
+	}
+
+	if hr.classes == nil {
+		hr.classes = make(map[string]int)
+	}
+	hr.classes[class] = weight
+
+	newCounts := make([]int, len(members))
+	for i, node := range members {
+		count, err := hr.virtualCount(node)
+		if err != nil {
+			if exists {
+				hr.classes[class] = old
+			} else {
+				delete(hr.classes, class)
+			}
+			return err
+		}
+		newCounts[i] = count
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	// The nodes themselves are unchanged, only their replica counts
+	if !hr.rescaleLocked() {
+		for i, node := range members {
+			hr.resizeNodeLocked(node, node, oldCounts[i], newCounts[i])
+		}
+	}
+	hr.advanceGenerationLocked()
+	for _, node := range members {
+		hr.emitLocked(EventNodeUpdated, node)
+	}
+
+	return nil
+}
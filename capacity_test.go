@@ -0,0 +1,131 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCapacityClasses(t *testing.T) {
+	hr, err := NewHashRing(10, WithCapacityClasses(map[string]int{"small": 1, "large": 4}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	// The class weight overrides the node's own weight
+	hr.AddNode(&Node{ID: "small1", Host: "localhost", Port: 8080, Class: "small", Weight: 9})
+	hr.AddNode(&Node{ID: "large1", Host: "localhost", Port: 8081, Class: "large"})
+	hr.AddNode(&Node{ID: "plain", Host: "localhost", Port: 8082, Weight: 2})
+	if got := hr.VirtualSize(); got != 10+40+20 {
+		t.Errorf("Expected 70 virtual nodes, got %d", got)
+	}
+
+	err = hr.AddNode(&Node{ID: "huge1", Host: "localhost", Port: 8083, Class: "huge"})
+	if !errors.Is(err, ErrUnknownClass) {
+		t.Errorf("Expected ErrUnknownClass, got %v", err)
+	}
+	if _, err := hr.SetMembers([]*Node{{ID: "huge1", Host: "localhost", Port: 8083, Class: "huge"}}); !errors.Is(err, ErrUnknownClass) {
+		t.Errorf("Expected ErrUnknownClass from SetMembers, got %v", err)
+	}
+	if err := hr.UpdateNodeWeight("large1", 2); err == nil {
+		t.Error("Expected UpdateNodeWeight to reject a node in a capacity class")
+	}
+	if err := hr.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+}
+
+func TestSetClassWeight(t *testing.T) {
+	hr, err := NewHashRing(20, WithCapacityClasses(map[string]int{"large": 4}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("large%d", i), Host: "localhost", Port: 8080 + i, Class: "large"})
+	}
+	hr.AddNode(&Node{ID: "plain", Host: "localhost", Port: 9000})
+
+	keys := make([]string, 1000)
+	before := make(map[string]string, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+		node, _ := hr.GetNode(keys[i])
+		before[keys[i]] = node.ID
+	}
+
+	events, cancel := hr.Watch(8)
+	defer cancel()
+	generation := hr.Generation()
+	if err := hr.SetClassWeight("large", 2); err != nil {
+		t.Fatalf("Failed to set class weight: %v", err)
+	}
+	if got := hr.Generation(); got != generation+1 {
+		t.Errorf("Expected a single generation for the class change, got %d -> %d", generation, got)
+	}
+	if got := hr.VirtualSize(); got != 3*40+20 {
+		t.Errorf("Expected 140 virtual nodes, got %d", got)
+	}
+	for i := 0; i < 3; i++ {
+		event := <-events
+		if event.Type != EventNodeUpdated || event.Node.ID != fmt.Sprintf("large%d", i) {
+			t.Errorf("Expected update for large%d, got %v %v", i, event.Type, event.Node)
+		}
+	}
+	if err := hr.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+
+	// Shrinking the class never takes keys from nodes outside it
+	for _, key := range keys {
+		node, _ := hr.GetNode(key)
+		if before[key] == "plain" && node.ID != "plain" {
+			t.Errorf("Key %s moved from plain to %s", key, node.ID)
+		}
+	}
+
+	if weight, ok := hr.ClassWeight("large"); !ok || weight != 2 {
+		t.Errorf("Expected class weight 2, got %d (%v)", weight, ok)
+	}
+	if err := hr.SetClassWeight("large", 0); !errors.Is(err, ErrInvalidNodeWeight) {
+		t.Errorf("Expected ErrInvalidNodeWeight, got %v", err)
+	}
+
+	// New classes can be registered on a live ring
+	if err := hr.SetClassWeight("small", 1); err != nil {
+		t.Fatalf("Failed to register class: %v", err)
+	}
+	if err := hr.AddNode(&Node{ID: "small1", Host: "localhost", Port: 9001, Class: "small"}); err != nil {
+		t.Errorf("Failed to add node in new class: %v", err)
+	}
+}
+
+func TestCapacityClassesInvalid(t *testing.T) {
+	if _, err := NewHashRing(10, WithCapacityClasses(map[string]int{"bad": -1})); !errors.Is(err, ErrInvalidNodeWeight) {
+		t.Errorf("Expected ErrInvalidNodeWeight, got %v", err)
+	}
+}
+
+func TestCapacityClassesSnapshot(t *testing.T) {
+	hr, err := NewHashRing(10, WithCapacityClasses(map[string]int{"large": 4}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	hr.AddNode(&Node{ID: "large1", Host: "localhost", Port: 8080, Class: "large"})
+
+	var buf bytes.Buffer
+	if err := hr.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	snapshot, err := ReadSnapshot(&buf, nil)
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	restored, err := RestoreHashRing(snapshot)
+	if err != nil {
+		t.Fatalf("Failed to restore ring: %v", err)
+	}
+	if got := restored.VirtualSize(); got != 40 {
+		t.Errorf("Expected 40 virtual nodes after restore, got %d", got)
+	}
+}
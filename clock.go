@@ -0,0 +1,45 @@
+package consistenthashing
+
+import "time"
+
+// Clock is the source of time for the ring's time-dependent behavior, such
+// as history timestamps, hook durations and periodic monitors
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks from a Clock
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// WithClock sets the clock used for time-dependent behavior. Tests can pass
+// a fake clock, such as testutil.FakeClock, to control time explicitly.
+// Defaults to the system clock.
+func WithClock(clock Clock) Option {
+	return func(hr *HashRing) {
+		if clock != nil {
+			hr.clock = clock
+		}
+	}
+}
+
+// systemClock is the Clock backed by package time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// systemTicker adapts a time.Ticker to Ticker
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+
+func (t systemTicker) Stop() { t.t.Stop() }
@@ -0,0 +1,103 @@
+package consistenthashing
+
+import (
+	"testing"
+	"time"
+)
+
+// stepClock is a Clock that only moves when told to, or by step after each
+// reading
+type stepClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func (c *stepClock) NewTicker(d time.Duration) Ticker {
+	return systemClock{}.NewTicker(d)
+}
+
+func TestWithClockHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &stepClock{now: start}
+	hr, err := NewHashRing(10, WithClock(clock), WithHistory(4))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	clock.now = start.Add(time.Minute)
+	if err := hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	clock.now = start.Add(2 * time.Minute)
+	if err := hr.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+
+	tests := []struct {
+		at   time.Time
+		want uint64
+	}{
+		{start, 0},
+		{start.Add(90 * time.Second), 1},
+		{start.Add(2 * time.Minute), 2},
+		{start.Add(time.Hour), 2},
+	}
+	for _, tt := range tests {
+		got, err := hr.GenerationAt(tt.at)
+		if err != nil {
+			t.Fatalf("GenerationAt(%v) failed: %v", tt.at, err)
+		}
+		if got != tt.want {
+			t.Errorf("GenerationAt(%v) = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+
+	view, _ := hr.AtGeneration(1)
+	if !view.Started().Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected generation 1 to start at the fake time, got %v", view.Started())
+	}
+}
+
+func TestWithClockHooks(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), step: 5 * time.Millisecond}
+	var durs []time.Duration
+	after := func(op Op, key string, node *Node, err error, dur time.Duration) {
+		durs = append(durs, dur)
+	}
+
+	// Options may be given in any order
+	hr, err := NewHashRing(10, WithHooks(nil, after), WithClock(clock))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if err := hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	hr.GetNode("user:1")
+
+	// Each operation reads the clock once at the start and once at the end
+	for i, dur := range durs {
+		if dur != 5*time.Millisecond {
+			t.Errorf("Operation %d: expected 5ms from the fake clock, got %v", i, dur)
+		}
+	}
+	if len(durs) != 2 {
+		t.Errorf("Expected 2 hooked operations, got %d", len(durs))
+	}
+}
+
+func TestWithClockNil(t *testing.T) {
+	hr, err := NewHashRing(10, WithClock(nil))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if _, ok := hr.clock.(systemClock); !ok {
+		t.Errorf("Expected nil clock to keep the system clock, got %T", hr.clock)
+	}
+}
@@ -0,0 +1,129 @@
+// Command chash looks up keys on a ring defined by a JSON configuration file,
+// or serves lookups over HTTP so small deployments get a standalone consistent
+// hashing service.
+//
+// Usage:
+//
+//	chash lookup -config ring.json [-count N] key...
+//	chash serve -config ring.json [-addr :8080] [-watch 5s]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "lookup":
+		err = lookup(os.Args[2:])
+	case "serve":
+		err = serve(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: chash lookup -config ring.json [-count N] key...")
+	fmt.Fprintln(os.Stderr, "       chash serve -config ring.json [-addr :8080] [-watch 5s]")
+	os.Exit(2)
+}
+
+// loadRing builds the ring described by a configuration file
+func loadRing(path string) (*consistenthashing.HashRing, error) {
+	if path == "" {
+		return nil, errors.New("-config is required")
+	}
+	config, err := consistenthashing.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return consistenthashing.NewHashRingFromConfig(config)
+}
+
+// lookup prints the nodes responsible for each key
+func lookup(args []string) error {
+	flags := flag.NewFlagSet("lookup", flag.ExitOnError)
+	configPath := flags.String("config", "", "ring configuration file")
+	count := flags.Int("count", 1, "number of replicas to print per key")
+	flags.Parse(args)
+
+	ring, err := loadRing(*configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range flags.Args() {
+		nodes, err := ring.GetNodes(key, *count)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		fmt.Print(key)
+		for _, node := range nodes {
+			fmt.Printf("\t%s=%s", node.ID, node)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// serve runs the HTTP lookup service until interrupted
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := flags.String("config", "", "ring configuration file")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	watch := flags.Duration("watch", 5*time.Second, "how often to check the config for changes, 0 to disable")
+	flags.Parse(args)
+
+	ring, err := loadRing(*configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *watch > 0 {
+		go ring.WatchConfig(ctx, *configPath, *watch, func(report consistenthashing.ChurnReport, err error) {
+			if err != nil {
+				log.Printf("Config reload failed, keeping current members: %v", err)
+				return
+			}
+			if report.Changed() {
+				log.Printf("Config reloaded: %s", report)
+			}
+		})
+	}
+
+	server := &http.Server{Addr: *addr, Handler: newHandler(ring)}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Serving %d nodes on %s", ring.Size(), *addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
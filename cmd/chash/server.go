@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// nodeResponse is the JSON body of single-node lookups
+type nodeResponse struct {
+	Key  string                  `json:"key"`
+	Node *consistenthashing.Node `json:"node"`
+}
+
+// nodesResponse is the JSON body of replica lookups and the member list
+type nodesResponse struct {
+	Key   string                    `json:"key,omitempty"`
+	Nodes []*consistenthashing.Node `json:"nodes"`
+}
+
+// errorResponse is the JSON body of failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newHandler returns the HTTP API of the lookup service:
+//
+//	GET /v1/node?key=K           node responsible for K
+//	GET /v1/nodes?key=K&count=N  N replicas for K
+//	GET /v1/members              all nodes
+//	GET /v1/state                fingerprint and generation, for agreement checks
+//	GET /readyz                  200 once the ring has an active member, 503 before
+func newHandler(ring *consistenthashing.HashRing) http.Handler {
+	mux := http.NewServeMux()
+	ready := ring.ReadinessCheck(1)
+
+	mux.HandleFunc("GET /v1/node", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		node, err := ring.GetNode(key)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nodeResponse{Key: key, Node: node})
+	})
+
+	mux.HandleFunc("GET /v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		count := 1
+		if raw := r.URL.Query().Get("count"); raw != "" {
+			var err error
+			if count, err = strconv.Atoi(raw); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Error: "count must be an integer"})
+				return
+			}
+		}
+
+		nodes, err := ring.GetNodes(key, count)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, nodesResponse{Key: key, Nodes: nodes})
+	})
+
+	mux.HandleFunc("GET /v1/members", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, nodesResponse{Nodes: ring.GetAllNodes()})
+	})
+
+	mux.HandleFunc("GET /v1/state", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, ring.State())
+	})
+
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := ready(); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, struct{}{})
+	})
+
+	return mux
+}
+
+// writeError maps ring errors to HTTP status codes
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, consistenthashing.ErrEmptyRing) {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func newTestHandler(t *testing.T, nodes ...consistenthashing.Node) (*consistenthashing.HashRing, http.Handler) {
+	t.Helper()
+	ring, err := consistenthashing.NewHashRingFromConfig(consistenthashing.RingConfig{VirtualReplicas: 20, Nodes: nodes})
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	return ring, newHandler(ring)
+}
+
+func get(t *testing.T, handler http.Handler, url string, body interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if body != nil {
+		if err := json.NewDecoder(rec.Body).Decode(body); err != nil {
+			t.Fatalf("Failed to decode %s response: %v", url, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestHandler(t *testing.T) {
+	ring, handler := newTestHandler(t,
+		consistenthashing.Node{ID: "a", Host: "10.0.0.1", Port: 6379},
+		consistenthashing.Node{ID: "b", Host: "10.0.0.2", Port: 6379},
+		consistenthashing.Node{ID: "c", Host: "10.0.0.3", Port: 6379},
+	)
+
+	var single nodeResponse
+	if code := get(t, handler, "/v1/node?key=user:42", &single); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	expected, _ := ring.GetNode("user:42")
+	if single.Node.ID != expected.ID {
+		t.Errorf("Expected node %s, got %s", expected.ID, single.Node.ID)
+	}
+
+	var replicas nodesResponse
+	if code := get(t, handler, "/v1/nodes?key=user:42&count=2", &replicas); code != http.StatusOK || len(replicas.Nodes) != 2 {
+		t.Errorf("Expected 2 replicas, got %d (%d)", len(replicas.Nodes), code)
+	}
+
+	var members nodesResponse
+	if get(t, handler, "/v1/members", &members); len(members.Nodes) != 3 {
+		t.Errorf("Expected 3 members, got %d", len(members.Nodes))
+	}
+
+	var state consistenthashing.RingState
+	if get(t, handler, "/v1/state", &state); state.Fingerprint != ring.Fingerprint() {
+		t.Errorf("Expected fingerprint %d, got %d", ring.Fingerprint(), state.Fingerprint)
+	}
+
+	var failure errorResponse
+	if code := get(t, handler, "/v1/node", &failure); code != http.StatusBadRequest || failure.Error == "" {
+		t.Errorf("Expected 400 with an error for a missing key, got %d %q", code, failure.Error)
+	}
+	if code := get(t, handler, "/v1/nodes?key=k&count=two", nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad count, got %d", code)
+	}
+	if code := get(t, handler, "/readyz", nil); code != http.StatusOK {
+		t.Errorf("Expected 200 from /readyz, got %d", code)
+	}
+}
+
+func TestHandlerEmptyRing(t *testing.T) {
+	_, handler := newTestHandler(t)
+	if code := get(t, handler, "/v1/node?key=k", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for an empty ring, got %d", code)
+	}
+	if code := get(t, handler, "/readyz", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 from /readyz for an empty ring, got %d", code)
+	}
+}
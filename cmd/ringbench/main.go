@@ -0,0 +1,281 @@
+// Command ringbench runs every ring strategy and hash function over the same
+// node and key workload and prints a comparison table covering lookup cost,
+// memory, balance and churn on membership changes.
+//
+// With -profile DIR it instead runs the small ring, huge ring and churn-heavy
+// scenarios (or those named by -scenarios) and writes a CPU and heap profile
+// for each to DIR, for use with go tool pprof.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// hasherCase is a named hash function under test
+type hasherCase struct {
+	name   string
+	hasher consistenthashing.HashFunction
+}
+
+// strategy builds an empty ring for a hash function
+type strategy struct {
+	name     string
+	build    func(hasher consistenthashing.HashFunction) (consistenthashing.Ring, error)
+	tailOnly bool // Only the last node added can be removed
+}
+
+// result holds the measurements of one strategy/hasher combination
+type result struct {
+	strategy    string
+	hasher      string
+	lookupNs    float64
+	memoryBytes uint64
+	balance     float64
+	addChurn    float64
+	removeChurn float64
+}
+
+func main() {
+	nodeCount := flag.Int("nodes", 10, "number of nodes in the ring")
+	keyCount := flag.Int("keys", 100000, "number of keys to look up")
+	replicaList := flag.String("replicas", "50,100,200", "comma-separated virtual replica counts to compare")
+	profileDir := flag.String("profile", "", "write CPU and heap profiles per scenario to this directory instead of comparing")
+	scenarioList := flag.String("scenarios", "small,huge,churn", "comma-separated scenarios to profile")
+	flag.Parse()
+
+	if *nodeCount < 2 || *keyCount <= 0 {
+		log.Fatal("need at least 2 nodes and a positive key count")
+	}
+
+	if *profileDir != "" {
+		selected, err := selectScenarios(*scenarioList)
+		if err != nil {
+			log.Fatalf("Invalid -scenarios: %v", err)
+		}
+		if err := profileScenarios(*profileDir, selected, makeKeys(*keyCount)); err != nil {
+			log.Fatalf("Profiling failed: %v", err)
+		}
+		return
+	}
+
+	strategies, err := parseStrategies(*replicaList)
+	if err != nil {
+		log.Fatalf("Invalid -replicas: %v", err)
+	}
+
+	hashers := []hasherCase{
+		{name: "FNV-1a", hasher: &consistenthashing.FNVHasher{}},
+		{name: "SHA-256", hasher: &consistenthashing.SHA256Hasher{}},
+		{name: "xxHash64", hasher: &consistenthashing.XXHasher{}},
+		{name: "Murmur3", hasher: &consistenthashing.Murmur3Hasher{}},
+		{name: "CRC32", hasher: &consistenthashing.CRC32Hasher{}},
+		{name: "MD5", hasher: &consistenthashing.MD5Hasher{}},
+		{name: "SHA-1", hasher: &consistenthashing.SHA1Hasher{}},
+		{name: "SipHash", hasher: &consistenthashing.SipHasher{Key: [16]byte{1}}},
+	}
+
+	nodes := makeNodes(*nodeCount)
+	keys := makeKeys(*keyCount)
+
+	var results []result
+	for _, s := range strategies {
+		for _, h := range hashers {
+			r, err := run(s, h, nodes, keys)
+			if err != nil {
+				log.Fatalf("%s/%s failed: %v", s.name, h.name, err)
+			}
+			results = append(results, r)
+		}
+	}
+
+	printResults(results)
+}
+
+// parseStrategies turns the -replicas flag into vnode ring strategies, which
+// are followed by the rendezvous, Maglev and jump hash rings
+func parseStrategies(list string) ([]strategy, error) {
+	var strategies []strategy
+	for _, field := range strings.Split(list, ",") {
+		replicas, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, strategy{
+			name: fmt.Sprintf("vnodes(%d)", replicas),
+			build: func(hasher consistenthashing.HashFunction) (consistenthashing.Ring, error) {
+				return consistenthashing.NewHashRing(replicas, consistenthashing.WithHashFunction(hasher))
+			},
+		})
+	}
+
+	strategies = append(strategies,
+		strategy{
+			name: "rendezvous",
+			build: func(hasher consistenthashing.HashFunction) (consistenthashing.Ring, error) {
+				return consistenthashing.NewRendezvousRing(hasher), nil
+			},
+		},
+		strategy{
+			name: fmt.Sprintf("maglev(%d)", consistenthashing.DefaultMaglevTableSize),
+			build: func(hasher consistenthashing.HashFunction) (consistenthashing.Ring, error) {
+				return consistenthashing.NewMaglevRing(0, hasher)
+			},
+		},
+		strategy{
+			name: "jump",
+			build: func(hasher consistenthashing.HashFunction) (consistenthashing.Ring, error) {
+				return consistenthashing.NewJumpRing(hasher), nil
+			},
+			tailOnly: true,
+		},
+	)
+	return strategies, nil
+}
+
+func makeNodes(count int) []*consistenthashing.Node {
+	nodes := make([]*consistenthashing.Node, count)
+	for i := range nodes {
+		nodes[i] = &consistenthashing.Node{
+			ID:   fmt.Sprintf("node%d", i),
+			Host: fmt.Sprintf("10.0.0.%d", i%250+1),
+			Port: 8080 + i/250,
+		}
+	}
+	return nodes
+}
+
+func makeKeys(count int) []string {
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user:%d", i)
+	}
+	return keys
+}
+
+// run measures one strategy/hasher combination
+func run(s strategy, h hasherCase, nodes []*consistenthashing.Node, keys []string) (result, error) {
+	r := result{strategy: s.name, hasher: h.name}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	ring, err := s.build(h.hasher)
+	if err != nil {
+		return r, err
+	}
+	// Leave the last node out so it can be added for the churn measurement
+	for _, node := range nodes[:len(nodes)-1] {
+		if err := ring.AddNode(node); err != nil {
+			return r, err
+		}
+	}
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	if after.HeapAlloc > before.HeapAlloc {
+		r.memoryBytes = after.HeapAlloc - before.HeapAlloc
+	}
+
+	owners := make([]string, len(keys))
+	counts := make(map[string]int)
+	start := time.Now()
+	for i, key := range keys {
+		node, err := ring.GetNode(key)
+		if err != nil {
+			return r, err
+		}
+		owners[i] = node.ID
+	}
+	r.lookupNs = float64(time.Since(start).Nanoseconds()) / float64(len(keys))
+
+	for _, owner := range owners {
+		counts[owner]++
+	}
+	r.balance = maxOverMean(counts, len(nodes)-1)
+
+	// Churn when the last node joins
+	if err := ring.AddNode(nodes[len(nodes)-1]); err != nil {
+		return r, err
+	}
+	grown, err := ownersOf(ring, keys)
+	if err != nil {
+		return r, err
+	}
+	r.addChurn = movedFraction(owners, grown)
+
+	// Churn when the first node leaves, or the last for rings that can only
+	// shrink at the tail
+	leaving := nodes[0]
+	if s.tailOnly {
+		leaving = nodes[len(nodes)-1]
+	}
+	if err := ring.RemoveNode(leaving.ID); err != nil {
+		return r, err
+	}
+	shrunk, err := ownersOf(ring, keys)
+	if err != nil {
+		return r, err
+	}
+	r.removeChurn = movedFraction(grown, shrunk)
+
+	return r, nil
+}
+
+func ownersOf(ring consistenthashing.Ring, keys []string) ([]string, error) {
+	owners := make([]string, len(keys))
+	for i, key := range keys {
+		node, err := ring.GetNode(key)
+		if err != nil {
+			return nil, err
+		}
+		owners[i] = node.ID
+	}
+	return owners, nil
+}
+
+// maxOverMean reports the busiest node's load relative to a perfect split
+func maxOverMean(counts map[string]int, nodes int) float64 {
+	total, busiest := 0, 0
+	for _, count := range counts {
+		total += count
+		if count > busiest {
+			busiest = count
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(busiest) / (float64(total) / float64(nodes))
+}
+
+func movedFraction(before, after []string) float64 {
+	moved := 0
+	for i := range before {
+		if before[i] != after[i] {
+			moved++
+		}
+	}
+	return float64(moved) / float64(len(before))
+}
+
+func printResults(results []result) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STRATEGY\tHASHER\tLOOKUP ns/op\tMEMORY\tMAX/MEAN\tADD CHURN\tREMOVE CHURN")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%.1f\t%.1f KiB\t%.3f\t%.2f%%\t%.2f%%\n",
+			r.strategy, r.hasher, r.lookupNs, float64(r.memoryBytes)/1024,
+			r.balance, r.addChurn*100, r.removeChurn*100)
+	}
+	tw.Flush()
+}
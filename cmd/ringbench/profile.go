@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// scenario is a workload profiled on its own, so its CPU profile only shows
+// the code paths it exercises. It returns its ring so the heap profile can
+// capture the ring while it is still live.
+type scenario struct {
+	name string
+	run  func(keys []string) (*consistenthashing.HashRing, error)
+}
+
+// scenarios are the profiled workloads, by name
+var scenarios = []scenario{
+	{name: "small", run: lookupScenario(10, 100)},
+	{name: "huge", run: lookupScenario(2000, 200)},
+	{name: "churn", run: churnScenario(100, 100, 200)},
+}
+
+// lookupScenario builds a ring of the given size and looks up every key
+func lookupScenario(nodeCount, replicas int) func([]string) (*consistenthashing.HashRing, error) {
+	return func(keys []string) (*consistenthashing.HashRing, error) {
+		ring, err := consistenthashing.NewHashRing(replicas)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range makeNodes(nodeCount) {
+			if err := ring.AddNode(node); err != nil {
+				return nil, err
+			}
+		}
+		for _, key := range keys {
+			if _, err := ring.GetNode(key); err != nil {
+				return nil, err
+			}
+		}
+		return ring, nil
+	}
+}
+
+// churnScenario repeatedly removes and re-adds nodes, with a slice of the
+// keys looked up after every change
+func churnScenario(nodeCount, replicas, rounds int) func([]string) (*consistenthashing.HashRing, error) {
+	return func(keys []string) (*consistenthashing.HashRing, error) {
+		ring, err := consistenthashing.NewHashRing(replicas)
+		if err != nil {
+			return nil, err
+		}
+		nodes := makeNodes(nodeCount)
+		for _, node := range nodes {
+			if err := ring.AddNode(node); err != nil {
+				return nil, err
+			}
+		}
+
+		batch := max(len(keys)/rounds, 1)
+		for i := 0; i < rounds; i++ {
+			node := nodes[i%len(nodes)]
+			if err := ring.RemoveNode(node.ID); err != nil {
+				return nil, err
+			}
+			if err := ring.AddNode(node); err != nil {
+				return nil, err
+			}
+			start := i * batch % len(keys)
+			for _, key := range keys[start:min(start+batch, len(keys))] {
+				if _, err := ring.GetNode(key); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return ring, nil
+	}
+}
+
+// selectScenarios returns the scenarios named in a comma-separated list
+func selectScenarios(list string) ([]scenario, error) {
+	var selected []scenario
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, s := range scenarios {
+			if s.name == name {
+				selected = append(selected, s)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown scenario %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// profileScenarios runs each scenario under the CPU profiler, then writes its
+// heap profile, as <dir>/<scenario>.cpu.pprof and <dir>/<scenario>.heap.pprof.
+// Allocation totals in heap profiles are cumulative, so compare alloc_space
+// between runs of a single scenario.
+func profileScenarios(dir string, selected []scenario, keys []string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, s := range selected {
+		cpuPath := filepath.Join(dir, s.name+".cpu.pprof")
+		heapPath := filepath.Join(dir, s.name+".heap.pprof")
+
+		var ring *consistenthashing.HashRing
+		elapsed, err := profileCPU(cpuPath, func() (err error) {
+			ring, err = s.run(keys)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		if err := writeHeapProfile(heapPath); err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		runtime.KeepAlive(ring)
+		fmt.Printf("%-6s %10v  %s  %s\n", s.name, elapsed.Round(time.Microsecond), cpuPath, heapPath)
+	}
+	return nil
+}
+
+// profileCPU runs fn with CPU profiling written to path
+func profileCPU(path string, fn func() error) (time.Duration, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	err = fn()
+	elapsed := time.Since(start)
+	pprof.StopCPUProfile()
+	if err != nil {
+		return 0, err
+	}
+	return elapsed, f.Close()
+}
+
+// writeHeapProfile writes an up to date heap profile to path
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
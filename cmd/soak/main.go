@@ -0,0 +1,335 @@
+// Command soak runs a long randomized workload against a ring for release
+// qualification. Lookup workers run concurrently with membership churn,
+// snapshot round trips and deep validation, and a report of latency
+// percentiles, heap growth and invariant violations is printed every
+// interval. It exits non-zero if any violation was seen.
+//
+// Usage:
+//
+//	soak [-duration 4h] [-nodes 50] [-workers 8] [-churn 100ms] [-report 1m]
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// maxSamples bounds the latency samples kept per worker per interval
+const maxSamples = 10000
+
+// config holds the command line settings
+type config struct {
+	duration  time.Duration
+	nodes     int
+	replicas  int
+	keys      int
+	workers   int
+	churn     time.Duration
+	snapshot  time.Duration
+	validate  time.Duration
+	report    time.Duration
+	seed      int64
+	maxGrowth float64
+}
+
+// soak holds the shared state of a run
+type soak struct {
+	config     config
+	ring       *consistenthashing.HashRing
+	nextID     atomic.Int64
+	lookups    atomic.Int64
+	churnOps   atomic.Int64
+	snapshots  atomic.Int64
+	violations atomic.Int64
+
+	mu      sync.Mutex
+	samples [][]time.Duration // Latency samples per worker for the current interval
+}
+
+func main() {
+	var c config
+	flag.DurationVar(&c.duration, "duration", time.Hour, "how long to run")
+	flag.IntVar(&c.nodes, "nodes", 50, "initial number of nodes")
+	flag.IntVar(&c.replicas, "replicas", 150, "virtual replicas per node")
+	flag.IntVar(&c.keys, "keys", 1000000, "size of the key space looked up")
+	flag.IntVar(&c.workers, "workers", runtime.GOMAXPROCS(0), "concurrent lookup workers")
+	flag.DurationVar(&c.churn, "churn", 100*time.Millisecond, "time between membership changes")
+	flag.DurationVar(&c.snapshot, "snapshot", 10*time.Second, "time between snapshot round trips")
+	flag.DurationVar(&c.validate, "validate", 5*time.Second, "time between deep validations")
+	flag.DurationVar(&c.report, "report", time.Minute, "time between progress reports")
+	flag.Int64Var(&c.seed, "seed", time.Now().UnixNano(), "random seed")
+	flag.Float64Var(&c.maxGrowth, "max-growth", 2, "largest accepted heap growth over the warmed-up baseline, as a ratio")
+	flag.Parse()
+
+	if c.nodes < 2 || c.replicas <= 0 || c.keys <= 0 || c.workers <= 0 {
+		log.Fatal("need at least 2 nodes and positive -replicas, -keys and -workers")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, c.duration)
+	defer cancel()
+
+	s, err := newSoak(c)
+	if err != nil {
+		log.Fatalf("Failed to build ring: %v", err)
+	}
+	log.Printf("soaking %d nodes x %d replicas with %d workers for %v (seed %d)",
+		c.nodes, c.replicas, c.workers, c.duration, c.seed)
+
+	if !s.run(ctx) {
+		os.Exit(1)
+	}
+}
+
+func newSoak(c config) (*soak, error) {
+	ring, err := consistenthashing.NewHashRing(c.replicas)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &soak{config: c, ring: ring, samples: make([][]time.Duration, c.workers)}
+	for i := 0; i < c.nodes; i++ {
+		if err := ring.AddNode(s.newNode()); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// newNode returns a node with a fresh ID
+func (s *soak) newNode() *consistenthashing.Node {
+	id := s.nextID.Add(1)
+	return &consistenthashing.Node{
+		ID:   fmt.Sprintf("node%d", id),
+		Host: fmt.Sprintf("10.%d.%d.%d", id>>16&0xff, id>>8&0xff, id&0xff),
+		Port: 6379,
+	}
+}
+
+// run drives the workload until ctx is done and reports whether the ring
+// passed
+func (s *soak) run(ctx context.Context) bool {
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			s.lookupLoop(ctx, worker)
+		}(i)
+	}
+
+	periodic := []struct {
+		every time.Duration
+		fn    func(*rand.Rand)
+	}{
+		{s.config.churn, s.churnOnce},
+		{s.config.snapshot, s.snapshotOnce},
+		{s.config.validate, s.validateOnce},
+	}
+	for i, p := range periodic {
+		if p.every <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(rng *rand.Rand, every time.Duration, fn func(*rand.Rand)) {
+			defer wg.Done()
+			ticker := time.NewTicker(every)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					fn(rng)
+				}
+			}
+		}(rand.New(rand.NewSource(s.config.seed+int64(s.config.workers+i))), p.every, p.fn)
+	}
+
+	// The first report's heap is the baseline, after the ring has warmed up
+	var baseline uint64
+	ticker := time.NewTicker(s.config.report)
+	defer ticker.Stop()
+	start := time.Now()
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			done = true
+		case <-ticker.C:
+		}
+
+		heap := s.report(time.Since(start))
+		if baseline == 0 {
+			baseline = heap
+		}
+	}
+	wg.Wait()
+	s.validateOnce(nil)
+
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	growth := float64(mem.HeapAlloc) / float64(max(baseline, 1))
+
+	passed := true
+	if v := s.violations.Load(); v > 0 {
+		log.Printf("FAIL: %d invariant violations", v)
+		passed = false
+	}
+	if growth > s.config.maxGrowth {
+		log.Printf("FAIL: heap grew %.2fx over the baseline, limit %.2fx", growth, s.config.maxGrowth)
+		passed = false
+	}
+	if passed {
+		log.Printf("PASS: heap %.2fx of baseline, no violations", growth)
+	}
+	return passed
+}
+
+// lookupLoop looks up random keys, sampling latencies for the report
+func (s *soak) lookupLoop(ctx context.Context, worker int) {
+	rng := rand.New(rand.NewSource(s.config.seed + int64(worker)))
+	for i := 0; ; i++ {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return
+		}
+
+		key := fmt.Sprintf("key:%d", rng.Intn(s.config.keys))
+		start := time.Now()
+		var err error
+		if i%8 == 0 {
+			_, err = s.ring.GetNodes(key, 3)
+		} else {
+			_, err = s.ring.GetNode(key)
+		}
+		elapsed := time.Since(start)
+		if err != nil {
+			s.violation("lookup failed: %v", err)
+		}
+		s.lookups.Add(1)
+
+		if i%16 == 0 {
+			s.mu.Lock()
+			if len(s.samples[worker]) < maxSamples {
+				s.samples[worker] = append(s.samples[worker], elapsed)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// churnOnce applies one random membership change, keeping at least half the
+// initial nodes
+func (s *soak) churnOnce(rng *rand.Rand) {
+	nodes := s.ring.GetAllNodes()
+	var err error
+	switch op := rng.Intn(4); {
+	case op == 0 || len(nodes) <= s.config.nodes/2:
+		err = s.ring.AddNode(s.newNode())
+	case op == 1:
+		err = s.ring.RemoveNode(nodes[rng.Intn(len(nodes))].ID)
+	case op == 2:
+		err = s.ring.UpdateNodeWeight(nodes[rng.Intn(len(nodes))].ID, 1+rng.Intn(4))
+	default:
+		// Replace a random tenth of the membership in one step
+		members := nodes[:0:0]
+		for _, node := range nodes {
+			if rng.Intn(10) > 0 {
+				members = append(members, node)
+			}
+		}
+		for len(members) < len(nodes) {
+			members = append(members, s.newNode())
+		}
+		_, err = s.ring.SetMembers(members)
+	}
+	if err != nil {
+		s.violation("churn failed: %v", err)
+	}
+	s.churnOps.Add(1)
+}
+
+// snapshotOnce round trips the ring through a snapshot and checks the copy
+func (s *soak) snapshotOnce(*rand.Rand) {
+	var buf bytes.Buffer
+	if err := s.ring.WriteSnapshot(&buf); err != nil {
+		s.violation("snapshot failed: %v", err)
+		return
+	}
+	snapshot, err := consistenthashing.ReadSnapshot(&buf, nil)
+	if err != nil {
+		s.violation("snapshot decode failed: %v", err)
+		return
+	}
+	restored, err := consistenthashing.RestoreHashRing(snapshot)
+	if err != nil {
+		s.violation("snapshot restore failed: %v", err)
+		return
+	}
+	if err := restored.ValidateRingDeep(); err != nil {
+		s.violation("restored ring invalid: %v", err)
+	}
+	if restored.Size() != len(snapshot.Nodes) || restored.Generation() != snapshot.Generation {
+		s.violation("restored ring has %d nodes at generation %d, snapshot has %d at %d",
+			restored.Size(), restored.Generation(), len(snapshot.Nodes), snapshot.Generation)
+	}
+	s.snapshots.Add(1)
+}
+
+// validateOnce runs the ring's deep consistency checks
+func (s *soak) validateOnce(*rand.Rand) {
+	if err := s.ring.ValidateRingDeep(); err != nil {
+		s.violation("validation failed: %v", err)
+	}
+}
+
+func (s *soak) violation(format string, args ...interface{}) {
+	s.violations.Add(1)
+	log.Printf("VIOLATION: "+format, args...)
+}
+
+// report prints progress for the interval just ended and returns the live
+// heap size
+func (s *soak) report(elapsed time.Duration) uint64 {
+	s.mu.Lock()
+	var samples []time.Duration
+	for i := range s.samples {
+		samples = append(samples, s.samples[i]...)
+		s.samples[i] = s.samples[i][:0]
+	}
+	s.mu.Unlock()
+	slices.Sort(samples)
+
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	log.Printf("%v: lookups=%d churn=%d snapshots=%d nodes=%d gen=%d p50=%v p99=%v p99.9=%v heap=%.1fMiB goroutines=%d violations=%d",
+		elapsed.Round(time.Second), s.lookups.Load(), s.churnOps.Load(), s.snapshots.Load(),
+		s.ring.Size(), s.ring.Generation(),
+		percentile(samples, 0.5), percentile(samples, 0.99), percentile(samples, 0.999),
+		float64(mem.HeapAlloc)/(1<<20), runtime.NumGoroutine(), s.violations.Load())
+	return mem.HeapAlloc
+}
+
+// percentile returns the p-th quantile of sorted samples
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[min(int(p*float64(len(sorted))), len(sorted)-1)]
+}
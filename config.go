@@ -0,0 +1,126 @@
+package consistenthashing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RingConfig is the JSON configuration file format for a ring's membership,
+// for example {"virtual_replicas": 150, "nodes": [{"id": "a", "host": "10.0.0.1", "port": 6379}]}
+type RingConfig struct {
+	VirtualReplicas int            `json:"virtual_replicas,omitempty"`
+	HashFunction    string         `json:"hash_function,omitempty"`     // "FNV-1a", "SHA-256", "xxHash64", "Murmur3", "CRC32", "MD5" or "SHA-1"
+	Classes         map[string]int `json:"classes,omitempty"`           // Capacity class weights, e.g. {"small": 1, "large": 4}
+	MaxVirtualNodes int            `json:"max_virtual_nodes,omitempty"` // Cap on the total virtual node count
+	Nodes           []Node         `json:"nodes"`
+}
+
+// defaultConfigReplicas is used when a configuration omits virtual_replicas
+const defaultConfigReplicas = 150
+
+// NewHashRingFromConfig creates a ring with the settings and members of a
+// configuration. Options are applied after the configured hash function.
+func NewHashRingFromConfig(config RingConfig, opts ...Option) (*HashRing, error) {
+	replicas := config.VirtualReplicas
+	if replicas == 0 {
+		replicas = defaultConfigReplicas
+	}
+
+	base := []Option{WithCapacityClasses(config.Classes), WithMaxVirtualNodes(config.MaxVirtualNodes)}
+	if config.HashFunction != "" {
+		hasher := hasherByName(config.HashFunction)
+		if hasher == nil {
+			return nil, fmt.Errorf("unknown hash function %q", config.HashFunction)
+		}
+		base = append(base, WithHashFunction(hasher))
+	}
+
+	hr, err := NewHashRing(replicas, append(base, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := hr.SetMembers(config.Members()); err != nil {
+		return nil, err
+	}
+	return hr, nil
+}
+
+// LoadConfig reads a ring configuration file
+func LoadConfig(path string) (RingConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RingConfig{}, err
+	}
+
+	var config RingConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return RingConfig{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Members returns the configured nodes
+func (c RingConfig) Members() []*Node {
+	nodes := make([]*Node, len(c.Nodes))
+	for i := range c.Nodes {
+		node := c.Nodes[i].clone()
+		nodes[i] = &node
+	}
+	return nodes
+}
+
+// ReloadFromConfig atomically replaces the ring's membership with the nodes in
+// a configuration file and reports the churn. Ring settings in the file are
+// ignored, since they can't change on a live ring; capacity classes are
+// re-tuned with SetClassWeight.
+func (hr *HashRing) ReloadFromConfig(path string) (ChurnReport, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return ChurnReport{}, err
+	}
+	return hr.SetMembers(config.Members())
+}
+
+// WatchConfig polls a configuration file every interval and reloads the ring
+// when the file's size or modification time changes, calling onReload with
+// the outcome of each reload. The current contents are assumed to be loaded
+// already. It blocks until ctx is done.
+func (hr *HashRing) WatchConfig(ctx context.Context, path string, interval time.Duration, onReload func(ChurnReport, error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid watch interval %v", interval)
+	}
+
+	last, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	ticker := hr.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			// The file may be mid-replacement; try again next tick
+			continue
+		}
+		if info.Size() == last.Size() && info.ModTime().Equal(last.ModTime()) {
+			continue
+		}
+		last = info
+
+		report, err := hr.ReloadFromConfig(path)
+		if onReload != nil {
+			onReload(report, err)
+		}
+	}
+}
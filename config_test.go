@@ -0,0 +1,135 @@
+package consistenthashing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.json")
+	writeConfig(t, path, `{"virtual_replicas": 100, "nodes": [{"id": "a", "host": "10.0.0.1", "port": 6379, "weight": 2}]}`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.VirtualReplicas != 100 || len(config.Nodes) != 1 {
+		t.Fatalf("Unexpected config %+v", config)
+	}
+	if node := config.Members()[0]; node.ID != "a" || node.Weight != 2 {
+		t.Errorf("Unexpected member %+v", node)
+	}
+
+	writeConfig(t, path, `{"nodes": [`)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected error for malformed config")
+	}
+}
+
+func TestReloadFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.json")
+	writeConfig(t, path, `{"nodes": [{"id": "a", "host": "h", "port": 1}, {"id": "b", "host": "h", "port": 2}]}`)
+
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	report, err := ring.ReloadFromConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if len(report.Added) != 2 || ring.Size() != 2 {
+		t.Errorf("Expected 2 nodes added, got %+v", report)
+	}
+
+	// A broken file keeps the current membership
+	writeConfig(t, path, `{"nodes": [{"id": "a"}]}`)
+	if _, err := ring.ReloadFromConfig(path); err == nil {
+		t.Error("Expected error for invalid node")
+	}
+	if ring.Size() != 2 {
+		t.Errorf("Expected membership to be kept, got %d nodes", ring.Size())
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.json")
+	writeConfig(t, path, `{"nodes": [{"id": "a", "host": "h", "port": 1}]}`)
+
+	ring, _ := NewHashRing(20)
+	ring.ReloadFromConfig(path)
+
+	if err := ring.WatchConfig(context.Background(), path, 0, nil); err == nil {
+		t.Error("Expected error for invalid interval")
+	}
+
+	reloads := make(chan ChurnReport, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- ring.WatchConfig(ctx, path, 5*time.Millisecond, func(report ChurnReport, err error) {
+			if err != nil || !report.Changed() {
+				return
+			}
+			select {
+			case reloads <- report:
+			default:
+			}
+		})
+	}()
+
+	// The watcher may not have looked at the file yet, so keep rewriting it
+	// until the change is picked up
+	timeout := time.After(5 * time.Second)
+	for waiting := true; waiting; {
+		writeConfig(t, path, `{"nodes": [{"id": "a", "host": "h", "port": 1}, {"id": "bb", "host": "h", "port": 2}]}`)
+		select {
+		case report := <-reloads:
+			if len(report.Added) != 1 || report.Added[0] != "bb" {
+				t.Errorf("Unexpected reload %+v", report)
+			}
+			waiting = false
+		case <-time.After(20 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("Timed out waiting for reload")
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewHashRingFromConfig(t *testing.T) {
+	config := RingConfig{
+		HashFunction: "SHA-256",
+		Nodes:        []Node{{ID: "a", Host: "h", Port: 1}, {ID: "b", Host: "h", Port: 2, Weight: 3}},
+	}
+
+	ring, err := NewHashRingFromConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if ring.Size() != 2 || ring.VirtualSize() != 4*defaultConfigReplicas {
+		t.Errorf("Expected 2 nodes and %d virtual nodes, got %d and %d", 4*defaultConfigReplicas, ring.Size(), ring.VirtualSize())
+	}
+	if info := ring.GetRingInfo(); info["hash_function"] != "SHA-256" {
+		t.Errorf("Expected SHA-256, got %v", info["hash_function"])
+	}
+
+	config.HashFunction = "md4"
+	if _, err := NewHashRingFromConfig(config); err == nil {
+		t.Error("Expected error for unknown hash function")
+	}
+}
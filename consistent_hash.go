@@ -1,13 +1,18 @@
 package consistenthashing
 
 import (
-	"crypto/sha256"
+	"context"
 	"errors"
 	"fmt"
-	"hash/fnv"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
-	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Common errors
@@ -18,38 +23,63 @@ var (
 	ErrInvalidNodePort        = errors.New("node port must be positive")
 	ErrInvalidCount           = errors.New("count must be positive")
 	ErrNodeNotFound           = errors.New("node not found")
+	ErrEmptyRing              = errors.New("no nodes available in the ring")
+	ErrInvalidNodeWeight      = errors.New("node weight exceeds MaxNodeWeight")
+	ErrTooManyVirtualNodes    = errors.New("too many virtual nodes for a single node")
 )
 
-// HashFunction defines the interface for hash functions
+// MaxNodeWeight is the largest accepted node weight. It keeps a single node
+// from exploding the number of virtual nodes in the ring.
+const MaxNodeWeight = 10000
+
+// HashFunction defines the interface for hash functions. HashBytes must
+// return what Hash returns for the same bytes, so byte slice keys route like
+// their string form without being converted.
 type HashFunction interface {
 	Hash(key string) uint64 // Changed to uint64 for better collision resistance
+	HashBytes(key []byte) uint64
 }
 
-// FNVHasher implements HashFunction using FNV-1a (faster than SHA-256)
-type FNVHasher struct{}
+// FNV-1a 64-bit parameters
+const (
+	fnvOffset64 uint64 = 14695981039346656037
+	fnvPrime64  uint64 = 1099511628211
+)
 
-func (f *FNVHasher) Hash(key string) uint64 {
-	h := fnv.New64a()
-	h.Write([]byte(key))
-	return h.Sum64()
+// FNVHasher implements HashFunction using FNV-1a (faster than SHA-256). A
+// non-zero Seed is mixed into the offset basis.
+type FNVHasher struct {
+	Seed uint64
 }
 
-// SHA256Hasher implements HashFunction using SHA-256 (more secure)
-type SHA256Hasher struct{}
+// Hash computes FNV-1a inline, which avoids allocating a hash.Hash64
+func (f *FNVHasher) Hash(key string) uint64 {
+	h := fnvOffset64 ^ f.Seed
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= fnvPrime64
+	}
+	return h
+}
 
-func (s *SHA256Hasher) Hash(key string) uint64 {
-	h := sha256.Sum256([]byte(key))
-	// Use full 64 bits instead of truncating to 32 bits
-	return uint64(h[0])<<56 | uint64(h[1])<<48 | uint64(h[2])<<40 | uint64(h[3])<<32 |
-		uint64(h[4])<<24 | uint64(h[5])<<16 | uint64(h[6])<<8 | uint64(h[7])
+func (f *FNVHasher) HashBytes(key []byte) uint64 {
+	h := fnvOffset64 ^ f.Seed
+	for _, b := range key {
+		h ^= uint64(b)
+		h *= fnvPrime64
+	}
+	return h
 }
 
 // Node represents a physical node in the distributed system
 type Node struct {
-	ID     string
-	Host   string
-	Port   int
-	Weight int // Weight for weighted consistent hashing
+	ID     string            `json:"id"`
+	Host   string            `json:"host"`
+	Port   int               `json:"port"`
+	Weight int               `json:"weight,omitempty"` // Weight for weighted consistent hashing
+	Zone   string            `json:"zone,omitempty"`   // Optional failure domain, e.g. an availability zone
+	Class  string            `json:"class,omitempty"`  // Optional capacity class, overriding Weight
+	Meta   map[string]string `json:"meta,omitempty"`   // Optional labels, e.g. {"disk": "ssd", "region": "us-east"}
 }
 
 // Validate checks if the node has valid parameters
@@ -63,15 +93,44 @@ func (n *Node) Validate() error {
 	if n.Port <= 0 || n.Port > 65535 {
 		return ErrInvalidNodePort
 	}
+	if n.Weight > MaxNodeWeight {
+		return ErrInvalidNodeWeight
+	}
 	return nil
 }
 
+// nodeWeight returns the node's weight, treating non-positive weights as 1
+func nodeWeight(node *Node) int {
+	if node.Weight <= 0 {
+		return 1
+	}
+	return node.Weight
+}
+
 // String returns a string representation of the node
 func (n Node) String() string {
 	return fmt.Sprintf("%s:%d", n.Host, n.Port)
 }
 
-// VirtualNode represents a virtual node on the hash ring
+// Equal reports whether two nodes have the same fields and metadata
+func (n *Node) Equal(other *Node) bool {
+	return n.ID == other.ID && n.Host == other.Host && n.Port == other.Port &&
+		n.Weight == other.Weight && n.Zone == other.Zone && n.Class == other.Class &&
+		maps.Equal(n.Meta, other.Meta)
+}
+
+// clone returns a copy of n with its own Meta map, so copies handed out of or
+// into the ring never share labels with the original
+func (n *Node) clone() Node {
+	c := *n
+	c.Meta = maps.Clone(n.Meta)
+	return c
+}
+
+// VirtualNode represents a virtual node on the hash ring. It references its
+// node by pointer, so node IDs are stored once however many virtual nodes a
+// node has; per-node maps and events reuse the same ID string rather than
+// copies of it.
 type VirtualNode struct {
 	Hash uint64 // Changed to uint64
 	Node *Node
@@ -79,11 +138,43 @@ type VirtualNode struct {
 
 // HashRing represents the consistent hash ring
 type HashRing struct {
-	virtualNodes    []VirtualNode
-	nodes           map[string]*Node
-	virtualReplicas int
-	hasher          HashFunction
-	mu              sync.RWMutex // Thread safety
+	virtualNodes      []VirtualNode
+	scratch           []VirtualNode // Reused buffer for virtual nodes being added
+	nodes             map[string]*Node
+	virtualReplicas   int
+	hasher            HashFunction
+	hashSeed          uint64                      // Optional seed applied to the hasher by configure
+	sampler           *keySampler                 // Optional reservoir of looked-up keys
+	history           *ringHistory                // Optional past generations for GetNodeAt
+	sorted            atomic.Pointer[sortedNodes] // Members sorted by ID, per generation
+	affinity          map[string]string           // Key prefix to affinity group
+	classes           map[string]int              // Capacity class weights
+	loads             *loadTracker                // Optional per-node load counters
+	loadBound         float64                     // Optional bounded-load factor over the weighted average
+	spillover         SpilloverPolicy             // Where GetNodeBounded sends keys of full nodes
+	spillCandidates   int                         // Nodes SpillLeastLoaded chooses from, or 0
+	loadHalfLife      time.Duration               // Optional decay of the load counters
+	proximity         []Proximity                 // Optional replica ordering for GetNodes
+	weightedOrder     bool                        // Order GetNodes replicas by weight
+	shardCount        int                         // Fixed number of shards for ShardOf
+	codec             Codec                       // Encoding used by WriteSnapshot
+	hooks             *hooks                      // Optional operation hooks
+	clock             Clock                       // Source of time for time-dependent features
+	weightCurve       WeightCurve                 // Weight to virtual node count
+	maxVirtualNodes   int                         // Optional cap on the total virtual node count
+	fallback          *Node                       // Optional owner of every key while empty
+	emptyKeyPolicy    EmptyKeyPolicy              // How the empty key is routed
+	emptyKeyNode      *Node                       // Owner of the empty key under EmptyKeyNode
+	scaledTotal       int                         // Uncapped total the counts are scaled from, or 0
+	randSource        rand.Source                 // Optional source for randomized strategies
+	random            *lockedRand                 // Generator over randSource, set by configure
+	states            map[string]NodeState        // Non-active member states
+	drains            map[string]*drainState      // Progress of draining members
+	replicationFactor int                         // Replica set size for availability checks
+	conflictPolicy    ConflictPolicy              // How SetMembers handles changed members
+	watchers          *watchers                   // Subscribers to membership changes
+	generation        uint64                      // Incremented on every membership change
+	mu                ringMutex                   // Thread safety
 }
 
 // Option defines configuration options for HashRing
@@ -98,23 +189,90 @@ func WithHashFunction(hasher HashFunction) Option {
 
 // NewHashRing creates a new hash ring with the specified number of virtual replicas per node
 func NewHashRing(virtualReplicas int, opts ...Option) (*HashRing, error) {
+	hr := new(HashRing)
+	if err := hr.reset(virtualReplicas, opts); err != nil {
+		return nil, err
+	}
+	return hr, nil
+}
+
+// reset turns hr into a new, empty ring. It must not be in use.
+func (hr *HashRing) reset(virtualReplicas int, opts []Option) error {
 	if virtualReplicas <= 0 {
-		return nil, ErrInvalidVirtualReplicas
+		return ErrInvalidVirtualReplicas
 	}
 
-	hr := &HashRing{
-		virtualNodes:    make([]VirtualNode, 0),
-		nodes:           make(map[string]*Node),
-		virtualReplicas: virtualReplicas,
-		hasher:          &FNVHasher{}, // Default to faster FNV hash
+	*hr = HashRing{
+		virtualNodes:      make([]VirtualNode, 0),
+		nodes:             make(map[string]*Node),
+		virtualReplicas:   virtualReplicas,
+		hasher:            &FNVHasher{}, // Default to faster FNV hash
+		shardCount:        DefaultShardCount,
+		replicationFactor: DefaultReplicationFactor,
+		codec:             JSONCodec{},
+		clock:             systemClock{},
+		weightCurve:       LinearWeights,
+		watchers:          &watchers{subs: make(map[int]chan RingEvent)},
 	}
+	return hr.configure(opts)
+}
 
-	// Apply options
+// configure applies options to a new ring, validates the result and sets up
+// the features they enabled
+func (hr *HashRing) configure(opts []Option) error {
 	for _, opt := range opts {
 		opt(hr)
 	}
 
-	return hr, nil
+	if hr.virtualReplicas <= 0 {
+		return ErrInvalidVirtualReplicas
+	}
+	if err := validateClasses(hr.classes); err != nil {
+		return err
+	}
+	if err := hr.validateEmptyKeyPolicy(); err != nil {
+		return err
+	}
+	if err := hr.validateBoundedLoads(); err != nil {
+		return err
+	}
+	if err := hr.applyHashSeed(); err != nil {
+		return err
+	}
+	if hr.loadHalfLife < 0 {
+		return errors.New("load half-life cannot be negative")
+	}
+	if hr.fallback != nil {
+		if err := hr.fallback.Validate(); err != nil {
+			return fmt.Errorf("invalid fallback node: %w", err)
+		}
+	}
+	if h, ok := hr.hasher.(digestHasher); ok {
+		mapping, size := h.digestMapping()
+		if err := mapping.validate(size); err != nil {
+			return err
+		}
+	}
+
+	// Options may be given in any order, so propagate WithNoLocking,
+	// WithRandSource and WithClock last
+	hr.random = &lockedRand{rng: hr.newRand()}
+	hr.random.mu.disabled = hr.mu.disabled
+	if hr.sampler != nil {
+		hr.sampler.mu.disabled = hr.mu.disabled
+		hr.sampler.rng = hr.random
+	}
+	if hr.loads != nil {
+		hr.loads.mu.disabled = hr.mu.disabled
+		hr.loads.halfLife = hr.loadHalfLife
+		hr.loads.clock = hr.clock
+	}
+	if hr.hooks != nil {
+		hr.hooks.clock = hr.clock
+	}
+	hr.recordHistoryLocked()
+
+	return nil
 }
 
 // hash generates a hash value for the given key
@@ -122,15 +280,106 @@ func (hr *HashRing) hash(key string) uint64 {
 	return hr.hasher.Hash(key)
 }
 
+// hashBytes hashes a binary key without converting it to a string. Built-in
+// hashers, none of which retain the key, are called directly; custom hashers
+// get a copy, so callers' stack buffers never escape to the heap.
+func (hr *HashRing) hashBytes(key []byte) uint64 {
+	switch h := hr.hasher.(type) {
+	case *FNVHasher:
+		return h.HashBytes(key)
+	case *XXHasher:
+		return h.HashBytes(key)
+	case *Murmur3Hasher:
+		return h.HashBytes(key)
+	case *SipHasher:
+		return h.HashBytes(key)
+	case *SHA256Hasher:
+		return h.HashBytes(key)
+	case *MD5Hasher:
+		return h.HashBytes(key)
+	case *SHA1Hasher:
+		return h.HashBytes(key)
+	case *CRC32Hasher:
+		return h.hashShort(key)
+	}
+	return hr.hasher.HashBytes(append([]byte(nil), key...))
+}
+
 // generateVirtualKey creates a more varied virtual node key to improve distribution
 func (hr *HashRing) generateVirtualKey(nodeID string, index int) string {
+	return string(hr.appendVirtualKey(nil, nodeID, index))
+}
+
+// appendVirtualKey appends the virtual node key to dst. Building keys into a
+// reused buffer means adding a node does not copy its ID into a new string for
+// every virtual node.
+func (hr *HashRing) appendVirtualKey(dst []byte, nodeID string, index int) []byte {
 	// Use a more complex pattern to reduce clustering for similar node IDs
 	// Include the hash ring's virtual replica count as a seed for uniqueness
-	return fmt.Sprintf("vnode:%s:replica:%d:seed:%d", nodeID, index, hr.virtualReplicas)
+	dst = append(dst, "vnode:"...)
+	dst = append(dst, nodeID...)
+	dst = append(dst, ":replica:"...)
+	dst = strconv.AppendInt(dst, int64(index), 10)
+	dst = append(dst, ":seed:"...)
+	return strconv.AppendInt(dst, int64(hr.virtualReplicas), 10)
+}
+
+// virtualHashes returns the hash positions of a node's first count virtual nodes
+func (hr *HashRing) virtualHashes(nodeID string, count int) []uint64 {
+	hashes := make([]uint64, count)
+	var buf []byte
+	for i := range hashes {
+		buf = hr.appendVirtualKey(buf[:0], nodeID, i)
+		hashes[i] = hr.hashBytes(buf)
+	}
+	return hashes
+}
+
+// virtualCount returns the number of virtual nodes a member has: its curve
+// count, scaled down while the ring is over its virtual node cap. Callers
+// must hold the lock.
+func (hr *HashRing) virtualCount(node *Node) (int, error) {
+	count, err := hr.curveCount(node)
+	if err != nil {
+		return 0, err
+	}
+	return scaleCount(count, hr.scaledTotal, hr.maxVirtualNodes), nil
+}
+
+// curveCount returns the number of virtual nodes the weight curve gives a
+// node for its effective weight, guarding against overflow
+func (hr *HashRing) curveCount(node *Node) (int, error) {
+	if _, ok := hr.classes[node.Class]; node.Class != "" && !ok {
+		return 0, fmt.Errorf("%w %q for node %s", ErrUnknownClass, node.Class, node.ID)
+	}
+	count := hr.weightCurve(hr.virtualReplicas, hr.weightLocked(node))
+	if count <= 0 {
+		return 0, fmt.Errorf("weight curve gave %d virtual nodes for node %s", count, node.ID)
+	}
+	if count > math.MaxInt32 {
+		return 0, ErrTooManyVirtualNodes
+	}
+	return count, nil
 }
 
 // AddNode adds a new node to the hash ring
 func (hr *HashRing) AddNode(node *Node) error {
+	if hr.hooks == nil {
+		return hr.addNode(node)
+	}
+
+	id := ""
+	if node != nil {
+		id = node.ID
+	}
+	start := hr.hooks.begin(OpAddNode, id)
+	err := hr.addNode(node)
+	hr.hooks.end(OpAddNode, id, node, err, start)
+	return err
+}
+
+// addNode is AddNode without hooks
+func (hr *HashRing) addNode(node *Node) error {
 	if node == nil {
 		return errors.New("node cannot be nil")
 	}
@@ -146,165 +395,460 @@ func (hr *HashRing) AddNode(node *Node) error {
 		return nil // Node already exists, not an error
 	}
 
-	hr.nodes[node.ID] = node
+	if _, err := hr.virtualCount(node); err != nil {
+		return err
+	}
 
-	// Calculate virtual replicas based on weight (default weight = 1)
-	weight := node.Weight
-	if weight <= 0 {
-		weight = 1
+	// Apply the cap before placing the node, so it is never placed at its
+	// uncapped count only to be rebuilt
+	hr.nodes[node.ID] = node
+	if !hr.rescaleLocked() {
+		count, _ := hr.virtualCount(node)
+		hr.addVirtualNodesLocked(node, 0, count)
 	}
-	virtualCount := hr.virtualReplicas * weight
+	hr.advanceGenerationLocked()
+	hr.emitLocked(EventNodeAdded, node)
+
+	return nil
+}
+
+// replicaSpan is a node's replica indexes in [from, to)
+type replicaSpan struct {
+	node     *Node
+	from, to int
+}
 
-	// Add virtual nodes with improved key generation
-	newVirtualNodes := make([]VirtualNode, virtualCount)
-	for i := 0; i < virtualCount; i++ {
-		virtualKey := hr.generateVirtualKey(node.ID, i)
-		hash := hr.hash(virtualKey)
-		newVirtualNodes[i] = VirtualNode{
-			Hash: hash,
-			Node: node,
+// addVirtualNodesLocked adds the node's virtual nodes with replica indexes in
+// [from, to). Callers must hold the write lock.
+func (hr *HashRing) addVirtualNodesLocked(node *Node, from, to int) {
+	hr.addReplicasLocked(replicaSpan{node: node, from: from, to: to})
+}
+
+// addReplicasLocked adds the virtual nodes of every span with a single sort
+// and merge, so batch changes don't merge into the ring once per node.
+// Callers must hold the write lock.
+func (hr *HashRing) addReplicasLocked(spans ...replicaSpan) {
+	// Build the new virtual nodes in the reusable scratch buffer
+	count := 0
+	for _, span := range spans {
+		count += span.to - span.from
+	}
+	if cap(hr.scratch) < count {
+		hr.scratch = make([]VirtualNode, count)
+	}
+	added := hr.scratch[:0]
+	var buf []byte
+	for _, span := range spans {
+		for i := span.from; i < span.to; i++ {
+			buf = hr.appendVirtualKey(buf[:0], span.node.ID, i)
+			added = append(added, VirtualNode{
+				Hash: hr.hashBytes(buf),
+				Node: span.node,
+			})
 		}
 	}
-
-	// Merge and sort - more efficient than appending and sorting entire slice
-	hr.virtualNodes = append(hr.virtualNodes, newVirtualNodes...)
-	sort.Slice(hr.virtualNodes, func(i, j int) bool {
-		return hr.virtualNodes[i].Hash < hr.virtualNodes[j].Hash
+	sort.SliceStable(added, func(i, j int) bool {
+		return added[i].Hash < added[j].Hash
 	})
 
-	return nil
+	hr.mergeVirtualNodes(added)
+	clear(added) // Don't keep the nodes reachable from the scratch buffer
+}
+
+// mergeVirtualNodes merges sorted virtual nodes into the ring in place. Merging
+// from the back reuses the ring slice's spare capacity, which is retained
+// across removals, so autoscaling add/remove cycles don't reallocate or
+// re-sort the whole ring.
+func (hr *HashRing) mergeVirtualNodes(added []VirtualNode) {
+	i := len(hr.virtualNodes) - 1
+	hr.virtualNodes = append(hr.virtualNodes, added...)
+
+	for j, w := len(added)-1, len(hr.virtualNodes)-1; j >= 0; w-- {
+		if i >= 0 && hr.virtualNodes[i].Hash > added[j].Hash {
+			hr.virtualNodes[w] = hr.virtualNodes[i]
+			i--
+		} else {
+			hr.virtualNodes[w] = added[j]
+			j--
+		}
+	}
 }
 
 // RemoveNode removes a node from the hash ring
 func (hr *HashRing) RemoveNode(nodeID string) error {
+	if hr.hooks == nil {
+		_, err := hr.removeNode(nodeID)
+		return err
+	}
+
+	start := hr.hooks.begin(OpRemoveNode, nodeID)
+	node, err := hr.removeNode(nodeID)
+	hr.hooks.end(OpRemoveNode, nodeID, node, err, start)
+	return err
+}
+
+// removeNode is RemoveNode without hooks, returning the removed node
+func (hr *HashRing) removeNode(nodeID string) (*Node, error) {
 	if strings.TrimSpace(nodeID) == "" {
-		return ErrInvalidNodeID
+		return nil, ErrInvalidNodeID
 	}
 
 	hr.mu.Lock()
 	defer hr.mu.Unlock()
 
-	if _, exists := hr.nodes[nodeID]; !exists {
-		return ErrNodeNotFound
+	node, exists := hr.nodes[nodeID]
+	if !exists {
+		return nil, ErrNodeNotFound
 	}
 
-	delete(hr.nodes, nodeID)
+	hr.removeNodeLocked(node)
+	delete(hr.states, nodeID)
+	delete(hr.drains, nodeID)
+	if hr.loads != nil {
+		hr.loads.forget(nodeID)
+	}
+	hr.advanceGenerationLocked()
+	hr.emitLocked(EventNodeRemoved, node)
 
-	// Remove virtual nodes in-place for better performance
+	return node, nil
+}
+
+// removeNodeLocked removes a node and its virtual nodes. Callers must hold the
+// write lock.
+func (hr *HashRing) removeNodeLocked(node *Node) {
+	delete(hr.nodes, node.ID)
+	if !hr.rescaleLocked() {
+		hr.dropVirtualNodesLocked(node)
+	}
+
+	if hr.sampler != nil {
+		hr.sampler.forget(node.ID)
+	}
+}
+
+// dropVirtualNodesLocked removes the virtual nodes of nodes in a single pass.
+// Callers must hold the write lock.
+func (hr *HashRing) dropVirtualNodesLocked(nodes ...*Node) {
+	drop := make(map[*Node]bool, len(nodes))
+	for _, node := range nodes {
+		drop[node] = true
+	}
+
+	// Remove virtual nodes in-place for better performance. Virtual nodes
+	// reference their node by pointer, so no ID comparisons are needed.
 	writeIndex := 0
 	for readIndex := 0; readIndex < len(hr.virtualNodes); readIndex++ {
-		if hr.virtualNodes[readIndex].Node.ID != nodeID {
+		if !drop[hr.virtualNodes[readIndex].Node] {
 			hr.virtualNodes[writeIndex] = hr.virtualNodes[readIndex]
 			writeIndex++
 		}
 	}
+	// Keep the capacity for future additions but drop stale node pointers
+	clear(hr.virtualNodes[writeIndex:])
 	hr.virtualNodes = hr.virtualNodes[:writeIndex]
+}
+
+// UpdateNodeWeight changes a node's weight in place. Virtual nodes are indexed
+// by replica number, so only the replicas added or dropped by the change move
+// keys; re-adding the node would move nothing more but briefly drop it. The
+// ring stores an updated copy of the node, and earlier *Node values returned
+// by lookups keep their old weight. Nodes in a capacity class take their
+// weight from the class and are re-tuned with SetClassWeight instead.
+func (hr *HashRing) UpdateNodeWeight(nodeID string, weight int) error {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	old, exists := hr.nodes[nodeID]
+	if !exists {
+		return ErrNodeNotFound
+	}
+	if old.Class != "" {
+		return fmt.Errorf("node %s takes its weight from capacity class %s", nodeID, old.Class)
+	}
+
+	updated := *old
+	updated.Weight = weight
+	if err := updated.Validate(); err != nil {
+		return fmt.Errorf("invalid node: %w", err)
+	}
+	newCount, err := hr.virtualCount(&updated)
+	if err != nil {
+		return err
+	}
+	oldCount, _ := hr.virtualCount(old)
+
+	hr.nodes[nodeID] = &updated
+	if !hr.rescaleLocked() {
+		hr.resizeNodeLocked(old, &updated, oldCount, newCount)
+	}
+	hr.advanceGenerationLocked()
+	hr.emitLocked(EventNodeUpdated, &updated)
 
 	return nil
 }
 
+// resizeNodeLocked changes a node's replica count from oldCount to newCount,
+// pointing its remaining virtual nodes at updated
+func (hr *HashRing) resizeNodeLocked(old, updated *Node, oldCount, newCount int) {
+	nodeID := old.ID
+
+	// Replicas past the new count are dropped; a node can own the same hash
+	// more than once, so they are counted per hash
+	dropped := make(map[uint64]int, max(oldCount-newCount, 0))
+	if newCount < oldCount {
+		var buf []byte
+		for i := newCount; i < oldCount; i++ {
+			buf = hr.appendVirtualKey(buf[:0], nodeID, i)
+			dropped[hr.hashBytes(buf)]++
+		}
+	}
+
+	writeIndex := 0
+	for _, vnode := range hr.virtualNodes {
+		if vnode.Node == old {
+			if dropped[vnode.Hash] > 0 {
+				dropped[vnode.Hash]--
+				continue
+			}
+			vnode.Node = updated
+		}
+		hr.virtualNodes[writeIndex] = vnode
+		writeIndex++
+	}
+	clear(hr.virtualNodes[writeIndex:])
+	hr.virtualNodes = hr.virtualNodes[:writeIndex]
+
+	if newCount > oldCount {
+		hr.addVirtualNodesLocked(updated, oldCount, newCount)
+	}
+}
+
 // GetNode returns the node responsible for the given key
 func (hr *HashRing) GetNode(key string) (*Node, error) {
+	if hr.hooks == nil {
+		return hr.getNode(key)
+	}
+
+	start := hr.hooks.begin(OpGetNode, key)
+	node, err := hr.getNode(key)
+	hr.hooks.end(OpGetNode, key, node, err, start)
+	return node, err
+}
+
+// getNode is GetNode without hooks
+func (hr *HashRing) getNode(key string) (*Node, error) {
 	if key == "" {
-		return nil, errors.New("key cannot be empty")
+		if node, handled, err := hr.routeEmptyKey(); handled {
+			return node, err
+		}
 	}
 
 	hr.mu.RLock()
 	defer hr.mu.RUnlock()
 
 	if len(hr.virtualNodes) == 0 {
-		return nil, errors.New("no nodes available in the ring")
+		if hr.fallback != nil {
+			return hr.fallback, nil
+		}
+		return nil, ErrEmptyRing
 	}
 
-	hash := hr.hash(key)
+	node := hr.virtualNodes[hr.search(hr.keyHashLocked(key))].Node
+	if hr.sampler != nil {
+		hr.sampler.record(node.ID, key)
+	}
 
+	return node, nil
+}
+
+// GetOwnerID returns the ID of the node responsible for the given key. It
+// doesn't hand out the internal *Node and doesn't allocate, which makes it the
+// cheapest lookup for callers that only route by node ID.
+func (hr *HashRing) GetOwnerID(key string) (string, error) {
+	if key == "" {
+		if node, handled, err := hr.routeEmptyKey(); handled {
+			if err != nil {
+				return "", err
+			}
+			return node.ID, nil
+		}
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		if hr.fallback != nil {
+			return hr.fallback.ID, nil
+		}
+		return "", ErrEmptyRing
+	}
+
+	id := hr.virtualNodes[hr.search(hr.keyHashLocked(key))].Node.ID
+	if hr.sampler != nil {
+		hr.sampler.record(id, key)
+	}
+
+	return id, nil
+}
+
+// search returns the index of the virtual node responsible for the given hash.
+// The ring must not be empty.
+func (hr *HashRing) search(hash uint64) int {
+	return searchVirtualNodes(hr.virtualNodes, hash)
+}
+
+// searchVirtualNodes returns the index of the virtual node responsible for the
+// given hash in a sorted, non-empty slice of virtual nodes
+func searchVirtualNodes(vnodes []VirtualNode, hash uint64) int {
 	// Binary search for the first virtual node with hash >= key hash
-	idx := sort.Search(len(hr.virtualNodes), func(i int) bool {
-		return hr.virtualNodes[i].Hash >= hash
+	idx := sort.Search(len(vnodes), func(i int) bool {
+		return vnodes[i].Hash >= hash
 	})
 
 	// If no node found, wrap around to the first node
-	if idx == len(hr.virtualNodes) {
+	if idx == len(vnodes) {
 		idx = 0
 	}
 
-	return hr.virtualNodes[idx].Node, nil
+	return idx
 }
 
 // GetNodes returns the N nodes responsible for the given key (for replication)
 func (hr *HashRing) GetNodes(key string, count int) ([]*Node, error) {
-	if key == "" {
-		return nil, errors.New("key cannot be empty")
-	}
 	if count <= 0 {
 		return nil, ErrInvalidCount
 	}
 
+	nodes, err := hr.GetNodesAppend(make([]*Node, 0, min(count, hr.Size())), key, count)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// GetNodesAppend appends the N nodes responsible for the given key to dst and
+// returns the extended slice. Reusing dst across calls makes replicated
+// lookups allocation-free for typical replica counts.
+func (hr *HashRing) GetNodesAppend(dst []*Node, key string, count int) ([]*Node, error) {
+	if hr.hooks == nil {
+		return hr.getNodesAppend(dst, key, count)
+	}
+
+	start := hr.hooks.begin(OpGetNodes, key)
+	nodes, err := hr.getNodesAppend(dst, key, count)
+	var first *Node
+	if err == nil {
+		first = nodes[len(dst)]
+	}
+	hr.hooks.end(OpGetNodes, key, first, err, start)
+	return nodes, err
+}
+
+// getNodesAppend is GetNodesAppend without hooks
+func (hr *HashRing) getNodesAppend(dst []*Node, key string, count int) ([]*Node, error) {
+	if count <= 0 {
+		return dst, ErrInvalidCount
+	}
+	if key == "" {
+		if node, handled, err := hr.routeEmptyKey(); handled {
+			if err != nil {
+				return dst, err
+			}
+			return append(dst, node), nil
+		}
+	}
+
 	hr.mu.RLock()
 	defer hr.mu.RUnlock()
 
 	if len(hr.virtualNodes) == 0 {
-		return nil, errors.New("no nodes available in the ring")
+		if hr.fallback != nil {
+			return append(dst, hr.fallback), nil
+		}
+		return dst, ErrEmptyRing
 	}
 
-	hash := hr.hash(key)
-	nodes := make([]*Node, 0, count)
+	start := len(dst)
+	dst = hr.appendNodesLocked(dst, hr.keyHashLocked(key), count)
+	nodes := dst[start:]
 
-	// Optimize for small rings: use slice-based approach instead of map
-	var seen map[string]bool
-	var seenSlice []string
+	if hr.sampler != nil {
+		hr.sampler.record(nodes[0].ID, key)
+	}
 
-	if len(hr.nodes) <= 10 {
-		// For small rings, use slice-based deduplication (more memory efficient)
-		seenSlice = make([]string, 0, len(hr.nodes))
-	} else {
-		// For larger rings, use map-based deduplication (faster lookup)
-		seen = make(map[string]bool, len(hr.nodes))
+	// Reorder replicas so heavier ones tend to be tried first, then so the
+	// nearest one is
+	if hr.weightedOrder {
+		hr.sortByWeightLocked(nodes, key)
 	}
+	SortByProximity(nodes, hr.proximity...)
 
-	// Find starting position
-	idx := sort.Search(len(hr.virtualNodes), func(i int) bool {
-		return hr.virtualNodes[i].Hash >= hash
-	})
+	return dst, nil
+}
 
-	// Collect unique nodes
-	uniqueNodesFound := 0
-	for len(nodes) < count && uniqueNodesFound < len(hr.nodes) {
-		if idx >= len(hr.virtualNodes) {
+// maxScanDedup is the largest replica count deduplicated by scanning the
+// nodes found so far; larger walks use a map
+const maxScanDedup = 16
+
+// collectNodesLocked walks the ring clockwise from hash and returns up to count
+// distinct nodes. Callers must hold hr.mu and ensure the ring is not empty.
+func (hr *HashRing) collectNodesLocked(hash uint64, count int) []*Node {
+	return hr.appendNodesLocked(make([]*Node, 0, min(count, len(hr.nodes))), hash, count)
+}
+
+// appendNodesLocked is collectNodesLocked appending to dst. Callers must hold
+// hr.mu and ensure the ring is not empty.
+func (hr *HashRing) appendNodesLocked(dst []*Node, hash uint64, count int) []*Node {
+	return appendReplicas(dst, hr.virtualNodes, len(hr.nodes), hash, count)
+}
+
+// appendReplicas walks non-empty vnodes clockwise from hash and appends up to
+// count of their nodeCount distinct nodes to dst
+func appendReplicas(dst []*Node, vnodes []VirtualNode, nodeCount int, hash uint64, count int) []*Node {
+	start := len(dst)
+	limit := min(count, nodeCount)
+
+	// Small replica sets are deduplicated against the nodes already found,
+	// which needs no extra memory; large ones use a map
+	var seen map[*Node]bool
+	if limit > maxScanDedup {
+		seen = make(map[*Node]bool, limit)
+	}
+
+	idx := searchVirtualNodes(vnodes, hash)
+	for len(dst)-start < limit {
+		if idx >= len(vnodes) {
 			idx = 0
 		}
 
-		node := hr.virtualNodes[idx].Node
+		node := vnodes[idx].Node
+		idx++
 
-		// Check if we've seen this node before
-		var alreadySeen bool
 		if seen != nil {
-			alreadySeen = seen[node.ID]
-			if !alreadySeen {
-				seen[node.ID] = true
-				uniqueNodesFound++
-			}
-		} else {
-			// Linear search in slice (efficient for small slices)
-			for _, seenID := range seenSlice {
-				if seenID == node.ID {
-					alreadySeen = true
-					break
-				}
-			}
-			if !alreadySeen {
-				seenSlice = append(seenSlice, node.ID)
-				uniqueNodesFound++
+			if seen[node] {
+				continue
 			}
+			seen[node] = true
+		} else if containsNode(dst[start:], node) {
+			continue
 		}
 
-		if !alreadySeen {
-			nodes = append(nodes, node)
-		}
-		idx++
+		dst = append(dst, node)
 	}
 
-	return nodes, nil
+	return dst
+}
+
+// containsNode reports whether nodes contains node
+func containsNode(nodes []*Node, node *Node) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
 }
 
 // GetAllNodes returns all nodes in the ring, sorted by ID for deterministic results
@@ -312,17 +856,12 @@ func (hr *HashRing) GetAllNodes() []*Node {
 	hr.mu.RLock()
 	defer hr.mu.RUnlock()
 
-	nodes := make([]*Node, 0, len(hr.nodes))
-	for _, node := range hr.nodes {
-		nodes = append(nodes, node)
-	}
-
-	// Sort by ID for deterministic results (useful for testing/debugging)
-	sort.Slice(nodes, func(i, j int) bool {
-		return nodes[i].ID < nodes[j].ID
-	})
+	return hr.sortedNodesLocked()
+}
 
-	return nodes
+// sortedNodesLocked returns all nodes sorted by ID. Callers must hold hr.mu.
+func (hr *HashRing) sortedNodesLocked() []*Node {
+	return slices.Clone(hr.sortedViewLocked())
 }
 
 // GetNodeByID returns a node by its ID
@@ -363,6 +902,15 @@ func (hr *HashRing) Size() int {
 	return len(hr.nodes)
 }
 
+// Generation returns a counter that increases with every membership change,
+// so callers can cheaply detect that the ring has changed
+func (hr *HashRing) Generation() uint64 {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.generation
+}
+
 // VirtualSize returns the number of virtual nodes in the ring
 func (hr *HashRing) VirtualSize() int {
 	hr.mu.RLock()
@@ -371,7 +919,9 @@ func (hr *HashRing) VirtualSize() int {
 	return len(hr.virtualNodes)
 }
 
-// GetLoadDistribution returns the distribution of keys across nodes
+// GetLoadDistribution returns the distribution of keys across nodes. Empty
+// keys are skipped under EmptyKeySkip, the default, fail under EmptyKeyError
+// and are counted under the other policies.
 func (hr *HashRing) GetLoadDistribution(keys []string) (map[string]int, error) {
 	if keys == nil {
 		return nil, errors.New("keys slice cannot be nil")
@@ -380,12 +930,12 @@ func (hr *HashRing) GetLoadDistribution(keys []string) (map[string]int, error) {
 	distribution := make(map[string]int)
 
 	for _, key := range keys {
-		if key == "" {
-			continue // Skip empty keys
+		if hr.skipsEmptyKey(key) {
+			continue
 		}
 		node, err := hr.GetNode(key)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get node for key %s: %w", key, err)
+			return nil, fmt.Errorf("failed to get node for key %q: %w", key, err)
 		}
 		if node != nil {
 			distribution[node.ID]++
@@ -395,7 +945,69 @@ func (hr *HashRing) GetLoadDistribution(keys []string) (map[string]int, error) {
 	return distribution, nil
 }
 
-// GetRingInfo returns detailed information about the ring
+// GetReplicaLoadDistribution counts each key against every one of its
+// replicas, the first replicas nodes GetNodes returns, rather than only its
+// primary. In replicated deployments this is the actual storage load. Empty
+// keys are treated as by GetLoadDistribution.
+func (hr *HashRing) GetReplicaLoadDistribution(keys []string, replicas int) (map[string]int, error) {
+	if keys == nil {
+		return nil, errors.New("keys slice cannot be nil")
+	}
+	if replicas <= 0 {
+		return nil, ErrInvalidCount
+	}
+
+	distribution := make(map[string]int)
+	var nodes []*Node
+	for _, key := range keys {
+		if hr.skipsEmptyKey(key) {
+			continue
+		}
+		var err error
+		nodes, err = hr.GetNodesAppend(nodes[:0], key, replicas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nodes for key %q: %w", key, err)
+		}
+		for _, node := range nodes {
+			distribution[node.ID]++
+		}
+	}
+
+	return distribution, nil
+}
+
+// GetLoadDistributionStream aggregates the key distribution of keys read from
+// a channel until it is closed, so analyses of very large key sets never hold
+// the keys in memory. Empty keys are treated as by GetLoadDistribution. If
+// ctx is done first, the partial distribution is returned with ctx's error.
+func (hr *HashRing) GetLoadDistributionStream(ctx context.Context, keys <-chan string) (map[string]int, error) {
+	if keys == nil {
+		return nil, errors.New("keys channel cannot be nil")
+	}
+
+	distribution := make(map[string]int)
+	for {
+		select {
+		case <-ctx.Done():
+			return distribution, ctx.Err()
+		case key, ok := <-keys:
+			if !ok {
+				return distribution, nil
+			}
+			if hr.skipsEmptyKey(key) {
+				continue
+			}
+			id, err := hr.GetOwnerID(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get node for key %q: %w", key, err)
+			}
+			distribution[id]++
+		}
+	}
+}
+
+// GetRingInfo returns detailed information about the ring. Stats returns the
+// same values, and more, as a typed struct.
 func (hr *HashRing) GetRingInfo() map[string]interface{} {
 	hr.mu.RLock()
 	defer hr.mu.RUnlock()
@@ -410,17 +1022,65 @@ func (hr *HashRing) GetRingInfo() map[string]interface{} {
 		info["avg_virtual_per_physical"] = float64(len(hr.virtualNodes)) / float64(len(hr.nodes))
 	}
 
-	// Add hash function type
-	switch hr.hasher.(type) {
+	info["hash_function"] = hasherName(hr.hasher)
+
+	return info
+}
+
+// hasherName returns the display name of a hash function
+func hasherName(hasher HashFunction) string {
+	// A seeded hasher or a remapped digest can't be recreated from its name
+	// alone, and the seed is a secret
+	if h, ok := hasher.(seedable); ok && h.seed() != 0 {
+		return "Custom"
+	}
+	if h, ok := hasher.(digestHasher); ok {
+		if mapping, _ := h.digestMapping(); mapping != (DigestMapping{}) {
+			return "Custom"
+		}
+	}
+
+	switch hasher.(type) {
 	case *FNVHasher:
-		info["hash_function"] = "FNV-1a"
+		return "FNV-1a"
 	case *SHA256Hasher:
-		info["hash_function"] = "SHA-256"
+		return "SHA-256"
+	case *MD5Hasher:
+		return "MD5"
+	case *SHA1Hasher:
+		return "SHA-1"
+	case *XXHasher:
+		return "xxHash64"
+	case *Murmur3Hasher:
+		return "Murmur3"
+	case *CRC32Hasher:
+		return "CRC32"
 	default:
-		info["hash_function"] = "Custom"
+		return "Custom"
 	}
+}
 
-	return info
+// hasherByName returns the built-in hash function with the given display
+// name, or nil for custom hash functions
+func hasherByName(name string) HashFunction {
+	switch name {
+	case "FNV-1a":
+		return &FNVHasher{}
+	case "SHA-256":
+		return &SHA256Hasher{}
+	case "MD5":
+		return &MD5Hasher{}
+	case "SHA-1":
+		return &SHA1Hasher{}
+	case "xxHash64":
+		return &XXHasher{}
+	case "Murmur3":
+		return &Murmur3Hasher{}
+	case "CRC32":
+		return &CRC32Hasher{}
+	default:
+		return nil
+	}
 }
 
 // ValidateRing performs a comprehensive validation of the ring state
@@ -428,6 +1088,11 @@ func (hr *HashRing) ValidateRing() error {
 	hr.mu.RLock()
 	defer hr.mu.RUnlock()
 
+	return hr.validateLocked()
+}
+
+// validateLocked runs the checks of ValidateRing. Callers must hold hr.mu.
+func (hr *HashRing) validateLocked() error {
 	// Check if virtual nodes are properly sorted
 	for i := 1; i < len(hr.virtualNodes); i++ {
 		if hr.virtualNodes[i-1].Hash > hr.virtualNodes[i].Hash {
@@ -447,3 +1112,54 @@ func (hr *HashRing) ValidateRing() error {
 
 	return nil
 }
+
+// ValidateRingDeep performs the checks of ValidateRing under the write lock and
+// additionally verifies that every node has exactly the virtual nodes its
+// weight and the replica setting call for, at the expected hash positions, and
+// that virtual nodes reference the registered node instance. This catches
+// partial updates that leave the ring sorted but inconsistent.
+func (hr *HashRing) ValidateRingDeep() error {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	if err := hr.validateLocked(); err != nil {
+		return err
+	}
+
+	// Count the hash positions actually present for each node
+	actual := make(map[string]map[uint64]int, len(hr.nodes))
+	for _, vnode := range hr.virtualNodes {
+		if vnode.Node != hr.nodes[vnode.Node.ID] {
+			return fmt.Errorf("virtual node references a stale instance of node %s", vnode.Node.ID)
+		}
+		if actual[vnode.Node.ID] == nil {
+			actual[vnode.Node.ID] = make(map[uint64]int)
+		}
+		actual[vnode.Node.ID][vnode.Hash]++
+	}
+
+	for id, node := range hr.nodes {
+		expected, err := hr.virtualCount(node)
+		if err != nil {
+			return fmt.Errorf("node %s: %w", id, err)
+		}
+
+		positions := actual[id]
+		found := 0
+		for _, count := range positions {
+			found += count
+		}
+		if found != expected {
+			return fmt.Errorf("node %s has %d virtual nodes, expected %d", id, found, expected)
+		}
+
+		for i, hash := range hr.virtualHashes(id, expected) {
+			if positions[hash] == 0 {
+				return fmt.Errorf("node %s is missing virtual node %d", id, i)
+			}
+			positions[hash]--
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package consistenthashing
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
+	"unsafe"
 )
 
 func TestNewHashRing(t *testing.T) {
@@ -134,6 +136,11 @@ func TestNodeValidation(t *testing.T) {
 			node:        &Node{ID: "test", Host: "localhost", Port: 65536},
 			expectError: ErrInvalidNodePort,
 		},
+		{
+			name:        "weight too high",
+			node:        &Node{ID: "test", Host: "localhost", Port: 8080, Weight: MaxNodeWeight + 1},
+			expectError: ErrInvalidNodeWeight,
+		},
 	}
 
 	for _, tt := range tests {
@@ -876,3 +883,422 @@ func BenchmarkConcurrentGetNode(b *testing.B) {
 		}
 	})
 }
+
+func TestGeneration(t *testing.T) {
+	ring, err := NewHashRing(3)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if ring.Generation() != 0 {
+		t.Errorf("Expected generation 0 for new ring, got %d", ring.Generation())
+	}
+
+	node := &Node{ID: "node1", Host: "localhost", Port: 8080}
+	ring.AddNode(node)
+	if ring.Generation() != 1 {
+		t.Errorf("Expected generation 1 after add, got %d", ring.Generation())
+	}
+
+	// Duplicate adds and failed removals are not membership changes
+	ring.AddNode(node)
+	ring.RemoveNode("nonexistent")
+	if ring.Generation() != 1 {
+		t.Errorf("Expected generation to stay 1, got %d", ring.Generation())
+	}
+
+	ring.RemoveNode("node1")
+	if ring.Generation() != 2 {
+		t.Errorf("Expected generation 2 after removal, got %d", ring.Generation())
+	}
+}
+
+func TestValidateRingDeep(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	node1 := &Node{ID: "node1", Host: "localhost", Port: 8080}
+	node2 := &Node{ID: "node2", Host: "localhost", Port: 8081, Weight: 3}
+	ring.AddNode(node1)
+	ring.AddNode(node2)
+
+	if err := ring.ValidateRingDeep(); err != nil {
+		t.Errorf("Ring should be valid, got error: %v", err)
+	}
+
+	// Changing the weight behind the ring's back leaves the vnodes stale
+	node2.Weight = 2
+	if err := ring.ValidateRingDeep(); err == nil {
+		t.Error("Expected deep validation to catch a weight/vnode mismatch")
+	}
+	if err := ring.ValidateRing(); err != nil {
+		t.Errorf("Shallow validation should not notice the mismatch, got %v", err)
+	}
+	node2.Weight = 3
+
+	// Drop a single virtual node to simulate a partial removal
+	ring.mu.Lock()
+	ring.virtualNodes = ring.virtualNodes[1:]
+	ring.mu.Unlock()
+	if err := ring.ValidateRingDeep(); err == nil {
+		t.Error("Expected deep validation to catch a missing virtual node")
+	}
+}
+
+func TestVirtualHashesMatchVirtualKeys(t *testing.T) {
+	for _, hasher := range []HashFunction{&FNVHasher{}, &SHA256Hasher{}} {
+		ring, err := NewHashRing(5, WithHashFunction(hasher))
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+
+		for i, hash := range ring.virtualHashes("node1", 5) {
+			if expected := hasher.Hash(ring.generateVirtualKey("node1", i)); hash != expected {
+				t.Errorf("Virtual hash %d does not match hash of its key", i)
+			}
+		}
+	}
+}
+
+func TestAddRemoveCyclesReuseStorage(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i, Weight: i%3 + 1})
+	}
+	if err := ring.ValidateRingDeep(); err != nil {
+		t.Fatalf("Ring invalid after merging additions: %v", err)
+	}
+
+	scaled := &Node{ID: "autoscaled", Host: "localhost", Port: 9000, Weight: 2}
+	ring.AddNode(scaled)
+	ring.RemoveNode("autoscaled")
+	capacity := cap(ring.virtualNodes)
+
+	// Once the ring has grown, repeated add/remove cycles reuse its storage
+	for cycle := 0; cycle < 5; cycle++ {
+		ring.AddNode(scaled)
+		if err := ring.ValidateRingDeep(); err != nil {
+			t.Fatalf("Ring invalid in cycle %d: %v", cycle, err)
+		}
+		ring.RemoveNode("autoscaled")
+		if cap(ring.virtualNodes) != capacity {
+			t.Errorf("Expected ring storage to be reused, capacity changed from %d to %d", capacity, cap(ring.virtualNodes))
+		}
+	}
+
+	// Removed nodes must not stay reachable through spare capacity
+	for _, vnode := range ring.virtualNodes[len(ring.virtualNodes):cap(ring.virtualNodes)] {
+		if vnode.Node != nil {
+			t.Fatal("Expected spare capacity to be cleared")
+		}
+	}
+}
+
+func TestGetOwnerID(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if _, err := ring.GetOwnerID("key"); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	if _, err := ring.GetOwnerID(""); err == nil {
+		t.Error("Expected error for empty key")
+	}
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		node, _ := ring.GetNode(key)
+		id, err := ring.GetOwnerID(key)
+		if err != nil {
+			t.Fatalf("Failed to get owner ID: %v", err)
+		}
+		if id != node.ID {
+			t.Errorf("Key %s: GetOwnerID returned %s, GetNode returned %s", key, id, node.ID)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		ring.GetOwnerID("benchmark-key")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %.1f", allocs)
+	}
+}
+
+func TestGetNodesAppend(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	// More nodes than maxScanDedup so both deduplication paths are covered
+	for i := 0; i < 30; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	sentinel := &Node{ID: "sentinel"}
+	buf := make([]*Node, 0, 64)
+	for _, count := range []int{1, 3, maxScanDedup, 25, 40} {
+		for i := 0; i < 50; i++ {
+			key := fmt.Sprintf("key%d", i)
+			expected, err := ring.GetNodes(key, count)
+			if err != nil {
+				t.Fatalf("Failed to get nodes: %v", err)
+			}
+
+			buf, err = ring.GetNodesAppend(append(buf[:0], sentinel), key, count)
+			if err != nil {
+				t.Fatalf("Failed to append nodes: %v", err)
+			}
+			if buf[0] != sentinel {
+				t.Fatal("Expected existing dst contents to be preserved")
+			}
+			if len(buf)-1 != len(expected) {
+				t.Fatalf("Expected %d nodes, got %d", len(expected), len(buf)-1)
+			}
+			for j, node := range expected {
+				if buf[j+1] != node {
+					t.Errorf("Key %s count %d: replica %d differs from GetNodes", key, count, j)
+				}
+			}
+		}
+	}
+
+	if _, err := ring.GetNodesAppend(nil, "", 3); err == nil {
+		t.Error("Expected error for empty key")
+	}
+	if _, err := ring.GetNodesAppend(nil, "key", 0); err != ErrInvalidCount {
+		t.Errorf("Expected ErrInvalidCount, got %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = ring.GetNodesAppend(buf[:0], "benchmark-key", 3)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations with a reused buffer, got %.1f", allocs)
+	}
+}
+
+func BenchmarkGetNodesAppend(b *testing.B) {
+	ring, _ := NewHashRing(100)
+	for i := 0; i < 10; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	buf := make([]*Node, 0, 3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = ring.GetNodesAppend(buf[:0], "benchmark-key", 3)
+	}
+}
+
+func TestUpdateNodeWeight(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i, Weight: 2})
+	}
+
+	if err := ring.UpdateNodeWeight("missing", 3); err != ErrNodeNotFound {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if err := ring.UpdateNodeWeight("node0", MaxNodeWeight+1); err == nil {
+		t.Error("Expected error for invalid weight")
+	}
+
+	keys := make([]string, 2000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+	owners := func() map[string]string {
+		result := make(map[string]string, len(keys))
+		for _, key := range keys {
+			node, _ := ring.GetNode(key)
+			result[key] = node.ID
+		}
+		return result
+	}
+
+	for _, weight := range []int{5, 1, 2} {
+		before := owners()
+		generation := ring.Generation()
+		if err := ring.UpdateNodeWeight("node0", weight); err != nil {
+			t.Fatalf("Failed to update weight to %d: %v", weight, err)
+		}
+		if ring.Generation() == generation {
+			t.Error("Expected generation to change")
+		}
+		if err := ring.ValidateRingDeep(); err != nil {
+			t.Fatalf("Ring invalid after weight update to %d: %v", weight, err)
+		}
+		if node, _ := ring.GetNodeByID("node0"); node.Weight != weight {
+			t.Errorf("Expected weight %d, got %d", weight, node.Weight)
+		}
+
+		// Keys only move to node0 when it grows, and only away from it when
+		// it shrinks
+		for key, owner := range owners() {
+			if owner == before[key] {
+				continue
+			}
+			if owner != "node0" && before[key] != "node0" {
+				t.Errorf("Key %s moved between unchanged nodes %s and %s", key, before[key], owner)
+			}
+		}
+	}
+}
+
+func TestGetLoadDistributionStream(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	if _, err := ring.GetLoadDistributionStream(context.Background(), nil); err == nil {
+		t.Error("Expected error for nil channel")
+	}
+
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+	expected, _ := ring.GetLoadDistribution(keys)
+
+	stream := make(chan string)
+	go func() {
+		defer close(stream)
+		for _, key := range keys {
+			stream <- key
+		}
+		stream <- ""
+	}()
+
+	distribution, err := ring.GetLoadDistributionStream(context.Background(), stream)
+	if err != nil {
+		t.Fatalf("Failed to stream distribution: %v", err)
+	}
+	for id, count := range expected {
+		if distribution[id] != count {
+			t.Errorf("Node %s: expected %d keys, got %d", id, count, distribution[id])
+		}
+	}
+
+	// A cancelled context returns what was aggregated so far
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ring.GetLoadDistributionStream(ctx, make(chan string)); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGetReplicaLoadDistribution(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+
+	// One replica is the primary distribution
+	primary, _ := ring.GetLoadDistribution(keys)
+	single, err := ring.GetReplicaLoadDistribution(keys, 1)
+	if err != nil {
+		t.Fatalf("Failed to get distribution: %v", err)
+	}
+	if fmt.Sprint(single) != fmt.Sprint(primary) {
+		t.Errorf("Expected %v for one replica, got %v", primary, single)
+	}
+
+	distribution, err := ring.GetReplicaLoadDistribution(keys, 3)
+	if err != nil {
+		t.Fatalf("Failed to get distribution: %v", err)
+	}
+	total := 0
+	for id, count := range distribution {
+		if count < primary[id] || count > len(keys) {
+			t.Errorf("Node %s: expected between %d and %d keys, got %d", id, primary[id], len(keys), count)
+		}
+		total += count
+	}
+	if total != 3*len(keys) {
+		t.Errorf("Expected every key counted 3 times, got %d", total)
+	}
+
+	// Asking for more replicas than nodes counts each key on every node
+	distribution, _ = ring.GetReplicaLoadDistribution(keys, 10)
+	for id, count := range distribution {
+		if count != len(keys) {
+			t.Errorf("Node %s: expected all %d keys, got %d", id, len(keys), count)
+		}
+	}
+
+	if _, err := ring.GetReplicaLoadDistribution(keys, 0); err != ErrInvalidCount {
+		t.Errorf("Expected ErrInvalidCount, got %v", err)
+	}
+}
+
+func TestNodeIDsAreShared(t *testing.T) {
+	ring, err := NewHashRing(20, WithLoadTracking(nil))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	node := &Node{ID: "node1", Host: "localhost", Port: 8080}
+	ring.AddNode(node)
+
+	// IDs built by the caller are not retained; the ring keeps the member's
+	id := string([]byte("node1"))
+	ring.SetNodeState(id, NodeDown)
+	ring.RecordLoad(id, 1)
+	ring.SetNodeCapacity(id, 10)
+
+	shared := func(s string) bool { return unsafe.StringData(s) == unsafe.StringData(node.ID) }
+	for _, vnode := range ring.virtualNodes {
+		if !shared(vnode.Node.ID) {
+			t.Fatal("Expected virtual nodes to share the node's ID")
+		}
+	}
+	for name, m := range map[string][]string{
+		"states":   keysOf(ring.states),
+		"load":     keysOf(ring.loads.load),
+		"capacity": keysOf(ring.loads.capacity),
+	} {
+		for _, k := range m {
+			if !shared(k) {
+				t.Errorf("Expected %s to be keyed by the node's own ID", name)
+			}
+		}
+	}
+}
+
+// keysOf returns the keys of a map
+func keysOf[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
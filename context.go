@@ -0,0 +1,83 @@
+package consistenthashing
+
+import "context"
+
+// contextKey namespaces the values this package stores in a context
+type contextKey int
+
+const (
+	shardKeyContextKey contextKey = iota
+	shardNodeContextKey
+)
+
+// gRPC metadata keys used to carry shard decisions between services. They
+// match the httpaffinity headers, lower-cased as gRPC requires.
+const (
+	ShardKeyMetadata  = "x-shard-key"
+	ShardNodeMetadata = "x-shard-node"
+)
+
+// WithShardKey returns a copy of ctx carrying the key a request is sharded by,
+// so code further down the call chain can route consistently without
+// re-deriving it
+func WithShardKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, shardKeyContextKey, key)
+}
+
+// ShardKeyFromContext returns the shard key stored with WithShardKey
+func ShardKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(shardKeyContextKey).(string)
+	return key, ok
+}
+
+// WithShardNode returns a copy of ctx carrying the node chosen for a request
+func WithShardNode(ctx context.Context, node *Node) context.Context {
+	return context.WithValue(ctx, shardNodeContextKey, node)
+}
+
+// ShardNodeFromContext returns the node stored with WithShardNode
+func ShardNodeFromContext(ctx context.Context) (*Node, bool) {
+	node, ok := ctx.Value(shardNodeContextKey).(*Node)
+	return node, ok && node != nil
+}
+
+// Route looks up the owner of key and returns a copy of ctx carrying both the
+// key and the chosen node, the standard way to record a shard decision before
+// calling downstream services
+func Route(ctx context.Context, ring Ring, key string) (context.Context, *Node, error) {
+	node, err := ring.GetNode(key)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return WithShardNode(WithShardKey(ctx, key), node), node, nil
+}
+
+// RoutingMetadata returns the shard key and node ID stored in ctx as
+// alternating gRPC metadata keys and values. This package doesn't depend on
+// gRPC, so a client interceptor passes them to
+// metadata.AppendToOutgoingContext before invoking the call.
+func RoutingMetadata(ctx context.Context) []string {
+	var pairs []string
+	if key, ok := ShardKeyFromContext(ctx); ok {
+		pairs = append(pairs, ShardKeyMetadata, key)
+	}
+	if node, ok := ShardNodeFromContext(ctx); ok {
+		pairs = append(pairs, ShardNodeMetadata, node.ID)
+	}
+	return pairs
+}
+
+// WithRoutingMetadata returns a copy of ctx carrying the shard key and node
+// found in incoming gRPC metadata, which converts to md directly. The node is
+// resolved against ring and dropped if it is not a member.
+func WithRoutingMetadata(ctx context.Context, ring Ring, md map[string][]string) context.Context {
+	if keys := md[ShardKeyMetadata]; len(keys) > 0 && keys[0] != "" {
+		ctx = WithShardKey(ctx, keys[0])
+	}
+	if ids := md[ShardNodeMetadata]; len(ids) > 0 && ids[0] != "" {
+		if node, err := ring.GetNodeByID(ids[0]); err == nil {
+			ctx = WithShardNode(ctx, node)
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,99 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestShardContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := ShardKeyFromContext(ctx); ok {
+		t.Error("Expected no shard key in an empty context")
+	}
+	if _, ok := ShardNodeFromContext(ctx); ok {
+		t.Error("Expected no shard node in an empty context")
+	}
+
+	node := &Node{ID: "node1", Host: "localhost", Port: 8080}
+	ctx = WithShardNode(WithShardKey(ctx, "user:1"), node)
+
+	if key, ok := ShardKeyFromContext(ctx); !ok || key != "user:1" {
+		t.Errorf("Expected shard key user:1, got %q (%v)", key, ok)
+	}
+	if got, ok := ShardNodeFromContext(ctx); !ok || got != node {
+		t.Errorf("Expected shard node node1, got %v (%v)", got, ok)
+	}
+
+	// A nil node is treated as absent
+	if _, ok := ShardNodeFromContext(WithShardNode(ctx, nil)); ok {
+		t.Error("Expected nil shard node to be reported as absent")
+	}
+}
+
+func TestRoute(t *testing.T) {
+	hr, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if _, _, err := Route(context.Background(), hr, "user:1"); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected ErrEmptyRing on an empty ring, got %v", err)
+	}
+
+	hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	hr.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+
+	ctx, node, err := Route(context.Background(), hr, "user:1")
+	if err != nil {
+		t.Fatalf("Failed to route: %v", err)
+	}
+	want, _ := hr.GetNode("user:1")
+	if node != want {
+		t.Errorf("Expected %s, got %s", want.ID, node.ID)
+	}
+	if got, _ := ShardNodeFromContext(ctx); got != node {
+		t.Errorf("Expected context to carry %s, got %v", node.ID, got)
+	}
+	if key, _ := ShardKeyFromContext(ctx); key != "user:1" {
+		t.Errorf("Expected context to carry user:1, got %q", key)
+	}
+}
+
+func TestRoutingMetadata(t *testing.T) {
+	hr, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+
+	if pairs := RoutingMetadata(context.Background()); len(pairs) != 0 {
+		t.Errorf("Expected no metadata for an empty context, got %v", pairs)
+	}
+
+	ctx, node, err := Route(context.Background(), hr, "user:1")
+	if err != nil {
+		t.Fatalf("Failed to route: %v", err)
+	}
+	pairs := RoutingMetadata(ctx)
+	if !slices.Equal(pairs, []string{ShardKeyMetadata, "user:1", ShardNodeMetadata, "node1"}) {
+		t.Errorf("Unexpected metadata %v", pairs)
+	}
+
+	// The receiving side restores both from the metadata
+	md := map[string][]string{ShardKeyMetadata: {"user:1"}, ShardNodeMetadata: {"node1"}}
+	received := WithRoutingMetadata(context.Background(), hr, md)
+	if key, _ := ShardKeyFromContext(received); key != "user:1" {
+		t.Errorf("Expected shard key user:1, got %q", key)
+	}
+	if got, ok := ShardNodeFromContext(received); !ok || got != node {
+		t.Errorf("Expected shard node node1, got %v", got)
+	}
+
+	// Unknown nodes are dropped
+	md[ShardNodeMetadata] = []string{"gone"}
+	if _, ok := ShardNodeFromContext(WithRoutingMetadata(context.Background(), hr, md)); ok {
+		t.Error("Expected an unknown node to be dropped")
+	}
+}
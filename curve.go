@@ -0,0 +1,39 @@
+package consistenthashing
+
+import "math"
+
+// WeightCurve maps a node's weight to its number of virtual nodes, given the
+// ring's virtual replica setting. It must return a positive count and should
+// not decrease as the weight grows.
+type WeightCurve func(replicas, weight int) int
+
+// LinearWeights gives each node replicas × weight virtual nodes. It is the
+// default, and makes ownership proportional to weight.
+func LinearWeights(replicas, weight int) int {
+	return int(min(int64(replicas)*int64(weight), math.MaxInt32+1))
+}
+
+// SqrtWeights gives each node replicas × √weight virtual nodes, so very heavy
+// nodes don't explode the ring's size and AddNode latency. Ownership grows
+// with the square root of the weight.
+func SqrtWeights(replicas, weight int) int {
+	return max(int(math.Round(float64(replicas)*math.Sqrt(float64(weight)))), 1)
+}
+
+// LogWeights gives each node replicas × (1 + log₂ weight) virtual nodes, for
+// fleets whose weights span orders of magnitude
+func LogWeights(replicas, weight int) int {
+	return max(int(math.Round(float64(replicas)*(1+math.Log2(float64(weight))))), 1)
+}
+
+// WithWeightCurve sets how node weights map to virtual node counts, instead
+// of the default LinearWeights. With a non-linear curve, nodes own shares
+// proportional to their virtual node counts rather than their weights.
+// Curves are not stored in snapshots, so pass the same curve when restoring.
+func WithWeightCurve(curve WeightCurve) Option {
+	return func(hr *HashRing) {
+		if curve != nil {
+			hr.weightCurve = curve
+		}
+	}
+}
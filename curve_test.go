@@ -0,0 +1,75 @@
+package consistenthashing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWeightCurves(t *testing.T) {
+	tests := []struct {
+		name   string
+		curve  WeightCurve
+		weight int
+		want   int
+	}{
+		{"linear", LinearWeights, 1, 100},
+		{"linear", LinearWeights, 16, 1600},
+		{"sqrt", SqrtWeights, 1, 100},
+		{"sqrt", SqrtWeights, 16, 400},
+		{"log", LogWeights, 1, 100},
+		{"log", LogWeights, 16, 500},
+	}
+	for _, tt := range tests {
+		if got := tt.curve(100, tt.weight); got != tt.want {
+			t.Errorf("%s(100, %d) = %d, want %d", tt.name, tt.weight, got, tt.want)
+		}
+	}
+}
+
+func TestWithWeightCurve(t *testing.T) {
+	hr, err := NewHashRing(100, WithWeightCurve(SqrtWeights))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	hr.AddNode(&Node{ID: "small", Host: "localhost", Port: 8080, Weight: 1})
+	hr.AddNode(&Node{ID: "huge", Host: "localhost", Port: 8081, Weight: 10000})
+	if got := hr.VirtualSize(); got != 100+10000 {
+		t.Errorf("Expected 10100 virtual nodes, got %d", got)
+	}
+
+	// Resizing follows the curve too
+	if err := hr.UpdateNodeWeight("huge", 2500); err != nil {
+		t.Fatalf("Failed to update weight: %v", err)
+	}
+	if got := hr.VirtualSize(); got != 100+5000 {
+		t.Errorf("Expected 5100 virtual nodes, got %d", got)
+	}
+	if err := hr.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+
+	// Balance is measured against the curve, not the raw weights
+	if stats := hr.BalanceStats(); stats.MaxSkew > 1.5 {
+		t.Errorf("Expected skew near 1 against the curve, got %.2f", stats.MaxSkew)
+	}
+}
+
+func TestWeightCurveOverflow(t *testing.T) {
+	hr, err := NewHashRing(math32Replicas, WithWeightCurve(LinearWeights))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	err = hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080, Weight: MaxNodeWeight})
+	if !errors.Is(err, ErrTooManyVirtualNodes) {
+		t.Errorf("Expected ErrTooManyVirtualNodes, got %v", err)
+	}
+
+	broken := func(replicas, weight int) int { return 0 }
+	hr, _ = NewHashRing(10, WithWeightCurve(broken))
+	if err := hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080}); err == nil {
+		t.Error("Expected a curve giving no virtual nodes to be rejected")
+	}
+}
+
+// math32Replicas overflows 32 bits once multiplied by MaxNodeWeight
+const math32Replicas = 1 << 20
@@ -0,0 +1,30 @@
+package consistenthashing
+
+import "slices"
+
+// Diff returns the hash ranges whose primary owner differs between the ring
+// and other, clockwise from zero, with adjacent ranges of the same owners
+// merged. During a migration from a current to a target ring these are
+// exactly the ranges to copy. Owners are compared by node ID, and both rings
+// should use the same hash function, or the ranges don't match the same keys.
+// From is the owner on this ring and To the owner on other.
+func (hr *HashRing) Diff(other *HashRing) []MovedRange {
+	if other == nil || other == hr {
+		return nil
+	}
+
+	// Copy the other ring's virtual nodes rather than holding both locks, so
+	// concurrent Diffs in opposite directions can't deadlock
+	other.mu.RLock()
+	remote := slices.Clone(other.virtualNodes)
+	other.mu.RUnlock()
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	var changes []MovedRange
+	for _, r := range divergentRanges(hr.virtualNodes, remote) {
+		changes = append(changes, MovedRange{Range: r.Range, From: r.Local, To: r.Remote})
+	}
+	return changes
+}
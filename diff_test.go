@@ -0,0 +1,78 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	current, err := NewHashRing(50, WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	target, err := NewHashRing(50, WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		node := &Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i}
+		current.AddNode(node)
+		target.AddNode(node)
+	}
+
+	if changes := current.Diff(target); len(changes) != 0 {
+		t.Errorf("Expected identical rings to have no changes, got %d", len(changes))
+	}
+	if changes := current.Diff(current); changes != nil {
+		t.Errorf("Expected no changes against itself, got %d", len(changes))
+	}
+
+	target.AddNode(&Node{ID: "node4", Host: "localhost", Port: 8084})
+	changes := current.Diff(target)
+	if len(changes) == 0 {
+		t.Fatal("Expected changes after adding a node")
+	}
+	for _, c := range changes {
+		if c.To != "node4" || c.From == "node4" {
+			t.Errorf("Expected only moves to node4, got %s -> %s", c.From, c.To)
+		}
+	}
+
+	// Every key in a changed range moves, and no other key does
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		from, _ := current.GetNode(key)
+		to, _ := target.GetNode(key)
+		hash := current.HashKey(key)
+
+		var change *MovedRange
+		for j := range changes {
+			if changes[j].Range.Contains(hash) {
+				change = &changes[j]
+				break
+			}
+		}
+		switch {
+		case change == nil && from.ID != to.ID:
+			t.Fatalf("Key %s moved from %s to %s outside the changed ranges", key, from.ID, to.ID)
+		case change != nil && (change.From != from.ID || change.To != to.ID):
+			t.Fatalf("Key %s: expected %s -> %s, got %s -> %s", key, change.From, change.To, from.ID, to.ID)
+		}
+	}
+
+	// The reverse diff swaps the owners
+	reverse := target.Diff(current)
+	if len(reverse) != len(changes) {
+		t.Fatalf("Expected %d reverse changes, got %d", len(changes), len(reverse))
+	}
+	for i := range reverse {
+		if reverse[i].Range != changes[i].Range || reverse[i].From != changes[i].To || reverse[i].To != changes[i].From {
+			t.Errorf("Expected %+v reversed, got %+v", changes[i], reverse[i])
+		}
+	}
+
+	empty, _ := NewHashRing(50)
+	if changes := empty.Diff(current); len(changes) != 1 || changes[0].From != "" {
+		t.Errorf("Expected the whole space to change owner from an empty ring, got %+v", changes)
+	}
+}
@@ -0,0 +1,131 @@
+package consistenthashing
+
+import (
+	"errors"
+	"sync"
+)
+
+// Directory maps entity IDs, such as virtual actors, to the nodes hosting
+// them. Placements come from the ring and are cached until the ring's
+// generation changes. Entities can be pinned to a node, overriding the ring
+// while that node is a member.
+type Directory struct {
+	ring *HashRing
+
+	mu         sync.RWMutex
+	cache      map[string]*Node
+	generation uint64            // Ring generation the cache reflects
+	pins       map[string]string // Entity ID to pinned node ID
+}
+
+// NewDirectory creates an empty directory over ring
+func NewDirectory(ring *HashRing) (*Directory, error) {
+	if ring == nil {
+		return nil, errors.New("ring cannot be nil")
+	}
+
+	return &Directory{
+		ring:  ring,
+		cache: make(map[string]*Node),
+		pins:  make(map[string]string),
+	}, nil
+}
+
+// Lookup returns the node hosting an entity. A pinned entity whose node has
+// left the ring falls back to its ring placement until the node returns or
+// the pin is removed.
+func (d *Directory) Lookup(entityID string) (*Node, error) {
+	if entityID == "" {
+		return nil, errors.New("entity ID cannot be empty")
+	}
+
+	d.mu.RLock()
+	pin, pinned := d.pins[entityID]
+	node, cached := d.cache[entityID]
+	fresh := d.generation == d.ring.Generation()
+	d.mu.RUnlock()
+
+	if pinned {
+		if node, err := d.ring.GetNodeByID(pin); err == nil {
+			return node, nil
+		}
+	}
+	if cached && fresh {
+		return node, nil
+	}
+
+	node, generation, err := d.ring.placement(entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if generation != d.generation {
+		if generation < d.generation {
+			// A newer placement was cached meanwhile; don't overwrite it
+			return node, nil
+		}
+		clear(d.cache)
+		d.generation = generation
+	}
+	d.cache[entityID] = node
+	return node, nil
+}
+
+// Pin places an entity on a node regardless of the ring
+func (d *Directory) Pin(entityID, nodeID string) error {
+	if entityID == "" {
+		return errors.New("entity ID cannot be empty")
+	}
+	if !d.ring.HasNode(nodeID) {
+		return ErrNodeNotFound
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pins[entityID] = nodeID
+	return nil
+}
+
+// Unpin returns an entity to its ring placement
+func (d *Directory) Unpin(entityID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.pins, entityID)
+}
+
+// Pinned returns the node an entity is pinned to, if any
+func (d *Directory) Pinned(entityID string) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	nodeID, pinned := d.pins[entityID]
+	return nodeID, pinned
+}
+
+// CachedEntities returns the number of cached placements
+func (d *Directory) CachedEntities() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.generation != d.ring.Generation() {
+		return 0
+	}
+	return len(d.cache)
+}
+
+// placement returns the node responsible for key together with the generation
+// it was computed at
+func (hr *HashRing) placement(key string) (*Node, uint64, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		return nil, hr.generation, ErrEmptyRing
+	}
+	return hr.virtualNodes[hr.search(hr.keyHashLocked(key))].Node, hr.generation, nil
+}
@@ -0,0 +1,89 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDirectory(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if _, err := NewDirectory(nil); err == nil {
+		t.Error("Expected error for nil ring")
+	}
+	dir, _ := NewDirectory(ring)
+
+	if _, err := dir.Lookup("actor-1"); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	for i := 0; i < 100; i++ {
+		entity := fmt.Sprintf("actor-%d", i)
+		node, err := dir.Lookup(entity)
+		if err != nil {
+			t.Fatalf("Failed to look up %s: %v", entity, err)
+		}
+		if expected, _ := ring.GetNode(entity); node != expected {
+			t.Errorf("Entity %s placed on %s, ring says %s", entity, node.ID, expected.ID)
+		}
+	}
+	if dir.CachedEntities() != 100 {
+		t.Errorf("Expected 100 cached placements, got %d", dir.CachedEntities())
+	}
+
+	// A ring change invalidates the cache
+	ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 9000})
+	if dir.CachedEntities() != 0 {
+		t.Errorf("Expected the cache to be invalidated, got %d entries", dir.CachedEntities())
+	}
+	for i := 0; i < 100; i++ {
+		entity := fmt.Sprintf("actor-%d", i)
+		node, _ := dir.Lookup(entity)
+		if expected, _ := ring.GetNode(entity); node != expected {
+			t.Errorf("Entity %s placed on stale node %s, ring says %s", entity, node.ID, expected.ID)
+		}
+	}
+}
+
+func TestDirectoryPinning(t *testing.T) {
+	ring, _ := NewHashRing(20)
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8081})
+	dir, _ := NewDirectory(ring)
+
+	placed, _ := dir.Lookup("actor")
+	target := "node0"
+	if placed.ID == target {
+		target = "node1"
+	}
+
+	if err := dir.Pin("actor", "missing"); err != ErrNodeNotFound {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if err := dir.Pin("actor", target); err != nil {
+		t.Fatalf("Failed to pin: %v", err)
+	}
+	if node, _ := dir.Lookup("actor"); node.ID != target {
+		t.Errorf("Expected pinned node %s, got %s", target, node.ID)
+	}
+	if nodeID, pinned := dir.Pinned("actor"); !pinned || nodeID != target {
+		t.Errorf("Expected pin to %s, got %q %v", target, nodeID, pinned)
+	}
+
+	// A pinned node that leaves the ring falls back to ring placement
+	ring.RemoveNode(target)
+	if node, _ := dir.Lookup("actor"); node.ID != placed.ID {
+		t.Errorf("Expected fallback to %s, got %s", placed.ID, node.ID)
+	}
+
+	dir.Unpin("actor")
+	if _, pinned := dir.Pinned("actor"); pinned {
+		t.Error("Expected pin to be removed")
+	}
+}
@@ -0,0 +1,193 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrLastNode is returned when draining the only node, which has no
+// successor to hand its keys to
+var ErrLastNode = errors.New("cannot drain the only node in the ring")
+
+// Errors returned when tracking drain progress
+var (
+	ErrNotDraining     = errors.New("node is not draining")
+	ErrRangeNotPending = errors.New("range is not pending for the draining node")
+)
+
+// DrainRange is one range a draining node owns and the node that takes it
+// over once the draining node leaves
+type DrainRange struct {
+	Range     HashRange
+	Successor *Node
+	// EstimatedKeys scales the node's sampled keys in the range up to its
+	// lookup count. It is zero unless the ring uses WithKeySampling.
+	EstimatedKeys int
+}
+
+// DrainPlan returns, for each range nodeID owns, the successor that will take
+// it over when the node is removed, so operators can size and sequence a
+// decommission. Ranges are in ring order.
+func (hr *HashRing) DrainPlan(nodeID string) ([]DrainRange, error) {
+	// The sampler has its own lock, so read it before taking the ring's
+	samples, _ := hr.SampledKeys()
+	lookups, _ := hr.LookupCounts()
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	node, exists := hr.nodes[nodeID]
+	if !exists {
+		return nil, ErrNodeNotFound
+	}
+	if len(hr.nodes) == 1 {
+		return nil, ErrLastNode
+	}
+
+	plan := hr.drainRangesLocked(node)
+
+	if keys := samples[nodeID]; len(keys) > 0 {
+		scale := float64(lookups[nodeID]) / float64(len(keys))
+		counts := make([]int, len(plan))
+		for _, key := range keys {
+			hash := hr.keyHashLocked(key)
+			for i := range plan {
+				if plan[i].Range.Contains(hash) {
+					counts[i]++
+					break
+				}
+			}
+		}
+		for i := range plan {
+			plan[i].EstimatedKeys = int(float64(counts[i])*scale + 0.5)
+		}
+	}
+
+	return plan, nil
+}
+
+// drainRangesLocked returns the ranges node owns, in ring order, with their
+// successors. Every virtual node inside a merged range belongs to the node,
+// so the whole range moves to the first other node past its end.
+func (hr *HashRing) drainRangesLocked(node *Node) []DrainRange {
+	var plan []DrainRange
+	for _, owned := range hr.rangesLocked() {
+		if owned.Node != node {
+			continue
+		}
+		plan = append(plan, DrainRange{
+			Range:     owned.Range,
+			Successor: hr.successorLocked(owned.Range.End, node),
+		})
+	}
+	return plan
+}
+
+// successorLocked returns the first node other than skip clockwise from the
+// virtual node at hash
+func (hr *HashRing) successorLocked(hash uint64, skip *Node) *Node {
+	idx := hr.search(hash)
+	for i := 0; i < len(hr.virtualNodes); i++ {
+		if vnode := hr.virtualNodes[(idx+i)%len(hr.virtualNodes)]; vnode.Node != skip {
+			return vnode.Node
+		}
+	}
+	return nil
+}
+
+// drainState tracks which ranges of a draining node's plan were confirmed
+// moved
+type drainState struct {
+	plan  []DrainRange
+	moved []bool
+}
+
+func (d *drainState) clone() *drainState {
+	return &drainState{plan: slices.Clone(d.plan), moved: slices.Clone(d.moved)}
+}
+
+// DrainProgress is how far a decommission has come: the ranges of the drain
+// plan confirmed moved to their successors and those still pending
+type DrainProgress struct {
+	NodeID        string
+	Moved         []DrainRange // Confirmed moved, in ring order
+	Pending       []DrainRange // Still to move, in ring order
+	MovedFraction float64      // Share of the node's hash space confirmed moved, 0 to 1
+}
+
+// Complete reports whether every range was confirmed moved
+func (p DrainProgress) Complete() bool {
+	return len(p.Pending) == 0
+}
+
+// startDrainLocked captures the node's drain plan when it starts draining,
+// keeping the progress of a drain already under way
+func (hr *HashRing) startDrainLocked(node *Node) {
+	if _, exists := hr.drains[node.ID]; exists {
+		return
+	}
+	if hr.drains == nil {
+		hr.drains = make(map[string]*drainState)
+	}
+	plan := hr.drainRangesLocked(node)
+	hr.drains[node.ID] = &drainState{plan: plan, moved: make([]bool, len(plan))}
+}
+
+// ConfirmRangeMoved records that the keys of one range of a draining node's
+// plan, as returned by DrainProgress or DrainPlan when draining started, were
+// moved to its successor
+func (hr *HashRing) ConfirmRangeMoved(nodeID string, r HashRange) error {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	if _, exists := hr.nodes[nodeID]; !exists {
+		return ErrNodeNotFound
+	}
+	drain, exists := hr.drains[nodeID]
+	if !exists {
+		return ErrNotDraining
+	}
+	for i, planned := range drain.plan {
+		if planned.Range == r && !drain.moved[i] {
+			drain.moved[i] = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrRangeNotPending, r)
+}
+
+// DrainProgress returns the progress of a draining node's decommission, for
+// dashboards showing its completion. The plan is the one in effect when the
+// node was set to NodeDraining; later membership changes don't alter it.
+// Progress is dropped when the node leaves the draining state or the ring.
+func (hr *HashRing) DrainProgress(nodeID string) (DrainProgress, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if _, exists := hr.nodes[nodeID]; !exists {
+		return DrainProgress{}, ErrNodeNotFound
+	}
+	drain, exists := hr.drains[nodeID]
+	if !exists {
+		return DrainProgress{}, ErrNotDraining
+	}
+
+	progress := DrainProgress{NodeID: nodeID}
+	var moved, total float64
+	for i, planned := range drain.plan {
+		total += planned.Range.Fraction()
+		if drain.moved[i] {
+			moved += planned.Range.Fraction()
+			progress.Moved = append(progress.Moved, planned)
+		} else {
+			progress.Pending = append(progress.Pending, planned)
+		}
+	}
+	if total > 0 {
+		progress.MovedFraction = moved / total
+	} else if len(progress.Pending) == 0 {
+		progress.MovedFraction = 1
+	}
+	return progress, nil
+}
@@ -0,0 +1,169 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestDrainPlan(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	plan, err := ring.DrainPlan("node1")
+	if err != nil {
+		t.Fatalf("Failed to plan drain: %v", err)
+	}
+
+	var fraction float64
+	for _, r := range plan {
+		if r.Successor == nil || r.Successor.ID == "node1" {
+			t.Fatalf("Unexpected successor for %s: %v", r.Range, r.Successor)
+		}
+		if r.EstimatedKeys != 0 {
+			t.Errorf("Expected no estimate without sampling, got %d", r.EstimatedKeys)
+		}
+		fraction += r.Range.Fraction()
+	}
+	estimate, _ := ring.EstimateKeys(1 << 30)
+	if math.Abs(fraction*(1<<30)-float64(estimate["node1"])) > 1 {
+		t.Errorf("Expected the plan to cover node1's share, got %f", fraction)
+	}
+
+	// Keys in each range move to the planned successor after removal
+	before := make(map[string]*DrainRange)
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if owner, _ := ring.GetOwnerID(key); owner != "node1" {
+			continue
+		}
+		hash := ring.HashKey(key)
+		for j := range plan {
+			if plan[j].Range.Contains(hash) {
+				before[key] = &plan[j]
+			}
+		}
+		if before[key] == nil {
+			t.Fatalf("Key %q owned by node1 is not in the plan", key)
+		}
+	}
+
+	ring.RemoveNode("node1")
+	for key, r := range before {
+		if owner, _ := ring.GetOwnerID(key); owner != r.Successor.ID {
+			t.Errorf("Key %q moved to %s, plan said %s", key, owner, r.Successor.ID)
+		}
+	}
+}
+
+func TestDrainPlanEstimates(t *testing.T) {
+	ring, _ := NewHashRing(50, WithKeySampling(100))
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	for i := 0; i < 3000; i++ {
+		ring.GetNode(fmt.Sprintf("key%d", i))
+	}
+
+	plan, err := ring.DrainPlan("node0")
+	if err != nil {
+		t.Fatalf("Failed to plan drain: %v", err)
+	}
+
+	lookups, _ := ring.LookupCounts()
+	total := 0
+	for _, r := range plan {
+		total += r.EstimatedKeys
+	}
+	if diff := total - int(lookups["node0"]); diff < -len(plan) || diff > len(plan) {
+		t.Errorf("Expected estimates to add up to %d lookups, got %d", lookups["node0"], total)
+	}
+}
+
+func TestDrainPlanErrors(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	if _, err := ring.DrainPlan("missing"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "solo", Host: "localhost", Port: 8080})
+	if _, err := ring.DrainPlan("solo"); !errors.Is(err, ErrLastNode) {
+		t.Errorf("Expected ErrLastNode, got %v", err)
+	}
+}
+
+func TestDrainProgress(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	if _, err := ring.DrainProgress("node1"); !errors.Is(err, ErrNotDraining) {
+		t.Errorf("Expected ErrNotDraining, got %v", err)
+	}
+	plan, _ := ring.DrainPlan("node1")
+	if err := ring.SetNodeState("node1", NodeDraining); err != nil {
+		t.Fatalf("Failed to set state: %v", err)
+	}
+
+	progress, err := ring.DrainProgress("node1")
+	if err != nil {
+		t.Fatalf("Failed to get progress: %v", err)
+	}
+	if len(progress.Pending) != len(plan) || len(progress.Moved) != 0 || progress.MovedFraction != 0 {
+		t.Fatalf("Expected %d pending ranges and nothing moved, got %+v", len(plan), progress)
+	}
+
+	// Confirm the first half of the plan
+	var fraction, total float64
+	for i, r := range plan {
+		total += r.Range.Fraction()
+		if i < len(plan)/2 {
+			if err := ring.ConfirmRangeMoved("node1", r.Range); err != nil {
+				t.Fatalf("Failed to confirm range: %v", err)
+			}
+			fraction += r.Range.Fraction()
+		}
+	}
+	if err := ring.ConfirmRangeMoved("node1", plan[0].Range); !errors.Is(err, ErrRangeNotPending) {
+		t.Errorf("Expected ErrRangeNotPending for a confirmed range, got %v", err)
+	}
+
+	// The plan is kept as membership changes
+	ring.AddNode(&Node{ID: "node9", Host: "localhost", Port: 8089})
+	progress, _ = ring.DrainProgress("node1")
+	if len(progress.Moved) != len(plan)/2 || math.Abs(progress.MovedFraction-fraction/total) > 1e-9 {
+		t.Errorf("Expected %d ranges and %.3f moved, got %d and %.3f",
+			len(plan)/2, fraction/total, len(progress.Moved), progress.MovedFraction)
+	}
+	if progress.Complete() {
+		t.Error("Expected the drain to be incomplete")
+	}
+
+	for _, r := range progress.Pending {
+		ring.ConfirmRangeMoved("node1", r.Range)
+	}
+	if progress, _ = ring.DrainProgress("node1"); !progress.Complete() || progress.MovedFraction != 1 {
+		t.Errorf("Expected the drain to be complete, got %+v", progress)
+	}
+
+	// Leaving the draining state drops the progress
+	ring.SetNodeState("node1", NodeActive)
+	if _, err := ring.DrainProgress("node1"); !errors.Is(err, ErrNotDraining) {
+		t.Errorf("Expected ErrNotDraining after reactivation, got %v", err)
+	}
+	ring.SetNodeState("node2", NodeDraining)
+	ring.RemoveNode("node2")
+	if _, err := ring.DrainProgress("node2"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound after removal, got %v", err)
+	}
+}
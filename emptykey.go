@@ -0,0 +1,88 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyKey is returned when looking up an empty key under EmptyKeySkip or
+// EmptyKeyError
+var ErrEmptyKey = errors.New("key cannot be empty")
+
+// EmptyKeyPolicy decides how lookups and load analysis treat the empty key
+type EmptyKeyPolicy int
+
+const (
+	// EmptyKeySkip rejects the empty key in lookups with ErrEmptyKey, while
+	// the load distribution methods and EstimateReplicaChurn leave it out, so
+	// analyses of real key sets tolerate blank entries. This is the default.
+	EmptyKeySkip EmptyKeyPolicy = iota
+	// EmptyKeyError rejects the empty key everywhere: lookups fail with
+	// ErrEmptyKey and so do the load distribution methods
+	EmptyKeyError
+	// EmptyKeyHash routes the empty key like any other key
+	EmptyKeyHash
+	// EmptyKeyNode routes the empty key to the node set by WithEmptyKeyNode,
+	// which need not be a member
+	EmptyKeyNode
+)
+
+// WithEmptyKeyPolicy sets how GetNode, GetOwnerID, GetNodes, GetNodeBatch,
+// GetNodesWithFilter, the load distribution methods and the RingViews of
+// WithHistory treat the empty key
+func WithEmptyKeyPolicy(policy EmptyKeyPolicy) Option {
+	return func(hr *HashRing) {
+		hr.emptyKeyPolicy = policy
+	}
+}
+
+// WithEmptyKeyNode routes the empty key to node, under EmptyKeyNode
+func WithEmptyKeyNode(node *Node) Option {
+	return func(hr *HashRing) {
+		hr.emptyKeyPolicy = EmptyKeyNode
+		hr.emptyKeyNode = node
+	}
+}
+
+// validateEmptyKeyPolicy checks the policy set by the options
+func (hr *HashRing) validateEmptyKeyPolicy() error {
+	switch hr.emptyKeyPolicy {
+	case EmptyKeySkip, EmptyKeyError, EmptyKeyHash:
+		return nil
+	case EmptyKeyNode:
+		if hr.emptyKeyNode == nil {
+			return errors.New("EmptyKeyNode needs a node set with WithEmptyKeyNode")
+		}
+		if err := hr.emptyKeyNode.Validate(); err != nil {
+			return fmt.Errorf("invalid empty key node: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid empty key policy %d", int(hr.emptyKeyPolicy))
+	}
+}
+
+// skipsEmptyKey reports whether load analysis leaves key out: the empty key
+// under EmptyKeySkip
+func (hr *HashRing) skipsEmptyKey(key string) bool {
+	return key == "" && hr.emptyKeyPolicy == EmptyKeySkip
+}
+
+// routeEmptyKey applies the ring's policy to the empty key
+func (hr *HashRing) routeEmptyKey() (node *Node, handled bool, err error) {
+	return routeEmptyKey(hr.emptyKeyPolicy, hr.emptyKeyNode)
+}
+
+// routeEmptyKey applies policy to the empty key, with target the node set by
+// WithEmptyKeyNode. It reports handled as false when the key is to be routed
+// like any other.
+func routeEmptyKey(policy EmptyKeyPolicy, target *Node) (node *Node, handled bool, err error) {
+	switch policy {
+	case EmptyKeyHash:
+		return nil, false, nil
+	case EmptyKeyNode:
+		return target, true, nil
+	default:
+		return nil, true, ErrEmptyKey
+	}
+}
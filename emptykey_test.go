@@ -0,0 +1,94 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEmptyKeyPolicy(t *testing.T) {
+	designated := &Node{ID: "empty", Host: "localhost", Port: 9000}
+	policies := []struct {
+		name string
+		opts []Option
+		want string // Owner of the empty key; "" means ErrEmptyKey
+	}{
+		{"skip", nil, ""},
+		{"error", []Option{WithEmptyKeyPolicy(EmptyKeyError)}, ""},
+		{"hash", []Option{WithEmptyKeyPolicy(EmptyKeyHash)}, "owner"},
+		{"node", []Option{WithEmptyKeyNode(designated)}, "empty"},
+	}
+
+	for _, p := range policies {
+		hr, err := NewHashRing(10, append(p.opts, WithHistory(2))...)
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+		hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+		hr.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+		want := p.want
+		if want == "owner" {
+			// The empty key hashes to a position like any other key
+			want = hr.virtualNodes[hr.search(hr.hash(""))].Node.ID
+		}
+
+		node, err := hr.GetNode("")
+		id, idErr := hr.GetOwnerID("")
+		nodes, nodesErr := hr.GetNodes("", 2)
+		batch, batchErr := hr.GetNodeBatch([]string{""})
+		distribution, distErr := hr.GetLoadDistribution([]string{"", ""})
+		stream := make(chan string, 1)
+		stream <- ""
+		close(stream)
+		streamed, streamErr := hr.GetLoadDistributionStream(context.Background(), stream)
+		view, genErr := hr.AtGeneration(hr.Generation())
+		if genErr != nil {
+			t.Fatalf("Failed to get ring view: %v", genErr)
+		}
+		viewNode, viewErr := view.GetNode("")
+		viewNodes, viewNodesErr := view.GetNodes("", 2)
+
+		if want == "" {
+			for i, err := range []error{err, idErr, nodesErr, batchErr, viewErr, viewNodesErr} {
+				if !errors.Is(err, ErrEmptyKey) {
+					t.Errorf("%s: lookup %d expected ErrEmptyKey, got %v", p.name, i, err)
+				}
+			}
+
+			if p.name == "error" {
+				if !errors.Is(distErr, ErrEmptyKey) || !errors.Is(streamErr, ErrEmptyKey) {
+					t.Errorf("%s: expected load analysis to fail with ErrEmptyKey, got %v and %v", p.name, distErr, streamErr)
+				}
+				continue
+			}
+
+			// Load analysis skips empty keys instead
+			if distErr != nil || streamErr != nil || len(distribution) != 0 || len(streamed) != 0 {
+				t.Errorf("%s: expected empty keys skipped, got %v (%v) and %v (%v)",
+					p.name, distribution, distErr, streamed, streamErr)
+			}
+			continue
+		}
+
+		for i, err := range []error{err, idErr, nodesErr, batchErr, distErr, streamErr, viewErr, viewNodesErr} {
+			if err != nil {
+				t.Fatalf("%s: lookup %d failed: %v", p.name, i, err)
+			}
+		}
+		if viewNode.ID != want || viewNodes[0].ID != want {
+			t.Errorf("%s: expected the ring view to route the empty key to %s, got %s and %s",
+				p.name, want, viewNode.ID, viewNodes[0].ID)
+		}
+		if node.ID != want || id != want || nodes[0].ID != want || batch[""].ID != want {
+			t.Errorf("%s: expected the empty key on %s, got %s, %s, %s and %s",
+				p.name, want, node.ID, id, nodes[0].ID, batch[""].ID)
+		}
+		if distribution[want] != 2 || streamed[want] != 1 {
+			t.Errorf("%s: expected empty keys counted against %s, got %v and %v", p.name, want, distribution, streamed)
+		}
+	}
+
+	if _, err := NewHashRing(10, WithEmptyKeyPolicy(EmptyKeyNode)); err == nil {
+		t.Error("Expected EmptyKeyNode without a node to be rejected")
+	}
+}
@@ -0,0 +1,257 @@
+package consistenthashing
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrInvalidEncoding is returned when decoding data that is not a binary
+// ring encoding of a supported version
+var ErrInvalidEncoding = errors.New("invalid binary ring encoding")
+
+// MarshalJSON encodes the ring as its JSON snapshot, so a ring can be stored
+// directly or as a field of a larger document
+func (hr *HashRing) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hr.Snapshot())
+}
+
+// UnmarshalJSON replaces hr with the ring encoded by MarshalJSON, as
+// RestoreHashRing would create it. Settings that snapshots don't hold get
+// their defaults, and snapshots of rings with custom hash functions fail
+// with ErrUnknownHasher. The ring must not be in use.
+func (hr *HashRing) UnmarshalJSON(data []byte) error {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return hr.restore(s, nil)
+}
+
+// Encode writes the ring's snapshot to w in the compact binary format of
+// BinaryCodec
+func (hr *HashRing) Encode(w io.Writer) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	return BinaryCodec{}.Encode(w, hr.Snapshot())
+}
+
+// Decode replaces hr with the ring written by Encode, as UnmarshalJSON does
+// for JSON. The ring must not be in use.
+func (hr *HashRing) Decode(r io.Reader) error {
+	s, err := ReadSnapshot(r, BinaryCodec{})
+	if err != nil {
+		return err
+	}
+	return hr.restore(s, nil)
+}
+
+// binaryMagic starts every binary encoding, followed by the format version
+const (
+	binaryMagic   = "CHR"
+	binaryVersion = 1
+)
+
+// maxBinaryLength bounds decoded strings and counts, so corrupt input can't
+// trigger huge allocations
+const maxBinaryLength = 1 << 24
+
+// BinaryCodec encodes snapshots in a compact, versioned binary format of
+// varints and length-prefixed strings, typically a fraction of the JSON
+// size. It only encodes Snapshot values. Decoding buffers readers that are
+// not io.ByteReaders, so it may read past the end of the encoding.
+type BinaryCodec struct{}
+
+func (BinaryCodec) Encode(w io.Writer, v interface{}) error {
+	var s Snapshot
+	switch v := v.(type) {
+	case Snapshot:
+		s = v
+	case *Snapshot:
+		s = *v
+	default:
+		return fmt.Errorf("binary codec cannot encode %T", v)
+	}
+
+	buf := append([]byte(binaryMagic), binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(s.VirtualReplicas))
+	buf = appendString(buf, s.HashFunction)
+	buf = binary.AppendUvarint(buf, uint64(s.ShardCount))
+	buf = binary.AppendUvarint(buf, s.Generation)
+	buf = binary.AppendUvarint(buf, uint64(s.MaxVirtualNodes))
+
+	classes := make([]string, 0, len(s.Classes))
+	for class := range s.Classes {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	buf = binary.AppendUvarint(buf, uint64(len(classes)))
+	for _, class := range classes {
+		buf = appendString(buf, class)
+		buf = binary.AppendVarint(buf, int64(s.Classes[class]))
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(s.Nodes)))
+	for _, node := range s.Nodes {
+		buf = appendString(buf, node.ID)
+		buf = appendString(buf, node.Host)
+		buf = binary.AppendUvarint(buf, uint64(node.Port))
+		buf = binary.AppendVarint(buf, int64(node.Weight))
+		buf = appendString(buf, node.Zone)
+		buf = appendString(buf, node.Class)
+
+		keys := sortedMetaKeys(node.Meta)
+		buf = binary.AppendUvarint(buf, uint64(len(keys)))
+		for _, k := range keys {
+			buf = appendString(buf, k)
+			buf = appendString(buf, node.Meta[k])
+		}
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+func (BinaryCodec) Decode(r io.Reader, v interface{}) error {
+	s, ok := v.(*Snapshot)
+	if !ok {
+		return fmt.Errorf("binary codec cannot decode into %T", v)
+	}
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	d := &binaryDecoder{r: br}
+
+	if magic := d.bytes(len(binaryMagic) + 1); d.err == nil &&
+		(string(magic[:len(binaryMagic)]) != binaryMagic || magic[len(binaryMagic)] != binaryVersion) {
+		return ErrInvalidEncoding
+	}
+
+	var decoded Snapshot
+	decoded.VirtualReplicas = d.int()
+	decoded.HashFunction = d.string()
+	decoded.ShardCount = d.int()
+	decoded.Generation = d.uvarint()
+	decoded.MaxVirtualNodes = d.int()
+
+	if n := d.length(); n > 0 {
+		decoded.Classes = make(map[string]int, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			class := d.string()
+			decoded.Classes[class] = int(d.varint())
+		}
+	}
+
+	n := d.length()
+	decoded.Nodes = make([]Node, 0, min(n, 1024))
+	for i := 0; i < n && d.err == nil; i++ {
+		node := Node{
+			ID:     d.string(),
+			Host:   d.string(),
+			Port:   d.int(),
+			Weight: int(d.varint()),
+			Zone:   d.string(),
+			Class:  d.string(),
+		}
+		if m := d.length(); m > 0 {
+			node.Meta = make(map[string]string, m)
+			for j := 0; j < m && d.err == nil; j++ {
+				k := d.string()
+				node.Meta[k] = d.string()
+			}
+		}
+		decoded.Nodes = append(decoded.Nodes, node)
+	}
+
+	if d.err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, d.err)
+	}
+	*s = decoded
+	return nil
+}
+
+// appendString appends a length-prefixed string
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// binaryDecoder reads the binary format, keeping the first error so fields
+// can be read in sequence and checked once
+type binaryDecoder struct {
+	r   io.ByteReader
+	err error
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.fail(err)
+	return v
+}
+
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	d.fail(err)
+	return v
+}
+
+// int reads a non-negative int
+func (d *binaryDecoder) int() int {
+	v := d.uvarint()
+	if v > maxBinaryLength<<8 {
+		d.fail(fmt.Errorf("value %d out of range", v))
+		return 0
+	}
+	return int(v)
+}
+
+// length reads a string length or element count
+func (d *binaryDecoder) length() int {
+	v := d.uvarint()
+	if v > maxBinaryLength {
+		d.fail(fmt.Errorf("length %d out of range", v))
+		return 0
+	}
+	return int(v)
+}
+
+func (d *binaryDecoder) string() string {
+	return string(d.bytes(d.length()))
+}
+
+func (d *binaryDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	b := make([]byte, n)
+	for i := range b {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			d.fail(err)
+			return nil
+		}
+		b[i] = c
+	}
+	return b
+}
+
+func (d *binaryDecoder) fail(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if d.err == nil && err != nil {
+		d.err = err
+	}
+}
@@ -0,0 +1,125 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func newEncodingRing(t *testing.T) *HashRing {
+	t.Helper()
+	hr, err := NewHashRing(40,
+		WithHashFunction(&SHA256Hasher{}),
+		WithCapacityClasses(map[string]int{"big": 3}),
+		WithMaxVirtualNodes(5000))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "10.0.0.1", Port: 8080 + i, Weight: i, Zone: "a"})
+	}
+	hr.AddNode(&Node{ID: "big", Host: "10.0.0.2", Port: 9000, Class: "big", Meta: map[string]string{"disk": "ssd"}})
+	return hr
+}
+
+// assertSameRing checks that two rings route alike and hold the same state
+func assertSameRing(t *testing.T, want, got *HashRing) {
+	t.Helper()
+	if !reflect.DeepEqual(want.Snapshot(), got.Snapshot()) {
+		t.Fatalf("Expected snapshot %+v, got %+v", want.Snapshot(), got.Snapshot())
+	}
+	if err := got.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("key%d", i)
+		a, _ := want.GetNode(key)
+		b, _ := got.GetNode(key)
+		if a.ID != b.ID {
+			t.Fatalf("Key %s routed to %s, expected %s", key, b.ID, a.ID)
+		}
+	}
+}
+
+func TestHashRingJSON(t *testing.T) {
+	hr := newEncodingRing(t)
+
+	data, err := json.Marshal(struct{ Ring *HashRing }{hr})
+	if err != nil {
+		t.Fatalf("Failed to marshal ring: %v", err)
+	}
+	var decoded struct{ Ring *HashRing }
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal ring: %v", err)
+	}
+	assertSameRing(t, hr, decoded.Ring)
+
+	custom, _ := NewHashRing(10, WithHashFunction(constantHasher{}))
+	data, _ = json.Marshal(custom)
+	var ring HashRing
+	if err := json.Unmarshal(data, &ring); !errors.Is(err, ErrUnknownHasher) {
+		t.Errorf("Expected ErrUnknownHasher, got %v", err)
+	}
+
+	// A snapshot that fails to restore leaves the receiver unchanged
+	bad := hr.Snapshot()
+	bad.Nodes = append(bad.Nodes, Node{ID: "bad"})
+	badData, _ := json.Marshal(bad)
+	for name, data := range map[string][]byte{"custom hasher": data, "invalid node": badData} {
+		if err := json.Unmarshal(data, hr); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+		assertSameRing(t, newEncodingRing(t), hr)
+	}
+}
+
+func TestHashRingBinary(t *testing.T) {
+	hr := newEncodingRing(t)
+
+	var buf bytes.Buffer
+	if err := hr.Encode(&buf); err != nil {
+		t.Fatalf("Failed to encode ring: %v", err)
+	}
+	encoded := buf.Bytes()
+	jsonData, _ := json.Marshal(hr)
+	if len(encoded) >= len(jsonData)/2 {
+		t.Errorf("Expected the binary encoding to be compact, got %d bytes vs %d for JSON", len(encoded), len(jsonData))
+	}
+
+	var decoded HashRing
+	if err := decoded.Decode(bytes.NewReader(encoded)); err != nil {
+		t.Fatalf("Failed to decode ring: %v", err)
+	}
+	assertSameRing(t, hr, &decoded)
+
+	// BinaryCodec also works with WriteSnapshot and ReadSnapshot
+	buf.Reset()
+	if err := (BinaryCodec{}).Encode(&buf, hr.Snapshot()); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	snapshot, err := ReadSnapshot(&buf, BinaryCodec{})
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	restored, err := RestoreHashRing(snapshot)
+	if err != nil {
+		t.Fatalf("Failed to restore ring: %v", err)
+	}
+	assertSameRing(t, hr, restored)
+
+	// Corrupt and truncated input is rejected
+	for name, data := range map[string][]byte{
+		"magic":     append([]byte("XYZ"), encoded[3:]...),
+		"version":   append([]byte("CHR\x09"), encoded[4:]...),
+		"truncated": encoded[:len(encoded)/2],
+		"empty":     nil,
+	} {
+		var ring HashRing
+		if err := ring.Decode(bytes.NewReader(data)); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("%s: expected ErrInvalidEncoding, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package consistenthashing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NodeIDFunc derives a member ID from an endpoint. It must be deterministic,
+// so the same endpoint always yields the same member across restarts.
+type NodeIDFunc func(host string, port int) (string, error)
+
+// HostPortID identifies a node by its "host:port" address. It is the default.
+func HostPortID(host string, port int) (string, error) {
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// HashedID identifies a node by the first 16 hex digits of the SHA-256 of its
+// "host:port" address, for IDs that don't reveal addresses
+func HashedID(host string, port int) (string, error) {
+	sum := sha256.Sum256([]byte(net.JoinHostPort(host, strconv.Itoa(port))))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// LookupID identifies nodes through a table keyed by "host:port", such as
+// endpoints mapped to cloud instance IDs. Endpoints missing from the table
+// are rejected.
+func LookupID(ids map[string]string) NodeIDFunc {
+	return func(host string, port int) (string, error) {
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
+		id, ok := ids[addr]
+		if !ok {
+			return "", fmt.Errorf("no ID for endpoint %s", addr)
+		}
+		return id, nil
+	}
+}
+
+// EndpointOption configures how endpoints are turned into nodes
+type EndpointOption func(*endpointConfig)
+
+// endpointConfig holds the settings applied by EndpointOptions
+type endpointConfig struct {
+	nodeID NodeIDFunc
+}
+
+// WithNodeID sets how node IDs are derived from endpoints
+func WithNodeID(fn NodeIDFunc) EndpointOption {
+	return func(c *endpointConfig) {
+		if fn != nil {
+			c.nodeID = fn
+		}
+	}
+}
+
+// newEndpointConfig applies opts over the defaults
+func newEndpointConfig(opts []EndpointOption) endpointConfig {
+	config := endpointConfig{nodeID: HostPortID}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// defaultSchemePorts are assumed for URLs without an explicit port
+var defaultSchemePorts = map[string]int{
+	"http":  80,
+	"https": 443,
+	"grpc":  443,
+	"redis": 6379,
+}
+
+// NodesFromHostPorts parses "host:port" endpoints into nodes identified by
+// their normalized address, or by WithNodeID, so the same endpoint always
+// yields the same member. Hosts are lowercased and IPv6 addresses must be
+// bracketed. Duplicate IDs are rejected.
+func NodesFromHostPorts(addrs []string, opts ...EndpointOption) ([]*Node, error) {
+	config := newEndpointConfig(opts)
+	nodes := make([]*Node, 0, len(addrs))
+	seen := make(map[string]bool, len(addrs))
+
+	for _, addr := range addrs {
+		host, portStr, err := net.SplitHostPort(strings.TrimSpace(addr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", addr, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: bad port %q", addr, portStr)
+		}
+
+		node, err := endpointNode(host, port, config.nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", addr, err)
+		}
+		if seen[node.ID] {
+			return nil, fmt.Errorf("duplicate node ID %s", node.ID)
+		}
+		seen[node.ID] = true
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// NodesFromURLs parses service URLs such as "http://10.0.0.1:8080" into nodes
+// identified by their normalized "host:port" address, or by WithNodeID. The port defaults by
+// scheme for http, https, grpc and redis. The optional "weight", "zone" and
+// "class" query parameters set the matching node fields, for example
+// "https://cache-1.internal?weight=4&zone=us-east-1a". Duplicate IDs are
+// rejected.
+func NodesFromURLs(urls []string, opts ...EndpointOption) ([]*Node, error) {
+	config := newEndpointConfig(opts)
+	nodes := make([]*Node, 0, len(urls))
+	seen := make(map[string]bool, len(urls))
+
+	for _, raw := range urls {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+		}
+		if u.Scheme == "" || u.Hostname() == "" {
+			return nil, fmt.Errorf("invalid URL %q: scheme and host are required", raw)
+		}
+
+		port, ok := defaultSchemePorts[strings.ToLower(u.Scheme)]
+		if p := u.Port(); p != "" {
+			if port, err = strconv.Atoi(p); err != nil {
+				return nil, fmt.Errorf("invalid URL %q: bad port %q", raw, p)
+			}
+		} else if !ok {
+			return nil, fmt.Errorf("invalid URL %q: no default port for scheme %s", raw, u.Scheme)
+		}
+
+		node, err := endpointNode(u.Hostname(), port, config.nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+		}
+
+		query := u.Query()
+		if w := query.Get("weight"); w != "" {
+			if node.Weight, err = strconv.Atoi(w); err != nil || node.Weight <= 0 {
+				return nil, fmt.Errorf("invalid URL %q: bad weight %q", raw, w)
+			}
+		}
+		node.Zone = query.Get("zone")
+		node.Class = query.Get("class")
+		if err := node.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+		}
+
+		if seen[node.ID] {
+			return nil, fmt.Errorf("duplicate node ID %s", node.ID)
+		}
+		seen[node.ID] = true
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// endpointNode builds a validated node for an endpoint
+func endpointNode(host string, port int, nodeID NodeIDFunc) (*Node, error) {
+	host = strings.ToLower(host)
+	id, err := nodeID(host, port)
+	if err != nil {
+		return nil, err
+	}
+	node := &Node{ID: id, Host: host, Port: port}
+	if err := node.Validate(); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
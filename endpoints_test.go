@@ -0,0 +1,141 @@
+package consistenthashing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNodesFromHostPorts(t *testing.T) {
+	nodes, err := NodesFromHostPorts([]string{"10.0.0.1:6379", " Cache-2.Internal:6380 ", "[::1]:7000"})
+	if err != nil {
+		t.Fatalf("Failed to parse endpoints: %v", err)
+	}
+
+	expected := []Node{
+		{ID: "10.0.0.1:6379", Host: "10.0.0.1", Port: 6379},
+		{ID: "cache-2.internal:6380", Host: "cache-2.internal", Port: 6380},
+		{ID: "[::1]:7000", Host: "::1", Port: 7000},
+	}
+	if len(nodes) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d", len(expected), len(nodes))
+	}
+	for i, node := range nodes {
+		if !node.Equal(&expected[i]) {
+			t.Errorf("Node %d: expected %+v, got %+v", i, expected[i], *node)
+		}
+	}
+
+	invalid := [][]string{
+		{"10.0.0.1"},
+		{"10.0.0.1:http"},
+		{"10.0.0.1:0"},
+		{":6379"},
+		{"10.0.0.1:6379", "10.0.0.1:6379"},
+	}
+	for _, addrs := range invalid {
+		if _, err := NodesFromHostPorts(addrs); err == nil {
+			t.Errorf("Expected %v to be rejected", addrs)
+		}
+	}
+}
+
+func TestNodesFromURLs(t *testing.T) {
+	nodes, err := NodesFromURLs([]string{
+		"http://10.0.0.1:8080/health",
+		"https://cache-1.internal?weight=4&zone=us-east-1a",
+		"redis://10.0.0.3",
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse URLs: %v", err)
+	}
+
+	expected := []Node{
+		{ID: "10.0.0.1:8080", Host: "10.0.0.1", Port: 8080},
+		{ID: "cache-1.internal:443", Host: "cache-1.internal", Port: 443, Weight: 4, Zone: "us-east-1a"},
+		{ID: "10.0.0.3:6379", Host: "10.0.0.3", Port: 6379},
+	}
+	if len(nodes) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d", len(expected), len(nodes))
+	}
+	for i, node := range nodes {
+		if !node.Equal(&expected[i]) {
+			t.Errorf("Node %d: expected %+v, got %+v", i, expected[i], *node)
+		}
+	}
+
+	// The same endpoint with different paths or schemes is one member
+	if _, err := NodesFromURLs([]string{"http://10.0.0.1:8080/a", "grpc://10.0.0.1:8080"}); err == nil {
+		t.Error("Expected duplicate endpoints to be rejected")
+	}
+
+	invalid := []string{
+		"10.0.0.1:8080",
+		"ftp://10.0.0.1",
+		"http://10.0.0.1:x",
+		"http://10.0.0.1?weight=0",
+		"http://:8080",
+	}
+	for _, raw := range invalid {
+		if _, err := NodesFromURLs([]string{raw}); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+	}
+
+	// Weights are validated like any other node
+	_, err = NodesFromURLs([]string{"http://10.0.0.1?weight=100000"})
+	if !errors.Is(err, ErrInvalidNodeWeight) {
+		t.Errorf("Expected ErrInvalidNodeWeight, got %v", err)
+	}
+}
+
+func TestWithNodeID(t *testing.T) {
+	addrs := []string{"10.0.0.1:6379", "10.0.0.2:6379"}
+
+	// Hashed IDs are stable and don't reveal the address
+	first, err := NodesFromHostPorts(addrs, WithNodeID(HashedID))
+	if err != nil {
+		t.Fatalf("Failed to parse endpoints: %v", err)
+	}
+	second, _ := NodesFromHostPorts(addrs, WithNodeID(HashedID))
+	for i := range first {
+		if first[i].ID != second[i].ID {
+			t.Errorf("Expected stable IDs, got %s and %s", first[i].ID, second[i].ID)
+		}
+		if len(first[i].ID) != 16 || first[i].ID == first[i].Host {
+			t.Errorf("Expected a 16 digit hashed ID, got %q", first[i].ID)
+		}
+	}
+	if first[0].ID == first[1].ID {
+		t.Error("Expected distinct endpoints to get distinct IDs")
+	}
+
+	// Lookup tables map endpoints to external IDs such as instance IDs
+	lookup := WithNodeID(LookupID(map[string]string{
+		"10.0.0.1:6379": "i-0abc",
+		"10.0.0.2:6379": "i-0def",
+	}))
+	nodes, err := NodesFromURLs([]string{"redis://10.0.0.1", "redis://10.0.0.2:6379"}, lookup)
+	if err != nil {
+		t.Fatalf("Failed to parse URLs: %v", err)
+	}
+	if nodes[0].ID != "i-0abc" || nodes[1].ID != "i-0def" {
+		t.Errorf("Expected instance IDs, got %s and %s", nodes[0].ID, nodes[1].ID)
+	}
+	if _, err := NodesFromHostPorts([]string{"10.0.0.3:6379"}, lookup); err == nil {
+		t.Error("Expected endpoints missing from the table to be rejected")
+	}
+
+	// Two endpoints mapped to one ID are duplicates
+	same := WithNodeID(func(host string, port int) (string, error) { return "shared", nil })
+	if _, err := NodesFromHostPorts(addrs, same); err == nil {
+		t.Error("Expected duplicate IDs to be rejected")
+	}
+
+	nodes, err = ImportKetamaServers([]string{"10.0.0.1:11211 2"}, WithNodeID(HashedID))
+	if err != nil {
+		t.Fatalf("Failed to import servers: %v", err)
+	}
+	if want, _ := HashedID("10.0.0.1", 11211); nodes[0].ID != want {
+		t.Errorf("Expected hashed ID %s, got %s", want, nodes[0].ID)
+	}
+}
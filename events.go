@@ -0,0 +1,102 @@
+package consistenthashing
+
+import "sync"
+
+// EventType is the kind of membership change described by a RingEvent
+type EventType string
+
+// Membership change events
+const (
+	EventNodeAdded   EventType = "added"
+	EventNodeRemoved EventType = "removed"
+	EventNodeUpdated EventType = "updated"  // Weight, address or zone changed
+	EventSnapshot    EventType = "snapshot" // Full membership, sent first by WatchReplay
+)
+
+// RingEvent describes one membership change. Changes applied together by
+// SetMembers share a generation.
+type RingEvent struct {
+	Type       EventType
+	Node       *Node   // The node as added, removed or updated
+	Members    []*Node // All members sorted by ID, only set on EventSnapshot
+	Generation uint64  // Ring generation after the change
+}
+
+// watchers fans ring events out to subscribers. It has its own lock so
+// subscribing never waits for the ring lock.
+type watchers struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]chan RingEvent
+}
+
+// Watch subscribes to membership changes. Delivery is best-effort: events are
+// dropped when the buffer is full, so subscribers that need the exact state
+// should re-read the ring on every event rather than replay the events. The
+// returned function unsubscribes and closes the channel.
+func (hr *HashRing) Watch(buffer int) (<-chan RingEvent, func()) {
+	return hr.watchers.subscribe(buffer, nil)
+}
+
+// WatchReplay is Watch for late subscribers: the channel first receives an
+// EventSnapshot with the current membership, followed by every change after
+// it, so a subscriber can build its view without a separate snapshot call.
+// The snapshot always fits the buffer; later events are best-effort as with
+// Watch.
+func (hr *HashRing) WatchReplay(buffer int) (<-chan RingEvent, func()) {
+	// The read lock keeps changes out until the snapshot is queued
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.watchers.subscribe(buffer, &RingEvent{
+		Type:       EventSnapshot,
+		Members:    hr.sortedNodesLocked(),
+		Generation: hr.generation,
+	})
+}
+
+// subscribe registers a subscriber with room for at least buffer events,
+// queueing first ahead of any change when it is set
+func (w *watchers) subscribe(buffer int, first *RingEvent) (<-chan RingEvent, func()) {
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.next
+	w.next++
+	ch := make(chan RingEvent, buffer)
+	if first != nil {
+		ch <- *first
+	}
+	w.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+
+			delete(w.subs, id)
+			close(ch)
+		})
+	}
+}
+
+// emitLocked sends an event to every subscriber without blocking. Callers must
+// hold the write lock, which keeps events in generation order.
+func (hr *HashRing) emitLocked(eventType EventType, node *Node) {
+	w := hr.watchers
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	event := RingEvent{Type: eventType, Node: node, Generation: hr.generation}
+	for _, ch := range w.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package consistenthashing
+
+import (
+	"testing"
+)
+
+func TestWatch(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	events, cancel := ring.Watch(10)
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.UpdateNodeWeight("node1", 2)
+	ring.RemoveNode("node1")
+	ring.SetMembers([]*Node{{ID: "a", Host: "localhost", Port: 1}, {ID: "b", Host: "localhost", Port: 2}})
+
+	expected := []struct {
+		eventType  EventType
+		id         string
+		generation uint64
+	}{
+		{EventNodeAdded, "node1", 1},
+		{EventNodeUpdated, "node1", 2},
+		{EventNodeRemoved, "node1", 3},
+		{EventNodeAdded, "a", 4},
+		{EventNodeAdded, "b", 4},
+	}
+	for _, e := range expected {
+		event := <-events
+		if event.Type != e.eventType || event.Node.ID != e.id || event.Generation != e.generation {
+			t.Errorf("Expected %s %s at %d, got %s %s at %d", e.eventType, e.id, e.generation, event.Type, event.Node.ID, event.Generation)
+		}
+	}
+
+	cancel()
+	cancel() // Safe to call twice
+	if _, ok := <-events; ok {
+		t.Error("Expected channel to be closed after unsubscribing")
+	}
+
+	// Unsubscribed channels no longer receive events
+	ring.RemoveNode("a")
+}
+
+func TestWatchDropsWhenFull(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	events, cancel := ring.Watch(1)
+	defer cancel()
+
+	// A slow subscriber must not block membership changes
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+
+	if event := <-events; event.Node.ID != "node1" {
+		t.Errorf("Expected the first event to be kept, got %s", event.Node.ID)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("Expected the second event to be dropped, got %+v", event)
+	default:
+	}
+}
+
+func TestWatchReplay(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "b", Host: "localhost", Port: 8081})
+	ring.AddNode(&Node{ID: "a", Host: "localhost", Port: 8080})
+
+	events, cancel := ring.WatchReplay(0)
+	defer cancel()
+
+	snapshot := <-events
+	if snapshot.Type != EventSnapshot || snapshot.Generation != ring.Generation() {
+		t.Fatalf("Expected a snapshot at generation %d, got %+v", ring.Generation(), snapshot)
+	}
+	if len(snapshot.Members) != 2 || snapshot.Members[0].ID != "a" || snapshot.Members[1].ID != "b" {
+		t.Errorf("Expected members a and b, got %v", snapshot.Members)
+	}
+
+	ring.RemoveNode("b")
+	event := <-events
+	if event.Type != EventNodeRemoved || event.Node.ID != "b" || event.Generation != snapshot.Generation+1 {
+		t.Errorf("Expected b's removal after the snapshot, got %+v", event)
+	}
+}
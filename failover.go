@@ -0,0 +1,178 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Failover errors
+var (
+	ErrGroupNotFound = errors.New("group not found")
+	ErrNoStandby     = errors.New("group has no standby to promote")
+)
+
+// failoverGroup is a primary/standby set that occupies a single ring position
+type failoverGroup struct {
+	members []*Node
+	leader  int
+}
+
+// FailoverRing places failover groups rather than individual nodes on the
+// ring. Keys map to a group by its ID, and lookups return the group's current
+// leader, so promoting a standby changes the serving node without remapping
+// any keys.
+type FailoverRing struct {
+	ring   *HashRing
+	groups map[string]*failoverGroup
+	mu     sync.RWMutex
+}
+
+// NewFailoverRing creates an empty failover ring
+func NewFailoverRing(virtualReplicas int, opts ...Option) (*FailoverRing, error) {
+	ring, err := NewHashRing(virtualReplicas, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FailoverRing{
+		ring:   ring,
+		groups: make(map[string]*failoverGroup),
+	}, nil
+}
+
+// AddGroup adds a failover group. The first member starts as leader and its
+// weight determines the group's share of the ring.
+func (f *FailoverRing) AddGroup(groupID string, members ...*Node) error {
+	if strings.TrimSpace(groupID) == "" {
+		return errors.New("group ID cannot be empty")
+	}
+	if len(members) == 0 {
+		return errors.New("group needs at least one member")
+	}
+	for _, member := range members {
+		if member == nil {
+			return errors.New("member cannot be nil")
+		}
+		if err := member.Validate(); err != nil {
+			return fmt.Errorf("invalid member: %w", err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.groups[groupID]; exists {
+		return fmt.Errorf("group %s already exists", groupID)
+	}
+
+	// The ring member is an anchor named after the group, so ring positions
+	// only depend on the group ID
+	leader := members[0]
+	anchor := &Node{ID: groupID, Host: leader.Host, Port: leader.Port, Weight: leader.Weight, Zone: leader.Zone}
+	if err := f.ring.AddNode(anchor); err != nil {
+		return err
+	}
+
+	f.groups[groupID] = &failoverGroup{members: append([]*Node(nil), members...)}
+	return nil
+}
+
+// RemoveGroup removes a failover group from the ring
+func (f *FailoverRing) RemoveGroup(groupID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.groups[groupID]; !exists {
+		return ErrGroupNotFound
+	}
+	if err := f.ring.RemoveNode(groupID); err != nil {
+		return err
+	}
+
+	delete(f.groups, groupID)
+	return nil
+}
+
+// PromoteStandby makes the next member of the group its leader and returns it.
+// Members rotate in the order they were added.
+func (f *FailoverRing) PromoteStandby(groupID string) (*Node, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, exists := f.groups[groupID]
+	if !exists {
+		return nil, ErrGroupNotFound
+	}
+	if len(group.members) < 2 {
+		return nil, ErrNoStandby
+	}
+
+	group.leader = (group.leader + 1) % len(group.members)
+	return group.members[group.leader], nil
+}
+
+// Leader returns the current leader of a group
+func (f *FailoverRing) Leader(groupID string) (*Node, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	group, exists := f.groups[groupID]
+	if !exists {
+		return nil, ErrGroupNotFound
+	}
+	return group.members[group.leader], nil
+}
+
+// GetGroup returns the ID of the group responsible for a key
+func (f *FailoverRing) GetGroup(key string) (string, error) {
+	anchor, err := f.ring.GetNode(key)
+	if err != nil {
+		return "", err
+	}
+	return anchor.ID, nil
+}
+
+// GetNode returns the current leader of the group responsible for a key
+func (f *FailoverRing) GetNode(key string) (*Node, error) {
+	// Hold the lock across both steps, so the group can't be removed between
+	// the ring lookup and resolving its leader
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	anchor, err := f.ring.GetNode(key)
+	if err != nil {
+		return nil, err
+	}
+
+	group, exists := f.groups[anchor.ID]
+	if !exists {
+		return nil, ErrGroupNotFound
+	}
+	return group.members[group.leader], nil
+}
+
+// GetNodes returns the leaders of the count groups responsible for a key
+func (f *FailoverRing) GetNodes(key string, count int) ([]*Node, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	anchors, err := f.ring.GetNodes(key, count)
+	if err != nil {
+		return nil, err
+	}
+
+	leaders := make([]*Node, 0, len(anchors))
+	for _, anchor := range anchors {
+		if group, exists := f.groups[anchor.ID]; exists {
+			leaders = append(leaders, group.members[group.leader])
+		}
+	}
+	return leaders, nil
+}
+
+// Size returns the number of groups
+func (f *FailoverRing) Size() int {
+	return f.ring.Size()
+}
@@ -0,0 +1,115 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestFailoverRing(t *testing.T) {
+	ring, err := NewFailoverRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	primaryA := &Node{ID: "a-primary", Host: "10.0.0.1", Port: 8080}
+	standbyA := &Node{ID: "a-standby", Host: "10.0.0.2", Port: 8080}
+	primaryB := &Node{ID: "b-primary", Host: "10.0.1.1", Port: 8080}
+
+	if err := ring.AddGroup("group-a", primaryA, standbyA); err != nil {
+		t.Fatalf("Failed to add group: %v", err)
+	}
+	if err := ring.AddGroup("group-b", primaryB); err != nil {
+		t.Fatalf("Failed to add group: %v", err)
+	}
+	if err := ring.AddGroup("group-b", primaryB); err == nil {
+		t.Error("Expected error when adding a duplicate group")
+	}
+
+	groups := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key_%d", i)
+		groups[key], _ = ring.GetGroup(key)
+		node, err := ring.GetNode(key)
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		if groups[key] == "group-a" && node != primaryA {
+			t.Errorf("Expected primary of group-a for %s, got %s", key, node.ID)
+		}
+	}
+
+	promoted, err := ring.PromoteStandby("group-a")
+	if err != nil {
+		t.Fatalf("Failed to promote standby: %v", err)
+	}
+	if promoted != standbyA {
+		t.Errorf("Expected a-standby to be promoted, got %s", promoted.ID)
+	}
+
+	// Promotion changes the serving node but never the group of a key
+	for key, group := range groups {
+		if g, _ := ring.GetGroup(key); g != group {
+			t.Errorf("Key %s moved from %s to %s after promotion", key, group, g)
+		}
+		node, _ := ring.GetNode(key)
+		if group == "group-a" && node != standbyA {
+			t.Errorf("Expected promoted standby for %s, got %s", key, node.ID)
+		}
+	}
+
+	if _, err := ring.PromoteStandby("group-b"); err != ErrNoStandby {
+		t.Errorf("Expected ErrNoStandby, got %v", err)
+	}
+	if _, err := ring.PromoteStandby("missing"); err != ErrGroupNotFound {
+		t.Errorf("Expected ErrGroupNotFound, got %v", err)
+	}
+
+	nodes, err := ring.GetNodes("key_1", 2)
+	if err != nil || len(nodes) != 2 {
+		t.Fatalf("Expected 2 leaders, got %v (%v)", nodes, err)
+	}
+
+	ring.RemoveGroup("group-a")
+	if ring.Size() != 1 {
+		t.Errorf("Expected 1 group after removal, got %d", ring.Size())
+	}
+}
+
+func TestFailoverRingConcurrentRemove(t *testing.T) {
+	f, err := NewFailoverRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	f.AddGroup("stable", &Node{ID: "s1", Host: "localhost", Port: 8080})
+	churned := &Node{ID: "c1", Host: "localhost", Port: 8081}
+
+	// Lookups never see a group the ring still routed to but that is gone
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			f.AddGroup("churned", churned)
+			f.RemoveGroup("churned")
+		}
+		close(done)
+	}()
+	for i := 0; ; i++ {
+		select {
+		case <-done:
+			wg.Wait()
+			return
+		default:
+		}
+		key := fmt.Sprintf("key%d", i)
+		if _, err := f.GetNode(key); errors.Is(err, ErrGroupNotFound) {
+			t.Fatalf("GetNode(%s) lost a race with RemoveGroup: %v", key, err)
+		}
+		if nodes, err := f.GetNodes(key, 2); err != nil || len(nodes) == 0 {
+			t.Fatalf("GetNodes(%s) returned %v, %v", key, nodes, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package consistenthashing
+
+// WithFallbackNode designates a node that GetNode, GetOwnerID, GetNodes,
+// GetNodeBatch and the binary key lookups return while the ring is empty,
+// instead of ErrEmptyRing, so lookups during bootstrap need no special case.
+// Replicated lookups return the fallback alone. The fallback is not a member: it owns no virtual
+// nodes and is never returned once the ring has members.
+func WithFallbackNode(node *Node) Option {
+	return func(hr *HashRing) {
+		hr.fallback = node
+	}
+}
+
+// FallbackNode returns the node designated by WithFallbackNode, or nil
+func (hr *HashRing) FallbackNode() *Node {
+	return hr.fallback
+}
+
+// GetNodeOrDefault returns the node responsible for key, or def if the lookup
+// fails, such as while the ring is empty and has no fallback node
+func (hr *HashRing) GetNodeOrDefault(key string, def *Node) *Node {
+	node, err := hr.GetNode(key)
+	if err != nil {
+		return def
+	}
+	return node
+}
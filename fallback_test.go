@@ -0,0 +1,76 @@
+package consistenthashing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetNodeOrDefault(t *testing.T) {
+	hr, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	def := &Node{ID: "default", Host: "localhost", Port: 9000}
+	if got := hr.GetNodeOrDefault("key", def); got != def {
+		t.Errorf("Expected the default node on an empty ring, got %v", got)
+	}
+	if got := hr.GetNodeOrDefault("key", nil); got != nil {
+		t.Errorf("Expected nil on an empty ring, got %v", got)
+	}
+
+	hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	if got := hr.GetNodeOrDefault("key", def); got == nil || got.ID != "node1" {
+		t.Errorf("Expected node1, got %v", got)
+	}
+}
+
+func TestWithFallbackNode(t *testing.T) {
+	fallback := &Node{ID: "bootstrap", Host: "localhost", Port: 9000}
+	hr, err := NewHashRing(10, WithFallbackNode(fallback))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if node, err := hr.GetNode("key"); err != nil || node != fallback {
+		t.Errorf("Expected the fallback node, got %v, %v", node, err)
+	}
+	if id, err := hr.GetOwnerID("key"); err != nil || id != "bootstrap" {
+		t.Errorf("Expected the fallback ID, got %q, %v", id, err)
+	}
+	if nodes, err := hr.GetNodes("key", 3); err != nil || len(nodes) != 1 || nodes[0] != fallback {
+		t.Errorf("Expected only the fallback node from GetNodes, got %v, %v", nodes, err)
+	}
+	dst := []*Node{{ID: "kept"}}
+	if nodes, err := hr.GetNodesAppend(dst, "key", 3); err != nil || len(nodes) != 2 || nodes[1] != fallback {
+		t.Errorf("Expected the fallback node appended by GetNodesAppend, got %v, %v", nodes, err)
+	}
+	if node, err := hr.GetNodeUint64(42); err != nil || node != fallback {
+		t.Errorf("Expected the fallback node from GetNodeUint64, got %v, %v", node, err)
+	}
+	if node, err := hr.GetNodeUUID([16]byte{1}); err != nil || node != fallback {
+		t.Errorf("Expected the fallback node from GetNodeUUID, got %v, %v", node, err)
+	}
+	if node, err := hr.GetNodeBytes([]byte("key")); err != nil || node != fallback {
+		t.Errorf("Expected the fallback node from GetNodeBytes, got %v, %v", node, err)
+	}
+	if batch, err := hr.GetNodeBatch([]string{"a", "b"}); err != nil || batch["a"] != fallback || batch["b"] != fallback {
+		t.Errorf("Expected the fallback node for every batch key, got %v, %v", batch, err)
+	}
+	if hr.HasNode("bootstrap") || hr.Size() != 0 {
+		t.Error("Expected the fallback node not to be a member")
+	}
+
+	hr.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	if node, _ := hr.GetNode("key"); node.ID != "node1" {
+		t.Errorf("Expected node1 once the ring has members, got %s", node.ID)
+	}
+	hr.RemoveNode("node1")
+	if node, _ := hr.GetNode("key"); node != fallback {
+		t.Errorf("Expected the fallback node after the ring emptied, got %v", node)
+	}
+
+	if _, err := NewHashRing(10, WithFallbackNode(&Node{ID: "bad"})); !errors.Is(err, ErrInvalidNodeHost) {
+		t.Errorf("Expected an invalid fallback node to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package consistenthashing
+
+import "testing"
+
+func FuzzNodeValidate(f *testing.F) {
+	f.Add("node1", "localhost", 8080, 1)
+	f.Add("", "", -1, -1)
+	f.Add("\t", "host", 65535, MaxNodeWeight+1)
+
+	f.Fuzz(func(t *testing.T, id, host string, port, weight int) {
+		node := &Node{ID: id, Host: host, Port: port, Weight: weight}
+		if err := node.Validate(); err != nil {
+			return
+		}
+
+		ring, err := NewHashRing(2)
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+		if weight > 64 {
+			// Valid but huge weights only slow the fuzzer down
+			return
+		}
+		if err := ring.AddNode(node); err != nil {
+			t.Errorf("AddNode rejected a node that passed validation: %v", err)
+		}
+		if got, err := ring.GetNode(id); err != nil || got != node {
+			t.Errorf("Expected single node ring to route to %q, got %v (%v)", id, got, err)
+		}
+	})
+}
+
+func FuzzGetNodes(f *testing.F) {
+	f.Add("key", 1)
+	f.Add("", 0)
+	f.Add("\x00", -3)
+	f.Add("very long key with unicode ✓", 1000)
+
+	ring, err := NewHashRing(8)
+	if err != nil {
+		f.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081, Weight: 3})
+	ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 8082})
+
+	f.Fuzz(func(t *testing.T, key string, count int) {
+		nodes, err := ring.GetNodes(key, count)
+		if err != nil {
+			return
+		}
+		if len(nodes) == 0 || len(nodes) > count || len(nodes) > ring.Size() {
+			t.Errorf("GetNodes(%q, %d) returned %d nodes", key, count, len(nodes))
+		}
+	})
+}
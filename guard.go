@@ -0,0 +1,68 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+)
+
+// Ownership guard errors
+var (
+	ErrNotOwner      = errors.New("node does not own the key")
+	ErrOwnershipLost = errors.New("node lost ownership of the key")
+)
+
+// IsOwner reports whether selfNodeID is the node responsible for key
+func (hr *HashRing) IsOwner(selfNodeID, key string) bool {
+	id, err := hr.GetOwnerID(key)
+	return err == nil && id == selfNodeID
+}
+
+// OwnerGuard runs keyed work only on the node owning the key, so every
+// process in a fleet can schedule the same jobs and each job runs once
+type OwnerGuard struct {
+	ring   *HashRing
+	selfID string
+}
+
+// NewOwnerGuard creates a guard for the local node selfID
+func NewOwnerGuard(ring *HashRing, selfID string) (*OwnerGuard, error) {
+	if ring == nil {
+		return nil, errors.New("ring cannot be nil")
+	}
+	if selfID == "" {
+		return nil, ErrInvalidNodeID
+	}
+	return &OwnerGuard{ring: ring, selfID: selfID}, nil
+}
+
+// Do runs fn if the local node owns key and returns ErrNotOwner otherwise.
+// Ownership is re-checked on every ring change while fn runs; if the key
+// moves away, fn's context is cancelled and Do returns ErrOwnershipLost, so
+// the new owner can take over without both running to completion.
+func (g *OwnerGuard) Do(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	// Subscribe before the first check so no change is missed in between
+	events, unsubscribe := g.ring.Watch(1)
+	defer unsubscribe()
+
+	if !g.ring.IsOwner(g.selfID, key) {
+		return ErrNotOwner
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	go func() {
+		for range events {
+			if !g.ring.IsOwner(g.selfID, key) {
+				cancel(ErrOwnershipLost)
+				return
+			}
+		}
+	}()
+
+	err := fn(runCtx)
+	if errors.Is(context.Cause(runCtx), ErrOwnershipLost) {
+		return ErrOwnershipLost
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsOwner(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if ring.IsOwner("node0", "key") {
+		t.Error("Expected no owner on an empty ring")
+	}
+
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("job%d", i)
+		owners := 0
+		for j := 0; j < 3; j++ {
+			if ring.IsOwner(fmt.Sprintf("node%d", j), key) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("Expected exactly one owner of %s, got %d", key, owners)
+		}
+	}
+}
+
+// ownedKey returns a key owned by nodeID
+func ownedKey(t *testing.T, ring *HashRing, nodeID string) string {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		if key := fmt.Sprintf("job%d", i); ring.IsOwner(nodeID, key) {
+			return key
+		}
+	}
+	t.Fatalf("No key owned by %s", nodeID)
+	return ""
+}
+
+func TestOwnerGuard(t *testing.T) {
+	ring, _ := NewHashRing(20)
+	ring.AddNode(&Node{ID: "self", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "other", Host: "localhost", Port: 8081})
+
+	if _, err := NewOwnerGuard(ring, ""); err == nil {
+		t.Error("Expected error for empty node ID")
+	}
+	guard, err := NewOwnerGuard(ring, "self")
+	if err != nil {
+		t.Fatalf("Failed to create guard: %v", err)
+	}
+
+	ran := false
+	err = guard.Do(context.Background(), ownedKey(t, ring, "other"), func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	if !errors.Is(err, ErrNotOwner) || ran {
+		t.Errorf("Expected ErrNotOwner without running, got %v (ran %v)", err, ran)
+	}
+
+	errJob := errors.New("job failed")
+	err = guard.Do(context.Background(), ownedKey(t, ring, "self"), func(ctx context.Context) error {
+		return errJob
+	})
+	if !errors.Is(err, errJob) {
+		t.Errorf("Expected the job's error, got %v", err)
+	}
+}
+
+func TestOwnerGuardOwnershipLost(t *testing.T) {
+	ring, _ := NewHashRing(20)
+	ring.AddNode(&Node{ID: "self", Host: "localhost", Port: 8080})
+	guard, _ := NewOwnerGuard(ring, "self")
+
+	err := guard.Do(context.Background(), "job", func(ctx context.Context) error {
+		// The local node is replaced, so the job moves elsewhere
+		ring.SetMembers([]*Node{{ID: "other", Host: "localhost", Port: 8081}})
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, ErrOwnershipLost) {
+		t.Errorf("Expected ErrOwnershipLost, got %v", err)
+	}
+}
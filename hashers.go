@@ -0,0 +1,230 @@
+package consistenthashing
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// ErrInvalidDigestMapping is returned when a hasher's DigestMapping selects
+// bytes outside its digest
+var ErrInvalidDigestMapping = errors.New("digest mapping out of range")
+
+// DigestMapping selects how a digest is turned into a ring position: which
+// bytes are used and in which byte order. The zero value reads the first
+// eight bytes big-endian. Matching another system's ring usually hinges on
+// these details.
+type DigestMapping struct {
+	Offset       int  // Index of the first digest byte used
+	Width        int  // Number of bytes used, 1 to 8; zero means 8
+	LittleEndian bool // Read the bytes least significant first
+}
+
+// KetamaDigestMapping reads the first four digest bytes little-endian, the
+// way libketama derives a key's point from its MD5 digest
+var KetamaDigestMapping = DigestMapping{Width: 4, LittleEndian: true}
+
+// Uint64 maps a digest to a ring position
+func (m DigestMapping) Uint64(digest []byte) uint64 {
+	if m == (DigestMapping{}) {
+		return binary.BigEndian.Uint64(digest)
+	}
+
+	b := digest[m.Offset : m.Offset+m.width()]
+	var v uint64
+	if m.LittleEndian {
+		for i := len(b) - 1; i >= 0; i-- {
+			v = v<<8 | uint64(b[i])
+		}
+	} else {
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+	}
+	return v
+}
+
+func (m DigestMapping) width() int {
+	if m.Width == 0 {
+		return 8
+	}
+	return m.Width
+}
+
+// validate checks that the mapping fits a digest of the given size
+func (m DigestMapping) validate(size int) error {
+	if m.Offset < 0 || m.Width < 0 || m.Width > 8 || m.Offset+m.width() > size {
+		return ErrInvalidDigestMapping
+	}
+	return nil
+}
+
+// seededKey appends seed, big-endian, followed by key to dst: the input a
+// seeded digest hasher hashes. Building it in the caller's buffer, rather than
+// writing to a hash.Hash, keeps key from escaping.
+func seededKey(dst []byte, seed uint64, key []byte) []byte {
+	dst = binary.BigEndian.AppendUint64(dst, seed)
+	return append(dst, key...)
+}
+
+// seededKeyBuffer is sized for the seed and typical keys
+type seededKeyBuffer [64]byte
+
+// digestHasher is implemented by the hashers built on a fixed-size digest
+type digestHasher interface {
+	digestMapping() (DigestMapping, int)
+}
+
+// SHA256Hasher implements HashFunction using SHA-256 (more secure)
+type SHA256Hasher struct {
+	Mapping DigestMapping
+	Seed    uint64 // Non-zero seeds are hashed ahead of the key
+}
+
+func (s *SHA256Hasher) Hash(key string) uint64 {
+	return s.HashBytes([]byte(key))
+}
+
+func (s *SHA256Hasher) HashBytes(key []byte) uint64 {
+	if s.Seed != 0 {
+		var buf seededKeyBuffer
+		key = seededKey(buf[:0], s.Seed, key)
+	}
+	h := sha256.Sum256(key)
+	return s.Mapping.Uint64(h[:])
+}
+
+func (s *SHA256Hasher) digestMapping() (DigestMapping, int) {
+	return s.Mapping, sha256.Size
+}
+
+// MD5Hasher implements HashFunction using MD5, the digest ketama derives its
+// points from. It exists for compatibility with ketama based rings and is not
+// suitable for any cryptographic use.
+type MD5Hasher struct {
+	Mapping DigestMapping
+	Seed    uint64 // Non-zero seeds are hashed ahead of the key
+}
+
+func (m *MD5Hasher) Hash(key string) uint64 {
+	return m.HashBytes([]byte(key))
+}
+
+func (m *MD5Hasher) HashBytes(key []byte) uint64 {
+	if m.Seed != 0 {
+		var buf seededKeyBuffer
+		key = seededKey(buf[:0], m.Seed, key)
+	}
+	h := md5.Sum(key)
+	return m.Mapping.Uint64(h[:])
+}
+
+func (m *MD5Hasher) digestMapping() (DigestMapping, int) {
+	return m.Mapping, md5.Size
+}
+
+// SHA1Hasher implements HashFunction using SHA-1, for compatibility with
+// rings built on it. SHA-1 is broken for cryptographic use and is only used
+// here to spread keys.
+type SHA1Hasher struct {
+	Mapping DigestMapping
+	Seed    uint64 // Non-zero seeds are hashed ahead of the key
+}
+
+func (s *SHA1Hasher) Hash(key string) uint64 {
+	return s.HashBytes([]byte(key))
+}
+
+func (s *SHA1Hasher) HashBytes(key []byte) uint64 {
+	if s.Seed != 0 {
+		var buf seededKeyBuffer
+		key = seededKey(buf[:0], s.Seed, key)
+	}
+	h := sha1.Sum(key)
+	return s.Mapping.Uint64(h[:])
+}
+
+func (s *SHA1Hasher) digestMapping() (DigestMapping, int) {
+	return s.Mapping, sha1.Size
+}
+
+// Parameters of AnalyzeHasher
+const (
+	analysisBuckets   = 64  // Equal slices of the hash space
+	avalancheMaxKeys  = 100 // Keys used for the avalanche estimate
+	avalancheMaxBytes = 64  // Leading key bytes flipped per key
+)
+
+// HasherReport describes how well a hash function spreads a key sample
+type HasherReport struct {
+	Keys           int     // Distinct keys analyzed
+	Collisions     int     // Keys whose hash equals an earlier key's
+	Buckets        int     // Equal slices of the uint64 space used for uniformity
+	ChiSquare      float64 // Chi-square of bucket counts, close to Buckets-1 for a uniform hash
+	MaxBucketRatio float64 // Fullest bucket over the expected count, 1 is perfect
+	Avalanche      float64 // Mean fraction of output bits flipped per input bit, 0.5 is ideal
+}
+
+// AnalyzeHasher reports collisions, bucket uniformity and an avalanche
+// estimate for a hash function over a key sample, to vet custom hashers
+// before deploying them. Uniformity is measured over the whole uint64 range,
+// so hashers that only produce small values, such as 32-bit mappings, score
+// badly even when their ring is balanced. Duplicate sample keys are ignored.
+func AnalyzeHasher(h HashFunction, sampleKeys []string) (HasherReport, error) {
+	if h == nil {
+		return HasherReport{}, errors.New("hash function cannot be nil")
+	}
+
+	report := HasherReport{Buckets: analysisBuckets}
+	counts := make([]int, analysisBuckets)
+	seenKeys := make(map[string]bool, len(sampleKeys))
+	seenHashes := make(map[uint64]bool, len(sampleKeys))
+
+	var flipped, trials int
+	for _, key := range sampleKeys {
+		if seenKeys[key] {
+			continue
+		}
+		seenKeys[key] = true
+		report.Keys++
+
+		hash := h.Hash(key)
+		if seenHashes[hash] {
+			report.Collisions++
+		}
+		seenHashes[hash] = true
+		counts[hash>>58]++ // Top six bits pick one of 64 buckets
+
+		if report.Keys > avalancheMaxKeys {
+			continue
+		}
+		buf := []byte(key)
+		for i := 0; i < len(buf) && i < avalancheMaxBytes; i++ {
+			for bit := 0; bit < 8; bit++ {
+				buf[i] ^= 1 << bit
+				flipped += bits.OnesCount64(hash ^ h.Hash(string(buf)))
+				trials++
+				buf[i] ^= 1 << bit
+			}
+		}
+	}
+
+	if report.Keys == 0 {
+		return HasherReport{}, errors.New("sample keys cannot be empty")
+	}
+
+	expected := float64(report.Keys) / analysisBuckets
+	for _, count := range counts {
+		diff := float64(count) - expected
+		report.ChiSquare += diff * diff / expected
+		report.MaxBucketRatio = max(report.MaxBucketRatio, float64(count)/expected)
+	}
+	if trials > 0 {
+		report.Avalanche = float64(flipped) / float64(trials*64)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,202 @@
+package consistenthashing
+
+import (
+	"hash/crc32"
+	"math/bits"
+)
+
+// XXHasher implements HashFunction using 64-bit xxHash (XXH64). It is much
+// faster than FNV for long keys and spreads keys well.
+type XXHasher struct {
+	Seed uint64
+}
+
+func (x *XXHasher) Hash(key string) uint64 {
+	return xxh64(key, x.Seed)
+}
+
+func (x *XXHasher) HashBytes(key []byte) uint64 {
+	return xxh64(key, x.Seed)
+}
+
+// Murmur3Hasher implements HashFunction using the first 64 bits of
+// MurmurHash3 x64_128, the value most Murmur3 libraries return as Sum64
+type Murmur3Hasher struct {
+	Seed uint64
+}
+
+func (m *Murmur3Hasher) Hash(key string) uint64 {
+	return murmur3(key, m.Seed)
+}
+
+func (m *Murmur3Hasher) HashBytes(key []byte) uint64 {
+	return murmur3(key, m.Seed)
+}
+
+// CRC32Hasher implements HashFunction using the IEEE CRC-32 checksum, for
+// parity with rings built on it. The checksum is moved to the top 32 bits of
+// the ring position so points span the whole hash space; their order, and so
+// the routing, is the same as ordering the checksums. A non-zero Seed, folded
+// to 32 bits, is the checksum's initial value.
+type CRC32Hasher struct {
+	Seed uint64
+}
+
+func (c *CRC32Hasher) Hash(key string) uint64 {
+	return c.HashBytes([]byte(key))
+}
+
+func (c *CRC32Hasher) HashBytes(key []byte) uint64 {
+	return uint64(crc32.Update(uint32(c.Seed^c.Seed>>32), crc32.IEEETable, key)) << 32
+}
+
+// hashShort is HashBytes computed a byte at a time. crc32.Update hands the key
+// to assembly that escape analysis can't see through, so the ring uses this
+// for its short binary keys to keep callers' buffers on the stack.
+func (c *CRC32Hasher) hashShort(key []byte) uint64 {
+	crc := ^uint32(c.Seed ^ c.Seed>>32)
+	for _, b := range key {
+		crc = crc32.IEEETable[byte(crc)^b] ^ crc>>8
+	}
+	return uint64(^crc) << 32
+}
+
+// XXH64 primes, variables so the seed arithmetic can wrap around
+var (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxh64 computes XXH64. It is generic so strings are hashed without a
+// conversion to []byte.
+func xxh64[T string | []byte](b T, seed uint64) uint64 {
+	n := len(b)
+	var h uint64
+	if n >= 32 {
+		v1 := seed + xxPrime1 + xxPrime2
+		v2 := seed + xxPrime2
+		v3 := seed
+		v4 := seed - xxPrime1
+		for len(b) >= 32 {
+			v1 = xxRound(v1, le64(b, 0))
+			v2 = xxRound(v2, le64(b, 8))
+			v3 = xxRound(v3, le64(b, 16))
+			v4 = xxRound(v4, le64(b, 24))
+			b = b[32:]
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) +
+			bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMerge(h, v1)
+		h = xxMerge(h, v2)
+		h = xxMerge(h, v3)
+		h = xxMerge(h, v4)
+	} else {
+		h = seed + xxPrime5
+	}
+	h += uint64(n)
+
+	for ; len(b) >= 8; b = b[8:] {
+		h ^= xxRound(0, le64(b, 0))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+	if len(b) >= 4 {
+		h ^= le32(b, 0) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		b = b[4:]
+	}
+	for i := 0; i < len(b); i++ {
+		h ^= uint64(b[i]) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	return bits.RotateLeft64(acc, 31) * xxPrime1
+}
+
+func xxMerge(acc, v uint64) uint64 {
+	acc ^= xxRound(0, v)
+	return acc*xxPrime1 + xxPrime4
+}
+
+// MurmurHash3 x64_128 constants
+const (
+	murmurC1 uint64 = 0x87c37b91114253d5
+	murmurC2 uint64 = 0x4cf5ad432745937f
+)
+
+// murmur3 returns the first half of MurmurHash3 x64_128
+func murmur3[T string | []byte](b T, seed uint64) uint64 {
+	n := len(b)
+	h1, h2 := seed, seed
+	for ; len(b) >= 16; b = b[16:] {
+		h1 ^= murmurMix1(le64(b, 0))
+		h1 = bits.RotateLeft64(h1, 27) + h2
+		h1 = h1*5 + 0x52dce729
+		h2 ^= murmurMix2(le64(b, 8))
+		h2 = bits.RotateLeft64(h2, 31) + h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	// The tail is read little-endian, up to eight bytes into each half
+	var k1, k2 uint64
+	for i := len(b) - 1; i >= 8; i-- {
+		k2 = k2<<8 | uint64(b[i])
+	}
+	for i := min(len(b), 8) - 1; i >= 0; i-- {
+		k1 = k1<<8 | uint64(b[i])
+	}
+	if len(b) > 8 {
+		h2 ^= murmurMix2(k2)
+	}
+	if len(b) > 0 {
+		h1 ^= murmurMix1(k1)
+	}
+
+	h1 ^= uint64(n)
+	h2 ^= uint64(n)
+	h1 += h2
+	h2 += h1
+	h1 = murmurFmix(h1)
+	h2 = murmurFmix(h2)
+	return h1 + h2
+}
+
+func murmurMix1(k uint64) uint64 {
+	return bits.RotateLeft64(k*murmurC1, 31) * murmurC2
+}
+
+func murmurMix2(k uint64) uint64 {
+	return bits.RotateLeft64(k*murmurC2, 33) * murmurC1
+}
+
+func murmurFmix(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
+
+// le64 reads eight bytes of b at i little-endian
+func le64[T string | []byte](b T, i int) uint64 {
+	return uint64(b[i]) | uint64(b[i+1])<<8 | uint64(b[i+2])<<16 | uint64(b[i+3])<<24 |
+		uint64(b[i+4])<<32 | uint64(b[i+5])<<40 | uint64(b[i+6])<<48 | uint64(b[i+7])<<56
+}
+
+// le32 reads four bytes of b at i little-endian
+func le32[T string | []byte](b T, i int) uint64 {
+	return uint64(b[i]) | uint64(b[i+1])<<8 | uint64(b[i+2])<<16 | uint64(b[i+3])<<24
+}
@@ -0,0 +1,84 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFastHashers(t *testing.T) {
+	tests := []struct {
+		name    string
+		hasher  HashFunction
+		vectors map[string]uint64 // Published reference values
+	}{
+		{"xxHash64", &XXHasher{}, map[string]uint64{
+			"":     0xef46db3751d8e999,
+			"a":    0xd24ec4f1a98c6e5b,
+			"asdf": 0x415872f599cea71e,
+			"Call me Ishmael. Some years ago--never mind how long precisely-": 0x02a2e85470d6fd96,
+		}},
+		{"Murmur3", &Murmur3Hasher{}, map[string]uint64{
+			"":             0,
+			"hello":        0xcbd8a7b341bd9b02,
+			"hello, world": 0x342fac623a5ebc8e,
+			"The quick brown fox jumps over the lazy dog.": 0xcd99481f9ee902c9,
+		}},
+		{"CRC32", &CRC32Hasher{}, map[string]uint64{
+			"":          0,
+			"123456789": 0xcbf43926 << 32,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, expected := range tt.vectors {
+				if got := tt.hasher.Hash(key); got != expected {
+					t.Errorf("Hash(%q): expected %x, got %x", key, expected, got)
+				}
+			}
+
+			// Every tail length agrees between Hash and HashBytes
+			long := strings.Repeat("0123456789abcdef", 5)
+			for i := 0; i <= len(long); i++ {
+				if tt.hasher.Hash(long[:i]) != tt.hasher.HashBytes([]byte(long[:i])) {
+					t.Errorf("Hash and HashBytes disagree for %d bytes", i)
+				}
+			}
+
+			if name := hasherName(tt.hasher); name != tt.name {
+				t.Errorf("Expected name %q, got %q", tt.name, name)
+			}
+
+			ring, err := NewHashRing(50, WithHashFunction(tt.hasher))
+			if err != nil {
+				t.Fatalf("Failed to create ring: %v", err)
+			}
+			for i := 0; i < 3; i++ {
+				ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+			}
+			if info := ring.GetRingInfo(); info["hash_function"] != tt.name {
+				t.Errorf("Expected GetRingInfo to report %q, got %v", tt.name, info["hash_function"])
+			}
+			restored, err := RestoreHashRing(ring.Snapshot())
+			if err != nil {
+				t.Fatalf("Failed to restore ring: %v", err)
+			}
+			if restored.Fingerprint() != ring.Fingerprint() {
+				t.Error("Expected restored ring to route identically")
+			}
+		})
+	}
+}
+
+func BenchmarkHashersLongKey(b *testing.B) {
+	key := strings.Repeat("session:0123456789abcdef:", 10)
+	for _, h := range []HashFunction{&FNVHasher{}, &XXHasher{}, &Murmur3Hasher{}, &CRC32Hasher{}} {
+		b.Run(hasherName(h), func(b *testing.B) {
+			b.SetBytes(int64(len(key)))
+			for i := 0; i < b.N; i++ {
+				h.Hash(key)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLegacyHashers(t *testing.T) {
+	tests := []struct {
+		name     string
+		hasher   HashFunction
+		expected uint64 // Leading eight digest bytes of the empty string
+	}{
+		{"MD5", &MD5Hasher{}, 0xd41d8cd98f00b204},
+		{"SHA-1", &SHA1Hasher{}, 0xda39a3ee5e6b4b0d},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.Hash(""); got != tt.expected {
+				t.Errorf("Expected %x, got %x", tt.expected, got)
+			}
+			for _, key := range []string{"a", "user:123", "ketama"} {
+				if tt.hasher.Hash(key) != tt.hasher.HashBytes([]byte(key)) {
+					t.Errorf("Hash and HashBytes disagree for %q", key)
+				}
+			}
+
+			if name := hasherName(tt.hasher); name != tt.name {
+				t.Errorf("Expected name %q, got %q", tt.name, name)
+			}
+			if _, ok := hasherByName(tt.name).(HashFunction); !ok {
+				t.Errorf("Expected %q to resolve to a hasher", tt.name)
+			}
+
+			ring, err := NewHashRing(50, WithHashFunction(tt.hasher))
+			if err != nil {
+				t.Fatalf("Failed to create ring: %v", err)
+			}
+			for i := 0; i < 3; i++ {
+				ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+			}
+			restored, err := RestoreHashRing(ring.Snapshot())
+			if err != nil {
+				t.Fatalf("Failed to restore ring: %v", err)
+			}
+			if restored.Fingerprint() != ring.Fingerprint() {
+				t.Error("Expected restored ring to route identically")
+			}
+		})
+	}
+}
+
+func TestDigestMapping(t *testing.T) {
+	digest := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a}
+
+	tests := []struct {
+		name     string
+		mapping  DigestMapping
+		expected uint64
+	}{
+		{"default", DigestMapping{}, 0x0102030405060708},
+		{"little endian", DigestMapping{LittleEndian: true}, 0x0807060504030201},
+		{"offset", DigestMapping{Offset: 2}, 0x030405060708090a},
+		{"ketama", KetamaDigestMapping, 0x04030201},
+		{"two bytes", DigestMapping{Offset: 8, Width: 2}, 0x090a},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mapping.validate(len(digest)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := tt.mapping.Uint64(digest); got != tt.expected {
+				t.Errorf("Expected %x, got %x", tt.expected, got)
+			}
+		})
+	}
+
+	// libketama's point for a key is the first four MD5 bytes, little-endian;
+	// md5("") starts d4 1d 8c d9
+	ketama := &MD5Hasher{Mapping: KetamaDigestMapping}
+	if got := ketama.Hash(""); got != 0xd98c1dd4 {
+		t.Errorf("Expected ketama point d98c1dd4, got %x", got)
+	}
+}
+
+func TestDigestMappingValidation(t *testing.T) {
+	invalid := []HashFunction{
+		&MD5Hasher{Mapping: DigestMapping{Offset: 10}},
+		&SHA1Hasher{Mapping: DigestMapping{Width: 9}},
+		&SHA256Hasher{Mapping: DigestMapping{Offset: -1}},
+	}
+	for _, hasher := range invalid {
+		if _, err := NewHashRing(10, WithHashFunction(hasher)); !errors.Is(err, ErrInvalidDigestMapping) {
+			t.Errorf("Expected ErrInvalidDigestMapping for %+v, got %v", hasher, err)
+		}
+	}
+
+	// A remapped hasher can't be restored by name, so snapshots call it custom
+	ring, err := NewHashRing(10, WithHashFunction(&MD5Hasher{Mapping: KetamaDigestMapping}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if name := ring.Snapshot().HashFunction; name != "Custom" {
+		t.Errorf("Expected Custom hash function, got %q", name)
+	}
+}
+
+// truncatingHasher keeps only the low byte of FNV, a deliberately bad hasher
+type truncatingHasher struct{}
+
+func (truncatingHasher) Hash(key string) uint64 {
+	return (&FNVHasher{}).Hash(key) & 0xff
+}
+
+func (truncatingHasher) HashBytes(key []byte) uint64 {
+	return (&FNVHasher{}).HashBytes(key) & 0xff
+}
+
+func TestAnalyzeHasher(t *testing.T) {
+	keys := make([]string, 20000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user:%d", i)
+	}
+
+	for _, hasher := range []HashFunction{&SHA256Hasher{}, &MD5Hasher{}, &SHA1Hasher{}} {
+		report, err := AnalyzeHasher(hasher, keys)
+		if err != nil {
+			t.Fatalf("Failed to analyze %T: %v", hasher, err)
+		}
+		if report.Keys != len(keys) || report.Collisions != 0 {
+			t.Errorf("%T: unexpected report %+v", hasher, report)
+		}
+		// 63 degrees of freedom; 120 is far beyond the 99.9th percentile
+		if report.ChiSquare > 120 || report.MaxBucketRatio > 1.5 {
+			t.Errorf("%T: expected a uniform spread, got %+v", hasher, report)
+		}
+		if report.Avalanche < 0.45 || report.Avalanche > 0.55 {
+			t.Errorf("%T: expected avalanche near 0.5, got %f", hasher, report.Avalanche)
+		}
+	}
+
+	report, err := AnalyzeHasher(truncatingHasher{}, append(keys, keys[0]))
+	if err != nil {
+		t.Fatalf("Failed to analyze: %v", err)
+	}
+	if report.Keys != len(keys) {
+		t.Errorf("Expected duplicate keys to be ignored, got %d keys", report.Keys)
+	}
+	if report.Collisions < len(keys)-256 {
+		t.Errorf("Expected collisions beyond 256 distinct values, got %d", report.Collisions)
+	}
+	if report.MaxBucketRatio != analysisBuckets || report.Avalanche > 0.1 {
+		t.Errorf("Expected a degenerate report, got %+v", report)
+	}
+
+	if _, err := AnalyzeHasher(nil, keys); err == nil {
+		t.Error("Expected error for nil hasher")
+	}
+	if _, err := AnalyzeHasher(&FNVHasher{}, nil); err == nil {
+		t.Error("Expected error for empty sample")
+	}
+}
@@ -0,0 +1,34 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotReady is wrapped by the errors of a ReadinessCheck
+var ErrNotReady = errors.New("ring not ready")
+
+// ReadinessCheck returns a check for health endpoints that fails while the
+// ring has fewer than minNodes active members or fails ValidateRing.
+// Draining and down members do not count as active.
+func (hr *HashRing) ReadinessCheck(minNodes int) func() error {
+	return func() error {
+		hr.mu.RLock()
+		defer hr.mu.RUnlock()
+
+		active := 0
+		for id := range hr.nodes {
+			if hr.states[id] == NodeActive {
+				active++
+			}
+		}
+		if active < minNodes {
+			return fmt.Errorf("%w: %d active nodes, need %d", ErrNotReady, active, minNodes)
+		}
+
+		if err := hr.validateLocked(); err != nil {
+			return fmt.Errorf("%w: %v", ErrNotReady, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package consistenthashing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadinessCheck(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ready := ring.ReadinessCheck(2)
+
+	if err := ready(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("Expected an empty ring to be unready, got %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "a", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "b", Host: "localhost", Port: 8081})
+	if err := ready(); err != nil {
+		t.Errorf("Expected ready, got %v", err)
+	}
+
+	ring.SetNodeState("b", NodeDown)
+	if err := ready(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("Expected a down member to make the ring unready, got %v", err)
+	}
+	ring.SetNodeState("b", NodeActive)
+
+	// Corrupt the ring so validation fails
+	ring.virtualNodes[0], ring.virtualNodes[1] = ring.virtualNodes[1], ring.virtualNodes[0]
+	if err := ready(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("Expected validation failures to make the ring unready, got %v", err)
+	}
+}
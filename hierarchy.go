@@ -0,0 +1,299 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placement is the full path chosen for a key by a HierarchicalRing
+type Placement struct {
+	Region string
+	Zone   string
+	Node   *Node
+}
+
+// Salts that decorrelate the region and zone choices made from one key hash
+const (
+	regionSalt = 0x9e3779b97f4a7c15
+	zoneSalt   = 0xc2b2ae3d27d4eb4f
+)
+
+// mix64 is the SplitMix64 finalizer. The level rings use it to spread keys that
+// only differ in their last bytes, which FNV leaves clustered on the ring.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// labelPoint is a weighted point of a region or zone on a label ring
+type labelPoint struct {
+	hash  uint64
+	label string
+}
+
+// labelRing is a minimal consistent hash ring over plain labels, used for the
+// region and zone levels of a HierarchicalRing
+type labelRing struct {
+	points []labelPoint
+}
+
+// set places count points for label, replacing any existing ones
+func (lr *labelRing) set(level, label string, count int, hash func(string) uint64) {
+	lr.remove(label)
+	for i := 0; i < count; i++ {
+		lr.points = append(lr.points, labelPoint{
+			hash:  mix64(hash(fmt.Sprintf("%s:%s:replica:%d", level, label, i))),
+			label: label,
+		})
+	}
+	sort.Slice(lr.points, func(i, j int) bool {
+		return lr.points[i].hash < lr.points[j].hash
+	})
+}
+
+// remove drops all points of label
+func (lr *labelRing) remove(label string) {
+	writeIndex := 0
+	for _, p := range lr.points {
+		if p.label != label {
+			lr.points[writeIndex] = p
+			writeIndex++
+		}
+	}
+	lr.points = lr.points[:writeIndex]
+}
+
+// get returns the label owning hash, or "" for an empty ring
+func (lr *labelRing) get(hash uint64) string {
+	if len(lr.points) == 0 {
+		return ""
+	}
+	idx := sort.Search(len(lr.points), func(i int) bool {
+		return lr.points[i].hash >= hash
+	})
+	if idx == len(lr.points) {
+		idx = 0
+	}
+	return lr.points[idx].label
+}
+
+// region holds the zone level of one region
+type region struct {
+	zones     labelRing
+	zoneRings map[string]*HashRing
+}
+
+// nodeLocation records where a node was added
+type nodeLocation struct {
+	region, zone string
+}
+
+// HierarchicalRing places keys in three steps: a region ring picks the region,
+// that region's zone ring picks the zone, and the zone's HashRing picks the
+// node. Regions and zones carry their own weights, independent of node weights.
+type HierarchicalRing struct {
+	virtualReplicas int
+	opts            []Option
+	hasher          HashFunction
+	regionRing      labelRing
+	regions         map[string]*region
+	regionWeights   map[string]int
+	zoneWeights     map[string]int // Keyed by "region/zone"
+	locations       map[string]nodeLocation
+	mu              sync.RWMutex
+}
+
+// NewHierarchicalRing creates an empty hierarchical ring. The options apply to
+// every zone's node ring; WithHashFunction also selects the hash for the
+// region and zone levels.
+func NewHierarchicalRing(virtualReplicas int, opts ...Option) (*HierarchicalRing, error) {
+	probe, err := NewHashRing(virtualReplicas, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HierarchicalRing{
+		virtualReplicas: virtualReplicas,
+		opts:            opts,
+		hasher:          probe.hasher,
+		regions:         make(map[string]*region),
+		regionWeights:   make(map[string]int),
+		zoneWeights:     make(map[string]int),
+		locations:       make(map[string]nodeLocation),
+	}, nil
+}
+
+// SetRegionWeight sets the relative weight of a region (default 1)
+func (h *HierarchicalRing) SetRegionWeight(regionName string, weight int) error {
+	if strings.TrimSpace(regionName) == "" {
+		return errors.New("region cannot be empty")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.regionWeights[regionName] = weight
+	if _, exists := h.regions[regionName]; exists {
+		h.placeRegionLocked(regionName)
+	}
+	return nil
+}
+
+// SetZoneWeight sets the relative weight of a zone within its region (default 1)
+func (h *HierarchicalRing) SetZoneWeight(regionName, zone string, weight int) error {
+	if strings.TrimSpace(regionName) == "" || strings.TrimSpace(zone) == "" {
+		return errors.New("region and zone cannot be empty")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.zoneWeights[regionName+"/"+zone] = weight
+	if r, exists := h.regions[regionName]; exists {
+		if _, exists := r.zoneRings[zone]; exists {
+			h.placeZoneLocked(r, regionName, zone)
+		}
+	}
+	return nil
+}
+
+// levelPoints turns a level weight into a number of ring points
+func (h *HierarchicalRing) levelPoints(weight int) int {
+	if weight <= 0 {
+		weight = 1
+	}
+	return h.virtualReplicas * weight
+}
+
+func (h *HierarchicalRing) placeRegionLocked(regionName string) {
+	h.regionRing.set("region", regionName, h.levelPoints(h.regionWeights[regionName]), h.hasher.Hash)
+}
+
+func (h *HierarchicalRing) placeZoneLocked(r *region, regionName, zone string) {
+	r.zones.set("zone", zone, h.levelPoints(h.zoneWeights[regionName+"/"+zone]), h.hasher.Hash)
+}
+
+// AddNode adds a node to the given region and zone, creating them as needed
+func (h *HierarchicalRing) AddNode(regionName, zone string, node *Node) error {
+	if strings.TrimSpace(regionName) == "" || strings.TrimSpace(zone) == "" {
+		return errors.New("region and zone cannot be empty")
+	}
+	if node == nil {
+		return errors.New("node cannot be nil")
+	}
+	if err := node.Validate(); err != nil {
+		return fmt.Errorf("invalid node: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if loc, exists := h.locations[node.ID]; exists {
+		if loc.region != regionName || loc.zone != zone {
+			return fmt.Errorf("node %s already placed in %s/%s", node.ID, loc.region, loc.zone)
+		}
+		return nil // Node already exists, not an error
+	}
+
+	r, exists := h.regions[regionName]
+	if !exists {
+		r = &region{zoneRings: make(map[string]*HashRing)}
+	}
+	zoneRing, exists := r.zoneRings[zone]
+	if !exists {
+		var err error
+		if zoneRing, err = NewHashRing(h.virtualReplicas, h.opts...); err != nil {
+			return err
+		}
+	}
+
+	if err := zoneRing.AddNode(node); err != nil {
+		return err
+	}
+
+	// Only regions and zones with nodes take part in placement
+	if _, exists := r.zoneRings[zone]; !exists {
+		r.zoneRings[zone] = zoneRing
+		h.placeZoneLocked(r, regionName, zone)
+	}
+	if _, exists := h.regions[regionName]; !exists {
+		h.regions[regionName] = r
+		h.placeRegionLocked(regionName)
+	}
+	h.locations[node.ID] = nodeLocation{region: regionName, zone: zone}
+
+	return nil
+}
+
+// RemoveNode removes a node, dropping its zone and region once they are empty
+func (h *HierarchicalRing) RemoveNode(nodeID string) error {
+	if strings.TrimSpace(nodeID) == "" {
+		return ErrInvalidNodeID
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	loc, exists := h.locations[nodeID]
+	if !exists {
+		return ErrNodeNotFound
+	}
+
+	r := h.regions[loc.region]
+	zoneRing := r.zoneRings[loc.zone]
+	if err := zoneRing.RemoveNode(nodeID); err != nil {
+		return err
+	}
+	delete(h.locations, nodeID)
+
+	if zoneRing.Size() == 0 {
+		delete(r.zoneRings, loc.zone)
+		r.zones.remove(loc.zone)
+	}
+	if len(r.zoneRings) == 0 {
+		delete(h.regions, loc.region)
+		h.regionRing.remove(loc.region)
+	}
+
+	return nil
+}
+
+// Locate returns the region, zone and node responsible for a key
+func (h *HierarchicalRing) Locate(key string) (Placement, error) {
+	if key == "" {
+		return Placement{}, ErrEmptyKey
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	keyHash := h.hasher.Hash(key)
+	regionName := h.regionRing.get(mix64(keyHash ^ regionSalt))
+	if regionName == "" {
+		return Placement{}, ErrEmptyRing
+	}
+	r := h.regions[regionName]
+	zone := r.zones.get(mix64(keyHash ^ zoneSalt))
+
+	node, err := r.zoneRings[zone].GetNode(key)
+	if err != nil {
+		return Placement{}, err
+	}
+
+	return Placement{Region: regionName, Zone: zone, Node: node}, nil
+}
+
+// Size returns the number of nodes across all regions
+func (h *HierarchicalRing) Size() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.locations)
+}
@@ -0,0 +1,70 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHierarchicalRing(t *testing.T) {
+	ring, err := NewHierarchicalRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if _, err := ring.Locate("key"); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	ring.AddNode("eu", "eu-1a", &Node{ID: "eu-a1", Host: "10.0.0.1", Port: 8080})
+	ring.AddNode("eu", "eu-1b", &Node{ID: "eu-b1", Host: "10.0.0.2", Port: 8080})
+	ring.AddNode("us", "us-1a", &Node{ID: "us-a1", Host: "10.1.0.1", Port: 8080})
+	ring.AddNode("us", "us-1a", &Node{ID: "us-a2", Host: "10.1.0.2", Port: 8080})
+
+	if ring.Size() != 4 {
+		t.Errorf("Expected 4 nodes, got %d", ring.Size())
+	}
+
+	regions := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		p, err := ring.Locate(fmt.Sprintf("key_%d", i))
+		if err != nil {
+			t.Fatalf("Failed to locate key: %v", err)
+		}
+		// The node must belong to the chosen region and zone
+		if p.Node.ID[:2] != p.Region || p.Zone[:2] != p.Region {
+			t.Errorf("Inconsistent placement %s/%s/%s", p.Region, p.Zone, p.Node.ID)
+		}
+		regions[p.Region]++
+	}
+	if regions["eu"] == 0 || regions["us"] == 0 {
+		t.Errorf("Expected keys in both regions, got %v", regions)
+	}
+
+	// Weighting a region up shifts keys towards it
+	ring.SetRegionWeight("us", 5)
+	us := 0
+	for i := 0; i < 1000; i++ {
+		if p, _ := ring.Locate(fmt.Sprintf("key_%d", i)); p.Region == "us" {
+			us++
+		}
+	}
+	if us <= regions["us"] {
+		t.Errorf("Expected more keys in us after increasing its weight, got %d (was %d)", us, regions["us"])
+	}
+
+	// Removing the last node of a region removes the region
+	ring.RemoveNode("eu-a1")
+	ring.RemoveNode("eu-b1")
+	for i := 0; i < 100; i++ {
+		if p, _ := ring.Locate(fmt.Sprintf("key_%d", i)); p.Region != "us" {
+			t.Fatalf("Expected all keys in us after eu was emptied, got %s", p.Region)
+		}
+	}
+
+	if err := ring.RemoveNode("eu-a1"); err != ErrNodeNotFound {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if err := ring.AddNode("eu", "eu-1a", &Node{ID: "us-a1", Host: "10.1.0.1", Port: 8080}); err == nil {
+		t.Error("Expected error when adding a node to a second location")
+	}
+}
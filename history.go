@@ -0,0 +1,195 @@
+package consistenthashing
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrGenerationUnavailable is returned for generations that are not in the
+// ring's history, either because they are too old or because history is off
+var ErrGenerationUnavailable = errors.New("generation not in ring history")
+
+// WithHistory keeps the ring state of the last n generations, including the
+// current one, so keys can be routed against an earlier generation with
+// GetNodeAt or AtGeneration. Each retained generation holds a copy of the
+// virtual nodes.
+func WithHistory(n int) Option {
+	return func(hr *HashRing) {
+		if n > 0 {
+			hr.history = &ringHistory{size: n}
+		}
+	}
+}
+
+// ringHistory is a bounded list of past ring states, oldest first
+type ringHistory struct {
+	size    int
+	entries []*RingView
+}
+
+// advanceGenerationLocked starts a new generation after a change to routing
+// and records it in the history, first re-applying the virtual node cap to
+// the new membership. Callers must hold the write lock.
+func (hr *HashRing) advanceGenerationLocked() {
+	hr.rescaleLocked()
+	hr.generation++
+	hr.recordHistoryLocked()
+}
+
+// recordHistoryLocked appends the current state to the history, dropping the
+// oldest entry when full
+func (hr *HashRing) recordHistoryLocked() {
+	h := hr.history
+	if h == nil {
+		return
+	}
+
+	if len(h.entries) == h.size {
+		clear(h.entries[:1])
+		h.entries = append(h.entries[:0], h.entries[1:]...)
+	}
+	h.entries = append(h.entries, &RingView{
+		generation:   hr.generation,
+		started:      hr.clock.Now(),
+		virtualNodes: slices.Clone(hr.virtualNodes),
+		nodeCount:    len(hr.nodes),
+		affinity:     maps.Clone(hr.affinity),
+		hasher:       hr.hasher,
+		emptyKey:     hr.emptyKeyPolicy,
+		emptyKeyNode: hr.emptyKeyNode,
+	})
+}
+
+// AtGeneration returns a read-only view of the ring as it was at the given
+// generation, for questions such as where a key lived before an incident.
+// The generation must be retained by WithHistory.
+func (hr *HashRing) AtGeneration(generation uint64) (*RingView, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if hr.history == nil {
+		return nil, ErrGenerationUnavailable
+	}
+	for _, view := range hr.history.entries {
+		if view.generation == generation {
+			return view, nil
+		}
+	}
+	return nil, ErrGenerationUnavailable
+}
+
+// GenerationAt returns the generation that was current at t
+func (hr *HashRing) GenerationAt(t time.Time) (uint64, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if hr.history == nil {
+		return 0, ErrGenerationUnavailable
+	}
+
+	// Times before the oldest retained generation are unknown
+	entries := hr.history.entries
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].started.After(t)
+	})
+	if i == 0 {
+		return 0, ErrGenerationUnavailable
+	}
+	return entries[i-1].generation, nil
+}
+
+// GetNodeAt returns the node that owned key at the given generation, so long
+// running operations can keep routing to the owner they started with. The
+// generation must be retained by WithHistory.
+func (hr *HashRing) GetNodeAt(key string, generation uint64) (*Node, error) {
+	view, err := hr.AtGeneration(generation)
+	if err != nil {
+		return nil, err
+	}
+	return view.GetNode(key)
+}
+
+// RingView is an immutable view of the ring at one generation. Nodes keep
+// the fields they had then, so later weight updates do not rewrite history.
+type RingView struct {
+	generation   uint64
+	started      time.Time
+	virtualNodes []VirtualNode
+	nodeCount    int
+	affinity     map[string]string
+	hasher       HashFunction
+	emptyKey     EmptyKeyPolicy
+	emptyKeyNode *Node
+}
+
+// Generation returns the generation the view shows
+func (v *RingView) Generation() uint64 {
+	return v.generation
+}
+
+// Started returns when the generation became current
+func (v *RingView) Started() time.Time {
+	return v.started
+}
+
+// Size returns the number of physical nodes in the view
+func (v *RingView) Size() int {
+	return v.nodeCount
+}
+
+// GetNode returns the node that owned key in the view. The empty key is
+// treated as the ring's EmptyKeyPolicy says.
+func (v *RingView) GetNode(key string) (*Node, error) {
+	if key == "" {
+		if node, handled, err := routeEmptyKey(v.emptyKey, v.emptyKeyNode); handled {
+			return node, err
+		}
+	}
+	if len(v.virtualNodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	return v.virtualNodes[searchVirtualNodes(v.virtualNodes, v.hash(key))].Node, nil
+}
+
+// GetNodes returns the count replicas of key in the view, in ring order
+func (v *RingView) GetNodes(key string, count int) ([]*Node, error) {
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	if key == "" {
+		if node, handled, err := routeEmptyKey(v.emptyKey, v.emptyKeyNode); handled {
+			if err != nil {
+				return nil, err
+			}
+			return []*Node{node}, nil
+		}
+	}
+	if len(v.virtualNodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	dst := make([]*Node, 0, min(count, v.nodeCount))
+	return appendReplicas(dst, v.virtualNodes, v.nodeCount, v.hash(key), count), nil
+}
+
+// GetAllNodes returns the nodes in the view, sorted by ID
+func (v *RingView) GetAllNodes() []*Node {
+	seen := make(map[*Node]bool, v.nodeCount)
+	nodes := make([]*Node, 0, v.nodeCount)
+	for _, vnode := range v.virtualNodes {
+		if !seen[vnode.Node] {
+			seen[vnode.Node] = true
+			nodes = append(nodes, vnode.Node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+func (v *RingView) hash(key string) uint64 {
+	return v.hasher.Hash(routingKey(v.affinity, key))
+}
@@ -0,0 +1,151 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGetNodeAt(t *testing.T) {
+	ring, err := NewHashRing(50, WithHistory(3))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if _, err := ring.GetNodeAt("key", 0); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected ErrEmptyRing at generation 0, got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	started := ring.Generation()
+
+	keys := make([]string, 200)
+	owners := make(map[string]string, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+		owners[keys[i]], _ = ring.GetOwnerID(keys[i])
+	}
+
+	ring.RemoveNode("node2")
+	ring.UpdateNodeWeight("node0", 3)
+
+	moved := 0
+	for _, key := range keys {
+		node, err := ring.GetNodeAt(key, started)
+		if err != nil {
+			t.Fatalf("Failed to route at generation %d: %v", started, err)
+		}
+		if node.ID != owners[key] {
+			t.Errorf("Expected %q on %s at generation %d, got %s", key, owners[key], started, node.ID)
+		}
+		if current, _ := ring.GetOwnerID(key); current != node.ID {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("Expected some keys to have moved since")
+	}
+
+	// Nodes keep the weight they had at the time
+	for _, key := range keys {
+		if node, _ := ring.GetNodeAt(key, started); node.ID == "node0" && node.Weight != 0 {
+			t.Errorf("Expected node0's old weight, got %d", node.Weight)
+		}
+	}
+
+	// Only the last three generations are kept
+	ring.RemoveNode("node3")
+	if _, err := ring.GetNodeAt("key", started); !errors.Is(err, ErrGenerationUnavailable) {
+		t.Errorf("Expected ErrGenerationUnavailable, got %v", err)
+	}
+	if node, err := ring.GetNodeAt("key", ring.Generation()); err != nil || node.ID != mustOwner(ring, "key") {
+		t.Errorf("Expected the current generation to match live routing, got %v, %v", node, err)
+	}
+}
+
+func mustOwner(ring *HashRing, key string) string {
+	id, _ := ring.GetOwnerID(key)
+	return id
+}
+
+func TestGetNodeAtAffinity(t *testing.T) {
+	ring, _ := NewHashRing(50, WithHistory(4))
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	ring.SetAffinityGroup("group", "a:", "b:")
+	grouped := ring.Generation()
+	ring.RemoveAffinityGroup("group")
+
+	a, _ := ring.GetNodeAt("a:1", grouped)
+	b, _ := ring.GetNodeAt("b:1", grouped)
+	if a.ID != b.ID {
+		t.Errorf("Expected the affinity group in effect at generation %d, got %s and %s", grouped, a.ID, b.ID)
+	}
+}
+
+func TestGetNodeAtWithoutHistory(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+	if _, err := ring.GetNodeAt("key", ring.Generation()); !errors.Is(err, ErrGenerationUnavailable) {
+		t.Errorf("Expected ErrGenerationUnavailable, got %v", err)
+	}
+
+	// Restored rings start their history at the snapshot's generation
+	restored, err := RestoreHashRing(ring.Snapshot(), WithHistory(2))
+	if err != nil {
+		t.Fatalf("Failed to restore ring: %v", err)
+	}
+	if node, err := restored.GetNodeAt("key", ring.Generation()); err != nil || node.ID != "node0" {
+		t.Errorf("Expected node0 at the restored generation, got %v, %v", node, err)
+	}
+	if _, err := restored.GetNodeAt("key", 0); !errors.Is(err, ErrGenerationUnavailable) {
+		t.Errorf("Expected intermediate restore generations to be absent, got %v", err)
+	}
+}
+
+func TestAtGeneration(t *testing.T) {
+	ring, _ := NewHashRing(50, WithHistory(5))
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	before := ring.Generation()
+	replicas, _ := ring.GetNodes("user:42", 2)
+	beforeTime := time.Now()
+
+	time.Sleep(2 * time.Millisecond)
+	ring.SetMembers([]*Node{{ID: "node9", Host: "localhost", Port: 9000}})
+
+	view, err := ring.AtGeneration(before)
+	if err != nil {
+		t.Fatalf("Failed to get view: %v", err)
+	}
+	if view.Generation() != before || view.Size() != 3 || len(view.GetAllNodes()) != 3 {
+		t.Errorf("Unexpected view at generation %d: size %d", view.Generation(), view.Size())
+	}
+	nodes, err := view.GetNodes("user:42", 2)
+	if err != nil || nodes[0] != replicas[0] || nodes[1] != replicas[1] {
+		t.Errorf("Expected replicas %v, got %v (%v)", replicas, nodes, err)
+	}
+	if _, err := view.GetNodes("user:42", 0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("Expected ErrInvalidCount, got %v", err)
+	}
+
+	// "Where did this key live a moment ago?"
+	generation, err := ring.GenerationAt(beforeTime)
+	if err != nil || generation != before {
+		t.Errorf("Expected generation %d at %v, got %d (%v)", before, beforeTime, generation, err)
+	}
+	if generation, _ := ring.GenerationAt(time.Now()); generation != ring.Generation() {
+		t.Errorf("Expected the current generation now, got %d", generation)
+	}
+	if _, err := ring.GenerationAt(view.Started().Add(-time.Hour)); !errors.Is(err, ErrGenerationUnavailable) {
+		t.Errorf("Expected ErrGenerationUnavailable before the history, got %v", err)
+	}
+
+	if _, err := ring.AtGeneration(ring.Generation() + 1); !errors.Is(err, ErrGenerationUnavailable) {
+		t.Errorf("Expected ErrGenerationUnavailable, got %v", err)
+	}
+}
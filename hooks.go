@@ -0,0 +1,59 @@
+package consistenthashing
+
+import "time"
+
+// Op identifies a ring operation passed to hooks
+type Op string
+
+// Operations reported to hooks
+const (
+	OpGetNode    Op = "GetNode"
+	OpGetNodes   Op = "GetNodes"
+	OpAddNode    Op = "AddNode"
+	OpRemoveNode Op = "RemoveNode"
+)
+
+// BeforeHook is called before an operation with the key it concerns. For
+// AddNode and RemoveNode the key is the node ID.
+type BeforeHook func(op Op, key string)
+
+// AfterHook is called after an operation with the node it returned, added or
+// removed, the error, and how long the operation took. GetNodes reports its
+// first replica.
+type AfterHook func(op Op, key string, node *Node, err error, dur time.Duration)
+
+// WithHooks installs hooks around GetNode, GetNodes, AddNode and RemoveNode,
+// a generic interception point for custom metrics, logging or chaos
+// injection. Either hook may be nil. Hooks run outside the ring lock, so they
+// may call back into the ring, and they must be safe for concurrent use.
+func WithHooks(before BeforeHook, after AfterHook) Option {
+	return func(hr *HashRing) {
+		if before == nil && after == nil {
+			hr.hooks = nil
+			return
+		}
+		hr.hooks = &hooks{before: before, after: after}
+	}
+}
+
+// hooks holds the hooks installed with WithHooks
+type hooks struct {
+	before BeforeHook
+	after  AfterHook
+	clock  Clock
+}
+
+// begin runs the before hook and returns the operation's start time
+func (h *hooks) begin(op Op, key string) time.Time {
+	if h.before != nil {
+		h.before(op, key)
+	}
+	return h.clock.Now()
+}
+
+// end runs the after hook
+func (h *hooks) end(op Op, key string, node *Node, err error, start time.Time) {
+	if h.after != nil {
+		h.after(op, key, node, err, h.clock.Now().Sub(start))
+	}
+}
@@ -0,0 +1,70 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithHooks(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	before := func(op Op, key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, fmt.Sprintf("before %s %s", op, key))
+	}
+	after := func(op Op, key string, node *Node, err error, dur time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := "-"
+		if node != nil {
+			id = node.ID
+		}
+		if dur < 0 {
+			t.Errorf("Negative duration for %s", op)
+		}
+		calls = append(calls, fmt.Sprintf("after %s %s %s %v", op, key, id, err != nil))
+	}
+
+	ring, err := NewHashRing(10, WithHooks(before, after))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.GetNode("early")
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.GetNode("key")
+	ring.GetNodes("key", 2)
+	ring.RemoveNode("node1")
+	ring.RemoveNode("node1")
+
+	expected := []string{
+		"before GetNode early", "after GetNode early - true",
+		"before AddNode node1", "after AddNode node1 node1 false",
+		"before GetNode key", "after GetNode key node1 false",
+		"before GetNodes key", "after GetNodes key node1 false",
+		"before RemoveNode node1", "after RemoveNode node1 node1 false",
+		"before RemoveNode node1", "after RemoveNode node1 - true",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected hook calls:\n%s\nexpected:\n%s", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestHooksMayReenterRing(t *testing.T) {
+	var ring *HashRing
+	sizes := 0
+	ring, _ = NewHashRing(10, WithHooks(nil, func(op Op, key string, node *Node, err error, dur time.Duration) {
+		// Hooks run outside the lock, so this must not deadlock
+		sizes += ring.Size()
+	}))
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.GetNode("key")
+	if sizes != 2 {
+		t.Errorf("Expected the hook to see 1 node twice, got %d", sizes)
+	}
+}
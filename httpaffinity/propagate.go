@@ -0,0 +1,63 @@
+package httpaffinity
+
+import (
+	"net/http"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// Headers used to carry shard decisions between services
+const (
+	ShardKeyHeader  = "X-Shard-Key"
+	ShardNodeHeader = "X-Shard-Node"
+)
+
+// Transport is an http.RoundTripper that copies the shard key and node ID
+// from each request's context into routing headers, so downstream services
+// see the caller's shard decision. Requests without them pass through
+// unchanged.
+type Transport struct {
+	Base http.RoundTripper // Defaults to http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	key, hasKey := consistenthashing.ShardKeyFromContext(r.Context())
+	node, hasNode := consistenthashing.ShardNodeFromContext(r.Context())
+	if !hasKey && !hasNode {
+		return base.RoundTrip(r)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	r = r.Clone(r.Context())
+	if hasKey {
+		r.Header.Set(ShardKeyHeader, key)
+	}
+	if hasNode {
+		r.Header.Set(ShardNodeHeader, node.ID)
+	}
+	return base.RoundTrip(r)
+}
+
+// Propagate wraps next so the shard key and node set by an upstream Transport
+// are available from the request context. The node is resolved against ring
+// and dropped if it is not a member.
+func Propagate(ring consistenthashing.Ring, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if key := r.Header.Get(ShardKeyHeader); key != "" {
+			ctx = consistenthashing.WithShardKey(ctx, key)
+		}
+		if id := r.Header.Get(ShardNodeHeader); id != "" {
+			if node, err := ring.GetNodeByID(id); err == nil {
+				ctx = consistenthashing.WithShardNode(ctx, node)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,69 @@
+package httpaffinity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func TestPropagate(t *testing.T) {
+	ring, err := consistenthashing.NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&consistenthashing.Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.AddNode(&consistenthashing.Node{ID: "node2", Host: "localhost", Port: 8081})
+
+	var gotKey string
+	var gotNode *consistenthashing.Node
+	server := httptest.NewServer(Propagate(ring, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey, _ = consistenthashing.ShardKeyFromContext(r.Context())
+		gotNode, _ = consistenthashing.ShardNodeFromContext(r.Context())
+	})))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+	send := func(ctx context.Context) *http.Request {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		return req
+	}
+
+	ctx, node, err := consistenthashing.Route(context.Background(), ring, "user:1")
+	if err != nil {
+		t.Fatalf("Failed to route: %v", err)
+	}
+	req := send(ctx)
+	if gotKey != "user:1" {
+		t.Errorf("Expected shard key user:1 downstream, got %q", gotKey)
+	}
+	if gotNode != node {
+		t.Errorf("Expected shard node %s downstream, got %v", node.ID, gotNode)
+	}
+	if req.Header.Get(ShardKeyHeader) != "" {
+		t.Error("Transport modified the caller's request")
+	}
+
+	// Nodes that are no longer members are dropped
+	ring.RemoveNode(node.ID)
+	send(ctx)
+	if gotNode != nil {
+		t.Errorf("Expected departed node to be dropped, got %s", gotNode.ID)
+	}
+
+	// Requests without a shard decision carry nothing
+	send(context.Background())
+	if gotKey != "" || gotNode != nil {
+		t.Errorf("Expected no shard decision, got %q and %v", gotKey, gotNode)
+	}
+}
@@ -0,0 +1,120 @@
+// Package httpaffinity implements HTTP session affinity on top of a consistent
+// hashing ring.
+package httpaffinity
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// DefaultCookieName is the session cookie used when Sticky.CookieName is empty
+const DefaultCookieName = "chash_session"
+
+// Sticky routes HTTP requests to nodes with classic session affinity. The
+// session ID is taken from a cookie (created if absent) and used as the ring
+// key. The chosen node is pinned in the cookie, and the session only moves
+// when the pinned node leaves the ring — new nodes joining never break
+// existing sessions.
+type Sticky struct {
+	Ring       consistenthashing.Ring
+	CookieName string
+	Path       string
+	MaxAge     time.Duration // Zero means a browser session cookie
+	Secure     bool
+}
+
+// NewSticky creates a Sticky router with default cookie settings
+func NewSticky(ring consistenthashing.Ring) *Sticky {
+	return &Sticky{Ring: ring, CookieName: DefaultCookieName, Path: "/"}
+}
+
+// Route returns the node serving the request's session. It sets the session
+// cookie on w whenever a session is created or re-pinned, so it must be called
+// before the response header is written.
+func (s *Sticky) Route(w http.ResponseWriter, r *http.Request) (*consistenthashing.Node, error) {
+	sessionID, pinned := s.readCookie(r)
+
+	if pinned != "" {
+		if node, err := s.Ring.GetNodeByID(pinned); err == nil {
+			return node, nil
+		}
+		// The original owner left; fall through and re-pin
+	}
+
+	if sessionID == "" {
+		var err error
+		if sessionID, err = newSessionID(); err != nil {
+			return nil, err
+		}
+	}
+
+	node, err := s.Ring.GetNode(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.writeCookie(w, sessionID, node.ID)
+	return node, nil
+}
+
+// SessionID returns the session ID carried by the request, if any
+func (s *Sticky) SessionID(r *http.Request) string {
+	sessionID, _ := s.readCookie(r)
+	return sessionID
+}
+
+func (s *Sticky) cookieName() string {
+	if s.CookieName == "" {
+		return DefaultCookieName
+	}
+	return s.CookieName
+}
+
+// readCookie parses "<session>.<base64 node ID>" from the session cookie
+func (s *Sticky) readCookie(r *http.Request) (sessionID, nodeID string) {
+	cookie, err := r.Cookie(s.cookieName())
+	if err != nil {
+		return "", ""
+	}
+
+	sessionID, encoded, _ := strings.Cut(cookie.Value, ".")
+	if _, err := hex.DecodeString(sessionID); err != nil || sessionID == "" {
+		return "", ""
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return sessionID, ""
+	}
+	return sessionID, string(decoded)
+}
+
+func (s *Sticky) writeCookie(w http.ResponseWriter, sessionID, nodeID string) {
+	cookie := &http.Cookie{
+		Name:     s.cookieName(),
+		Value:    sessionID + "." + base64.RawURLEncoding.EncodeToString([]byte(nodeID)),
+		Path:     s.Path,
+		Secure:   s.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if s.MaxAge > 0 {
+		cookie.MaxAge = int(s.MaxAge / time.Second)
+	}
+	http.SetCookie(w, cookie)
+}
+
+// newSessionID returns a random 128-bit hex session ID
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,77 @@
+package httpaffinity
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func TestStickyRoute(t *testing.T) {
+	ring, err := consistenthashing.NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	sticky := NewSticky(ring)
+
+	// First request creates and pins a session
+	rec := httptest.NewRecorder()
+	node, err := sticky.Route(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("Failed to route: %v", err)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultCookieName {
+		t.Fatalf("Expected session cookie to be set, got %v", cookies)
+	}
+
+	request := func() (*consistenthashing.Node, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+		rec := httptest.NewRecorder()
+		n, err := sticky.Route(rec, req)
+		if err != nil {
+			t.Fatalf("Failed to route: %v", err)
+		}
+		return n, rec
+	}
+
+	// Returning requests stay pinned, even when nodes join
+	for i := 3; i < 10; i++ {
+		ring.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	again, rec := request()
+	if again != node {
+		t.Errorf("Expected session to stay on %s, got %s", node.ID, again.ID)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie update while the pinned node is present")
+	}
+
+	// Removing the owner re-pins the session
+	ring.RemoveNode(node.ID)
+	moved, rec := request()
+	if moved.ID == node.ID {
+		t.Error("Expected session to move after its node left")
+	}
+	updated := rec.Result().Cookies()
+	if len(updated) != 1 {
+		t.Fatal("Expected cookie to be re-pinned")
+	}
+	if sticky.SessionID(httptest.NewRequest(http.MethodGet, "/", nil)) != "" {
+		t.Error("Expected no session ID without a cookie")
+	}
+
+	original := httptest.NewRequest(http.MethodGet, "/", nil)
+	original.AddCookie(cookies[0])
+	repinned := httptest.NewRequest(http.MethodGet, "/", nil)
+	repinned.AddCookie(updated[0])
+	if sticky.SessionID(original) != sticky.SessionID(repinned) {
+		t.Error("Expected session ID to survive re-pinning")
+	}
+}
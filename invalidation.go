@@ -0,0 +1,171 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// HashKey returns the ring position of a key, for matching keys against
+// HashRange values. Keys in an affinity group share their group's position.
+func (hr *HashRing) HashKey(key string) uint64 {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.keyHashLocked(key)
+}
+
+// OwnershipChange lists the parts of the hash space a node lost and gained in
+// a membership change. A cache of owned keys drops the keys in Lost, and may
+// have to load or warm the keys in Gained.
+type OwnershipChange struct {
+	Lost       []HashRange
+	Gained     []HashRange
+	Generation uint64
+}
+
+// Empty reports whether ownership is unchanged
+func (c OwnershipChange) Empty() bool {
+	return len(c.Lost) == 0 && len(c.Gained) == 0
+}
+
+// LostHash reports whether a hash moved away from the node
+func (c OwnershipChange) LostHash(hash uint64) bool {
+	return rangesContain(c.Lost, hash)
+}
+
+// GainedHash reports whether a hash moved to the node
+func (c OwnershipChange) GainedHash(hash uint64) bool {
+	return rangesContain(c.Gained, hash)
+}
+
+func rangesContain(ranges []HashRange, hash uint64) bool {
+	for _, r := range ranges {
+		if r.Contains(hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnershipTracker follows the ranges one node owns, typically the local
+// process, and reports exactly which ranges it lost or gained when the ring
+// changes. This is the invalidation step of ownership-based caches.
+type OwnershipTracker struct {
+	ring   *HashRing
+	nodeID string
+
+	mu    sync.Mutex
+	owned []HashRange
+}
+
+// NewOwnershipTracker starts tracking the ranges nodeID currently owns. The
+// node doesn't need to be in the ring yet.
+func NewOwnershipTracker(ring *HashRing, nodeID string) (*OwnershipTracker, error) {
+	if ring == nil {
+		return nil, errors.New("ring cannot be nil")
+	}
+	if nodeID == "" {
+		return nil, ErrInvalidNodeID
+	}
+
+	t := &OwnershipTracker{ring: ring, nodeID: nodeID}
+	t.owned, _ = t.current()
+	return t, nil
+}
+
+// current returns the ranges the node owns now and the ring generation
+func (t *OwnershipTracker) current() ([]HashRange, uint64) {
+	t.ring.mu.RLock()
+	defer t.ring.mu.RUnlock()
+
+	var owned []HashRange
+	for _, r := range t.ring.rangesLocked() {
+		if r.Node.ID == t.nodeID {
+			owned = append(owned, r.Range)
+		}
+	}
+	return owned, t.ring.generation
+}
+
+// Owned returns the ranges the node owned at the last update
+func (t *OwnershipTracker) Owned() []HashRange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.owned)
+}
+
+// Update compares the node's current ranges with those seen at the last
+// update and returns the difference
+func (t *OwnershipTracker) Update() OwnershipChange {
+	owned, generation := t.current()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	change := OwnershipChange{
+		Lost:       subtractRanges(t.owned, owned),
+		Gained:     subtractRanges(owned, t.owned),
+		Generation: generation,
+	}
+	t.owned = owned
+	return change
+}
+
+// Run updates on every ring event and calls fn with each non-empty change,
+// until ctx is done or events is closed. Events only serve as a trigger, so
+// dropped events merge into the next change rather than being lost.
+func (t *OwnershipTracker) Run(ctx context.Context, events <-chan RingEvent, fn func(OwnershipChange)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+			if change := t.Update(); !change.Empty() {
+				fn(change)
+			}
+		}
+	}
+}
+
+// subtractRanges returns the parts of a not covered by b, merged into maximal
+// ranges. Between consecutive endpoints of either set, membership in each set
+// is constant, so it is decided by the arc's end point.
+func subtractRanges(a, b []HashRange) []HashRange {
+	if len(a) == 0 {
+		return nil
+	}
+
+	bounds := make([]uint64, 0, 2*(len(a)+len(b)))
+	for _, r := range append(slices.Clone(a), b...) {
+		bounds = append(bounds, r.Start, r.End)
+	}
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	var result []HashRange
+	for i, end := range bounds {
+		arc := HashRange{Start: bounds[(i+len(bounds)-1)%len(bounds)], End: end}
+		if !rangesContain(a, end) || rangesContain(b, end) {
+			continue
+		}
+		if n := len(result); n > 0 && result[n-1].End == arc.Start {
+			result[n-1].End = arc.End
+			continue
+		}
+		result = append(result, arc)
+	}
+
+	// The first arc crosses zero, so it may continue the last one
+	if n := len(result); n > 1 && result[n-1].End == result[0].Start {
+		result[0].Start = result[n-1].Start
+		result = result[:n-1]
+	}
+
+	return result
+}
@@ -0,0 +1,120 @@
+package consistenthashing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSubtractRanges(t *testing.T) {
+	full := []HashRange{{Start: 10, End: 10}}
+	if got := subtractRanges(full, nil); len(got) != 1 || got[0] != full[0] {
+		t.Errorf("Expected the whole ring, got %v", got)
+	}
+	if got := subtractRanges(full, full); got != nil {
+		t.Errorf("Expected nothing, got %v", got)
+	}
+
+	// Removing the part around zero leaves one range
+	got := subtractRanges(full, []HashRange{{Start: math.MaxUint64 - 5, End: 5}})
+	if len(got) != 1 || got[0] != (HashRange{Start: 5, End: math.MaxUint64 - 5}) {
+		t.Errorf("Expected (5, max-5], got %v", got)
+	}
+
+	// Pieces that meet across zero are merged
+	a := []HashRange{{Start: 100, End: 200}, {Start: math.MaxUint64 - 10, End: 50}}
+	b := []HashRange{{Start: 50, End: 60}}
+	got = subtractRanges(append(a, HashRange{Start: 50, End: 100}), b)
+	expected := []HashRange{{Start: math.MaxUint64 - 10, End: 50}, {Start: 60, End: 200}}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestOwnershipTracker(t *testing.T) {
+	ring, err := NewHashRing(30)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	if _, err := NewOwnershipTracker(ring, ""); err == nil {
+		t.Error("Expected error for empty node ID")
+	}
+	tracker, err := NewOwnershipTracker(ring, "node0")
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	if len(tracker.Owned()) == 0 {
+		t.Fatal("Expected node0 to own ranges")
+	}
+
+	keys := make([]string, 5000)
+	owners := make(map[string]string, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+		node, _ := ring.GetNode(keys[i])
+		owners[keys[i]] = node.ID
+	}
+
+	checkChange := func(change OwnershipChange) {
+		t.Helper()
+		for _, key := range keys {
+			node, _ := ring.GetNode(key)
+			hash := ring.HashKey(key)
+			lost := owners[key] == "node0" && node.ID != "node0"
+			gained := owners[key] != "node0" && node.ID == "node0"
+			if change.LostHash(hash) != lost || change.GainedHash(hash) != gained {
+				t.Fatalf("Key %s moved %s -> %s, change says lost=%v gained=%v",
+					key, owners[key], node.ID, change.LostHash(hash), change.GainedHash(hash))
+			}
+			owners[key] = node.ID
+		}
+	}
+
+	// A new node takes ranges from node0
+	ring.AddNode(&Node{ID: "node4", Host: "localhost", Port: 9000})
+	change := tracker.Update()
+	if len(change.Lost) == 0 || len(change.Gained) != 0 || change.Generation != ring.Generation() {
+		t.Errorf("Expected only losses at the current generation, got %+v", change)
+	}
+	checkChange(change)
+
+	// A removed node hands ranges to node0
+	ring.RemoveNode("node1")
+	change = tracker.Update()
+	if len(change.Gained) == 0 || len(change.Lost) != 0 {
+		t.Errorf("Expected only gains, got %+v", change)
+	}
+	checkChange(change)
+
+	if change := tracker.Update(); !change.Empty() {
+		t.Errorf("Expected no change without ring changes, got %+v", change)
+	}
+}
+
+func TestOwnershipTrackerRun(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	ring.AddNode(&Node{ID: "self", Host: "localhost", Port: 8080})
+	tracker, _ := NewOwnershipTracker(ring, "self")
+
+	events, cancel := ring.Watch(4)
+	changes := make(chan OwnershipChange, 4)
+	done := make(chan struct{})
+	go func() {
+		tracker.Run(context.Background(), events, func(change OwnershipChange) { changes <- change })
+		close(done)
+	}()
+
+	ring.AddNode(&Node{ID: "other", Host: "localhost", Port: 8081})
+	if change := <-changes; len(change.Lost) == 0 {
+		t.Errorf("Expected self to lose ranges, got %+v", change)
+	}
+
+	// Closing the event channel stops the tracker
+	cancel()
+	<-done
+}
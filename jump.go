@@ -0,0 +1,191 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotLastBucket is returned when removing a JumpRing node other than the
+// last one
+var ErrNotLastBucket = errors.New("jump hash can only remove the last bucket")
+
+// JumpHash maps key to a bucket in [0, buckets) with Lamping and Veach's jump
+// consistent hash. It needs no memory, and growing from n to n+1 buckets
+// moves only the keys that land in the new bucket. It returns -1 if buckets
+// is not positive.
+func JumpHash(key uint64, buckets int) int {
+	if buckets <= 0 {
+		return -1
+	}
+
+	b, j := int64(-1), int64(0)
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// JumpRing places keys with JumpHash over an ordered list of nodes, where
+// node i is bucket i. It suits clusters that only grow or shrink at the
+// tail: AddNode appends a bucket and only the last node can be removed.
+// Weights are ignored. It implements Ring.
+type JumpRing struct {
+	hasher  HashFunction
+	buckets []*Node
+	index   map[string]int // Node ID to bucket
+	mu      sync.RWMutex
+}
+
+// JumpRing must satisfy Ring
+var _ Ring = (*JumpRing)(nil)
+
+// NewJumpRing creates an empty jump ring. A nil hasher means FNVHasher.
+func NewJumpRing(hasher HashFunction) *JumpRing {
+	if hasher == nil {
+		hasher = &FNVHasher{}
+	}
+	return &JumpRing{hasher: hasher, index: make(map[string]int)}
+}
+
+// AddNode appends a node as the new last bucket. Adding an existing ID is a
+// no-op, as with HashRing.
+func (j *JumpRing) AddNode(node *Node) error {
+	if node == nil {
+		return errors.New("node cannot be nil")
+	}
+	if err := node.Validate(); err != nil {
+		return fmt.Errorf("invalid node: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, exists := j.index[node.ID]; exists {
+		return nil
+	}
+	j.index[node.ID] = len(j.buckets)
+	j.buckets = append(j.buckets, node)
+	return nil
+}
+
+// RemoveNode removes the last bucket's node. Removing any other node would
+// renumber the buckets after it, so it fails with ErrNotLastBucket.
+func (j *JumpRing) RemoveNode(nodeID string) error {
+	if strings.TrimSpace(nodeID) == "" {
+		return ErrInvalidNodeID
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	bucket, exists := j.index[nodeID]
+	if !exists {
+		return ErrNodeNotFound
+	}
+	if last := len(j.buckets) - 1; bucket != last {
+		return fmt.Errorf("%w: %s is bucket %d of %d", ErrNotLastBucket, nodeID, bucket, last+1)
+	}
+
+	delete(j.index, nodeID)
+	j.buckets[bucket] = nil
+	j.buckets = j.buckets[:bucket]
+	return nil
+}
+
+// GetNode returns the node of key's bucket
+func (j *JumpRing) GetNode(key string) (*Node, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if len(j.buckets) == 0 {
+		return nil, ErrEmptyRing
+	}
+	return j.buckets[j.bucket(key)], nil
+}
+
+// GetNodes returns up to count distinct nodes for key: the node of its bucket
+// followed by the nodes of the next buckets
+func (j *JumpRing) GetNodes(key string, count int) ([]*Node, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if len(j.buckets) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	nodes := make([]*Node, min(count, len(j.buckets)))
+	start := j.bucket(key)
+	for i := range nodes {
+		nodes[i] = j.buckets[(start+i)%len(j.buckets)]
+	}
+	return nodes, nil
+}
+
+// Buckets returns the nodes in bucket order
+func (j *JumpRing) Buckets() []*Node {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return slices.Clone(j.buckets)
+}
+
+// GetAllNodes returns all nodes sorted by ID
+func (j *JumpRing) GetAllNodes() []*Node {
+	nodes := j.Buckets()
+	sort.Slice(nodes, func(a, b int) bool {
+		return nodes[a].ID < nodes[b].ID
+	})
+	return nodes
+}
+
+// GetNodeByID returns a node by its ID
+func (j *JumpRing) GetNodeByID(nodeID string) (*Node, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	bucket, exists := j.index[nodeID]
+	if !exists {
+		return nil, ErrNodeNotFound
+	}
+	return j.buckets[bucket], nil
+}
+
+// HasNode reports whether a node is in the ring
+func (j *JumpRing) HasNode(nodeID string) bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	_, exists := j.index[nodeID]
+	return exists
+}
+
+// Size returns the number of nodes
+func (j *JumpRing) Size() int {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return len(j.buckets)
+}
+
+// bucket returns the bucket of a key. mix64 spreads FNV hashes of similar
+// keys before they seed the jump.
+func (j *JumpRing) bucket(key string) int {
+	return JumpHash(mix64(j.hasher.Hash(key)), len(j.buckets))
+}
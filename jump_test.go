@@ -0,0 +1,75 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestJumpHash(t *testing.T) {
+	if got := JumpHash(42, 0); got != -1 {
+		t.Errorf("Expected -1 for no buckets, got %d", got)
+	}
+
+	// Growing by one bucket only moves keys into the new bucket
+	const keys = 10000
+	counts := make([]int, 10)
+	for key := uint64(0); key < keys; key++ {
+		before := JumpHash(mix64(key), 9)
+		after := JumpHash(mix64(key), 10)
+		if after != before && after != 9 {
+			t.Fatalf("Key %d moved from bucket %d to %d", key, before, after)
+		}
+		counts[after]++
+	}
+	for bucket, count := range counts {
+		if count < keys/10*8/10 || count > keys/10*12/10 {
+			t.Errorf("Bucket %d got %d of %d keys", bucket, count, keys)
+		}
+	}
+}
+
+func TestJumpRing(t *testing.T) {
+	ring := NewJumpRing(nil)
+	if _, err := ring.GetNode("key"); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	if ids := fmt.Sprint(nodeIDs(ring.Buckets())); ids != "[node0 node1 node2 node3 node4]" {
+		t.Errorf("Expected nodes in bucket order, got %s", ids)
+	}
+
+	owners := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		node, err := ring.GetNode(key)
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		owners[key] = node.ID
+	}
+
+	nodes, err := ring.GetNodes("key", 3)
+	if err != nil {
+		t.Fatalf("Failed to get nodes: %v", err)
+	}
+	if len(nodes) != 3 || nodes[0] == nodes[1] || nodes[1] == nodes[2] || nodes[0] == nodes[2] {
+		t.Errorf("Expected 3 distinct nodes, got %v", nodeIDs(nodes))
+	}
+
+	if err := ring.RemoveNode("node2"); !errors.Is(err, ErrNotLastBucket) {
+		t.Errorf("Expected ErrNotLastBucket, got %v", err)
+	}
+	if err := ring.RemoveNode("node4"); err != nil {
+		t.Fatalf("Failed to remove last node: %v", err)
+	}
+	for key, owner := range owners {
+		node, _ := ring.GetNode(key)
+		if owner != "node4" && node.ID != owner {
+			t.Errorf("Key %s moved from %s to %s", key, owner, node.ID)
+		}
+	}
+}
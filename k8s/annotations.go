@@ -0,0 +1,144 @@
+// Package k8s publishes a ring's routing state as Kubernetes pod annotations,
+// so kubectl alone shows which share of the hash space, and which ranges,
+// each pod owns during an incident. It doesn't depend on a Kubernetes
+// client: callers provide a Patcher, typically a one-line wrapper around
+// client-go's Pods(namespace).Patch with types.MergePatchType.
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// Annotation keys written on each pod
+const (
+	OwnershipAnnotation  = "consistent-hashing.io/ownership"   // Share of the hash space, e.g. "0.250612"
+	RangesAnnotation     = "consistent-hashing.io/ranges"      // Owned ranges (start, end] as comma-separated hex start-end pairs
+	RangeCountAnnotation = "consistent-hashing.io/range-count" // Number of owned ranges, even when truncated
+	GenerationAnnotation = "consistent-hashing.io/generation"  // Ring generation the values were read at
+)
+
+// MaxAnnotatedRanges caps the ranges written to RangesAnnotation, keeping
+// heavily weighted nodes well inside the 256KiB annotation limit. Longer
+// lists end with "...".
+const MaxAnnotatedRanges = 1000
+
+// PodRef names a pod
+type PodRef struct {
+	Namespace string
+	Name      string
+}
+
+// Patcher applies a JSON merge patch to a pod
+type Patcher interface {
+	PatchPod(ctx context.Context, pod PodRef, patch []byte) error
+}
+
+// PatcherFunc adapts a function to the Patcher interface
+type PatcherFunc func(ctx context.Context, pod PodRef, patch []byte) error
+
+// PatchPod calls f
+func (f PatcherFunc) PatchPod(ctx context.Context, pod PodRef, patch []byte) error {
+	return f(ctx, pod, patch)
+}
+
+// PodResolver returns the pod backing a node, or false for nodes that aren't
+// pods and should be skipped
+type PodResolver func(node *consistenthashing.Node) (PodRef, bool)
+
+// PodsByID resolves every node to the pod named by its ID in namespace, for
+// rings whose members are registered under their pod names
+func PodsByID(namespace string) PodResolver {
+	return func(node *consistenthashing.Node) (PodRef, bool) {
+		return PodRef{Namespace: namespace, Name: node.ID}, true
+	}
+}
+
+// State is the routing state of every member, read at one generation
+type State struct {
+	Generation uint64
+	Ranges     map[string][]consistenthashing.HashRange // Owned ranges per node ID, clockwise from zero
+}
+
+// ReadState reads the owned ranges of every member at a single generation
+func ReadState(ring *consistenthashing.HashRing) State {
+	ranges, generation := ring.RangesWithGeneration()
+	state := State{Generation: generation, Ranges: make(map[string][]consistenthashing.HashRange)}
+	for _, owner := range ranges {
+		state.Ranges[owner.Node.ID] = append(state.Ranges[owner.Node.ID], owner.Range)
+	}
+	return state
+}
+
+// Annotations returns the annotations describing a node's routing state. A
+// node that owns nothing gets zero ownership and no ranges.
+func (s State) Annotations(nodeID string) map[string]string {
+	ranges := s.Ranges[nodeID]
+	ownership := 0.0
+	for _, r := range ranges {
+		ownership += r.Fraction()
+	}
+
+	formatted := make([]string, 0, min(len(ranges), MaxAnnotatedRanges)+1)
+	for i, r := range ranges {
+		if i == MaxAnnotatedRanges {
+			formatted = append(formatted, "...")
+			break
+		}
+		formatted = append(formatted, fmt.Sprintf("%016x-%016x", r.Start, r.End))
+	}
+
+	return map[string]string{
+		OwnershipAnnotation:  strconv.FormatFloat(ownership, 'f', 6, 64),
+		RangesAnnotation:     strings.Join(formatted, ","),
+		RangeCountAnnotation: strconv.Itoa(len(ranges)),
+		GenerationAnnotation: strconv.FormatUint(s.Generation, 10),
+	}
+}
+
+// MergePatch returns a JSON merge patch setting annotations on an object
+func MergePatch(annotations map[string]string) ([]byte, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	}
+	return json.Marshal(patch)
+}
+
+// Publish annotates the pod of every member with its current ownership and
+// owned ranges, with at most parallelism patches in flight. The state is read
+// once, at a single generation, before any pod is patched; members that
+// joined since are left for the next call. Failed pods are reported in a
+// *consistenthashing.MultiError, as with HashRing.ForAllNodes.
+func Publish(ctx context.Context, ring *consistenthashing.HashRing, patcher Patcher, pods PodResolver, parallelism int) error {
+	if ring == nil {
+		return errors.New("ring cannot be nil")
+	}
+	if patcher == nil {
+		return errors.New("patcher cannot be nil")
+	}
+	if pods == nil {
+		return errors.New("pod resolver cannot be nil")
+	}
+
+	state := ReadState(ring)
+	return ring.ForAllNodes(ctx, parallelism, func(ctx context.Context, node *consistenthashing.Node) error {
+		if _, read := state.Ranges[node.ID]; !read {
+			return nil
+		}
+		pod, ok := pods(node)
+		if !ok {
+			return nil
+		}
+		patch, err := MergePatch(state.Annotations(node.ID))
+		if err != nil {
+			return err
+		}
+		return patcher.PatchPod(ctx, pod, patch)
+	})
+}
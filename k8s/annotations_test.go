@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func TestPublish(t *testing.T) {
+	ring, err := consistenthashing.NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("cache-%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	ring.AddNode(&consistenthashing.Node{ID: "external", Host: "10.0.0.1", Port: 8080})
+
+	var mu sync.Mutex
+	patched := make(map[PodRef]map[string]string)
+	patcher := PatcherFunc(func(ctx context.Context, pod PodRef, patch []byte) error {
+		var body struct {
+			Metadata struct {
+				Annotations map[string]string `json:"annotations"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(patch, &body); err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		patched[pod] = body.Metadata.Annotations
+		return nil
+	})
+	pods := func(node *consistenthashing.Node) (PodRef, bool) {
+		if !strings.HasPrefix(node.ID, "cache-") {
+			return PodRef{}, false
+		}
+		return PodsByID("prod")(node)
+	}
+
+	if err := Publish(context.Background(), ring, patcher, pods, 2); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+	if len(patched) != 3 {
+		t.Fatalf("Expected 3 pods patched, got %d", len(patched))
+	}
+
+	ownership := ring.Ownership()
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("cache-%d", i)
+		annotations := patched[PodRef{Namespace: "prod", Name: id}]
+		share, _ := strconv.ParseFloat(annotations[OwnershipAnnotation], 64)
+		if math.Abs(share-ownership[id]) > 1e-6 {
+			t.Errorf("Pod %s: expected ownership %f, got %s", id, ownership[id], annotations[OwnershipAnnotation])
+		}
+		count, _ := strconv.Atoi(annotations[RangeCountAnnotation])
+		if count == 0 || len(strings.Split(annotations[RangesAnnotation], ",")) != count {
+			t.Errorf("Pod %s: expected %d ranges, got %q", id, count, annotations[RangesAnnotation])
+		}
+		if annotations[GenerationAnnotation] != strconv.FormatUint(ring.Generation(), 10) {
+			t.Errorf("Pod %s: expected generation %d, got %s", id, ring.Generation(), annotations[GenerationAnnotation])
+		}
+	}
+
+	// Failed pods come back in a MultiError
+	failing := PatcherFunc(func(ctx context.Context, pod PodRef, patch []byte) error {
+		return errors.New("forbidden")
+	})
+	var multi *consistenthashing.MultiError
+	if err := Publish(context.Background(), ring, failing, PodsByID("prod"), 0); !errors.As(err, &multi) || len(multi.Failures) != 4 {
+		t.Errorf("Expected 4 failures, got %v", err)
+	}
+}
+
+func TestStateAnnotations(t *testing.T) {
+	// Adjacent ranges of a node merge, so two nodes own about half as many
+	// ranges as they have virtual nodes
+	ring, err := consistenthashing.NewHashRing(3 * MaxAnnotatedRanges)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&consistenthashing.Node{ID: "a", Host: "localhost", Port: 8080})
+	ring.AddNode(&consistenthashing.Node{ID: "b", Host: "localhost", Port: 8081})
+
+	state := ReadState(ring)
+	annotations := state.Annotations("a")
+	ranges := strings.Split(annotations[RangesAnnotation], ",")
+	if len(ranges) != MaxAnnotatedRanges+1 || ranges[MaxAnnotatedRanges] != "..." {
+		t.Errorf("Expected %d ranges and a truncation marker, got %d", MaxAnnotatedRanges, len(ranges))
+	}
+	if count, _ := strconv.Atoi(annotations[RangeCountAnnotation]); count != len(state.Ranges["a"]) {
+		t.Errorf("Expected the full range count %d, got %d", len(state.Ranges["a"]), count)
+	}
+
+	if annotations := state.Annotations("missing"); annotations[OwnershipAnnotation] != "0.000000" || annotations[RangesAnnotation] != "" {
+		t.Errorf("Expected empty annotations for a non-member, got %v", annotations)
+	}
+}
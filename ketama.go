@@ -0,0 +1,72 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// defaultMemcachedPort is assumed for ketama entries without a port
+const defaultMemcachedPort = 11211
+
+// ImportKetamaServers parses a libmemcached style ketama server list, one
+// "host:port weight" entry per line, into nodes with matching weights. The
+// port defaults to 11211 and the weight to 1. Blank lines and lines starting
+// with '#' are skipped. Nodes are identified by their "host:port" address,
+// or by WithNodeID, so the result can be passed straight to SetMembers.
+func ImportKetamaServers(lines []string, opts ...EndpointOption) ([]*Node, error) {
+	config := newEndpointConfig(opts)
+	nodes := make([]*Node, 0, len(lines))
+	seen := make(map[string]bool, len(lines))
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected \"host:port weight\", got %q", i+1, line)
+		}
+
+		host, port := fields[0], defaultMemcachedPort
+		if h, p, err := net.SplitHostPort(fields[0]); err == nil {
+			host = h
+			if port, err = strconv.Atoi(p); err != nil {
+				return nil, fmt.Errorf("line %d: invalid port %q", i+1, p)
+			}
+		}
+
+		weight := 1
+		if len(fields) == 2 {
+			w, err := strconv.Atoi(fields[1])
+			if err != nil || w <= 0 {
+				return nil, fmt.Errorf("line %d: invalid weight %q", i+1, fields[1])
+			}
+			weight = w
+		}
+
+		id, err := config.nodeID(host, port)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		node := &Node{
+			ID:     id,
+			Host:   host,
+			Port:   port,
+			Weight: weight,
+		}
+		if err := node.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if seen[node.ID] {
+			return nil, fmt.Errorf("line %d: duplicate server %s", i+1, node.ID)
+		}
+		seen[node.ID] = true
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,69 @@
+package consistenthashing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestImportKetamaServers(t *testing.T) {
+	nodes, err := ImportKetamaServers([]string{
+		"# memcached pool",
+		"10.0.1.1:11211 600",
+		"",
+		"10.0.1.2:11212   300",
+		"10.0.1.3",
+		"[::1]:11213 2",
+	})
+	if err != nil {
+		t.Fatalf("Failed to import servers: %v", err)
+	}
+
+	expected := []Node{
+		{ID: "10.0.1.1:11211", Host: "10.0.1.1", Port: 11211, Weight: 600},
+		{ID: "10.0.1.2:11212", Host: "10.0.1.2", Port: 11212, Weight: 300},
+		{ID: "10.0.1.3:11211", Host: "10.0.1.3", Port: 11211, Weight: 1},
+		{ID: "[::1]:11213", Host: "::1", Port: 11213, Weight: 2},
+	}
+	if len(nodes) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d", len(expected), len(nodes))
+	}
+	for i, node := range nodes {
+		if !node.Equal(&expected[i]) {
+			t.Errorf("Node %d: expected %+v, got %+v", i, expected[i], *node)
+		}
+	}
+
+	ring, _ := NewHashRing(10)
+	if _, err := ring.SetMembers(nodes); err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	if ring.Size() != 4 {
+		t.Errorf("Expected 4 nodes, got %d", ring.Size())
+	}
+}
+
+func TestImportKetamaServersErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{"bad weight", []string{"10.0.0.1:11211 heavy"}},
+		{"zero weight", []string{"10.0.0.1:11211 0"}},
+		{"bad port", []string{"10.0.0.1:memcache 1"}},
+		{"extra fields", []string{"10.0.0.1:11211 1 2"}},
+		{"duplicate", []string{"10.0.0.1:11211", "10.0.0.1 5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ImportKetamaServers(tt.lines); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	_, err := ImportKetamaServers([]string{"10.0.0.1:70000"})
+	if !errors.Is(err, ErrInvalidNodePort) {
+		t.Errorf("Expected ErrInvalidNodePort, got %v", err)
+	}
+}
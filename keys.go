@@ -0,0 +1,77 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidIP is returned when an address cannot be parsed or masked
+var ErrInvalidIP = errors.New("invalid IP address")
+
+// IPKey canonicalizes an IP address and masks it to a network prefix, so all
+// clients of a network share one ring key. addr may carry a port ("1.2.3.4:80",
+// "[::1]:80") or an IPv6 zone, both of which are dropped, and IPv4-mapped IPv6
+// addresses are treated as IPv4. v4Bits and v6Bits select the prefix length
+// per family (e.g. 24 and 64); 0 keeps the full address. The key has the form
+// "192.168.1.0/24".
+func IPKey(addr string, v4Bits, v6Bits int) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIP, addr)
+	}
+
+	return AddrKey(ip, v4Bits, v6Bits)
+}
+
+// AddrKey is IPKey for an already parsed address
+func AddrKey(ip netip.Addr, v4Bits, v6Bits int) (string, error) {
+	if !ip.IsValid() {
+		return "", ErrInvalidIP
+	}
+
+	ip = ip.Unmap().WithZone("")
+
+	bits := v6Bits
+	if ip.Is4() {
+		bits = v4Bits
+	}
+	if bits == 0 {
+		bits = ip.BitLen()
+	}
+
+	prefix, err := ip.Prefix(bits)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidIP, err)
+	}
+
+	return prefix.String(), nil
+}
+
+// TimeBucketKey returns a key for base that changes once per window, such as
+// "metrics@4733". Every key within a window maps to one node, while successive
+// windows rotate across owners, which suits sharding log and metric streams.
+// A non-positive window disables bucketing and returns base unchanged.
+func TimeBucketKey(base string, t time.Time, window time.Duration) string {
+	if window <= 0 {
+		return base
+	}
+
+	// Floor division so times before the Unix epoch bucket correctly
+	nanos := t.UnixNano()
+	bucket := nanos / int64(window)
+	if nanos%int64(window) < 0 {
+		bucket--
+	}
+
+	return base + "@" + strconv.FormatInt(bucket, 10)
+}
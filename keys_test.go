@@ -0,0 +1,68 @@
+package consistenthashing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIPKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		v4Bits   int
+		v6Bits   int
+		expected string
+	}{
+		{name: "ipv4 full", addr: "192.168.1.77", expected: "192.168.1.77/32"},
+		{name: "ipv4 /24", addr: "192.168.1.77", v4Bits: 24, expected: "192.168.1.0/24"},
+		{name: "ipv4 with port", addr: "192.168.1.77:5123", v4Bits: 24, expected: "192.168.1.0/24"},
+		{name: "ipv4 mapped", addr: "::ffff:192.168.1.77", v4Bits: 24, v6Bits: 64, expected: "192.168.1.0/24"},
+		{name: "ipv6 /64", addr: "2001:db8:1:2:3:4:5:6", v6Bits: 64, expected: "2001:db8:1:2::/64"},
+		{name: "ipv6 with port", addr: "[2001:db8::1]:443", v6Bits: 48, expected: "2001:db8::/48"},
+		{name: "ipv6 zone", addr: "fe80::1%eth0", expected: "fe80::1/128"},
+		{name: "whitespace", addr: " 10.0.0.1 ", v4Bits: 8, expected: "10.0.0.0/8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := IPKey(tt.addr, tt.v4Bits, tt.v6Bits)
+			if err != nil {
+				t.Fatalf("IPKey returned error: %v", err)
+			}
+			if key != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestIPKeyErrors(t *testing.T) {
+	if _, err := IPKey("not-an-ip", 24, 64); !errors.Is(err, ErrInvalidIP) {
+		t.Errorf("Expected ErrInvalidIP, got %v", err)
+	}
+	if _, err := IPKey("10.0.0.1", 33, 64); !errors.Is(err, ErrInvalidIP) {
+		t.Errorf("Expected ErrInvalidIP for prefix longer than address, got %v", err)
+	}
+}
+
+func TestTimeBucketKey(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	key := TimeBucketKey("logs", start, time.Minute)
+	if TimeBucketKey("logs", start.Add(59*time.Second), time.Minute) != key {
+		t.Error("Expected times within a window to share a key")
+	}
+	if TimeBucketKey("logs", start.Add(time.Minute), time.Minute) == key {
+		t.Error("Expected the next window to get a new key")
+	}
+	if TimeBucketKey("logs", start, 0) != "logs" {
+		t.Error("Expected a non-positive window to return the base key")
+	}
+
+	// Windows before the epoch are floored, not truncated towards zero
+	epoch := time.Unix(0, 0)
+	if TimeBucketKey("x", epoch.Add(-time.Second), time.Minute) != "x@-1" {
+		t.Errorf("Expected x@-1, got %s", TimeBucketKey("x", epoch.Add(-time.Second), time.Minute))
+	}
+}
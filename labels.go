@@ -0,0 +1,78 @@
+package consistenthashing
+
+import "errors"
+
+// ErrNoMatchingNodes is returned when no member passes a lookup's filter
+var ErrNoMatchingNodes = errors.New("no nodes match the filter")
+
+// MatchLabels returns a filter matching nodes whose metadata has every label
+// in selector, such as {"disk": "ssd"}. An empty selector matches every node.
+func MatchLabels(selector map[string]string) func(*Node) bool {
+	return func(node *Node) bool {
+		for k, v := range selector {
+			if value, ok := node.Meta[k]; !ok || value != v {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// GetNodesWithFilter returns up to count distinct nodes for key that pass
+// filter, walking the ring clockwise from the key as GetNodes does and
+// skipping nodes that don't match. Fewer nodes are returned when fewer
+// match. Replicas are ordered like those of GetNodes. The filter runs under
+// the ring's read lock, at most once per node, and must not call back into
+// the ring. A nil filter matches every node.
+func (hr *HashRing) GetNodesWithFilter(key string, count int, filter func(*Node) bool) ([]*Node, error) {
+	if filter == nil {
+		return hr.GetNodes(key, count)
+	}
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	if key == "" {
+		if node, handled, err := hr.routeEmptyKey(); handled {
+			if err != nil {
+				return nil, err
+			}
+			if !filter(node) {
+				return nil, ErrNoMatchingNodes
+			}
+			return []*Node{node}, nil
+		}
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	// Each node is checked once; the walk ends when enough nodes matched or
+	// every node was checked, wrapping around the ring at most once
+	checked := make(map[*Node]bool, min(len(hr.nodes), maxScanDedup))
+	var nodes []*Node
+	idx := hr.search(hr.keyHashLocked(key))
+	for i := 0; i < len(hr.virtualNodes) && len(nodes) < count && len(checked) < len(hr.nodes); i++ {
+		node := hr.virtualNodes[(idx+i)%len(hr.virtualNodes)].Node
+		if _, done := checked[node]; done {
+			continue
+		}
+		match := filter(node)
+		checked[node] = match
+		if match {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, ErrNoMatchingNodes
+	}
+
+	if hr.weightedOrder {
+		hr.sortByWeightLocked(nodes, key)
+	}
+	SortByProximity(nodes, hr.proximity...)
+	return nodes, nil
+}
@@ -0,0 +1,101 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newLabeledRing(t *testing.T) *HashRing {
+	t.Helper()
+	hr, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		disk := "hdd"
+		if i%3 == 0 {
+			disk = "ssd"
+		}
+		hr.AddNode(&Node{
+			ID:   fmt.Sprintf("node%d", i),
+			Host: "localhost",
+			Port: 8080 + i,
+			Meta: map[string]string{"disk": disk, "region": fmt.Sprintf("r%d", i%2)},
+		})
+	}
+	return hr
+}
+
+func TestGetNodesWithFilter(t *testing.T) {
+	hr := newLabeledRing(t)
+	ssd := MatchLabels(map[string]string{"disk": "ssd"})
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		all, _ := hr.GetNodes(key, hr.Size())
+		var want []string
+		for _, node := range all {
+			if ssd(node) && len(want) < 3 {
+				want = append(want, node.ID)
+			}
+		}
+
+		// The filtered walk is the unfiltered one without the other nodes
+		nodes, err := hr.GetNodesWithFilter(key, 3, ssd)
+		if err != nil {
+			t.Fatalf("Failed to get nodes: %v", err)
+		}
+		if got := fmt.Sprint(nodeIDs(nodes)); got != fmt.Sprint(want) {
+			t.Fatalf("Key %s: expected %v, got %s", key, want, got)
+		}
+	}
+
+	// Only node0, node3, node6 and node9 have SSDs
+	nodes, err := hr.GetNodesWithFilter("key", 10, ssd)
+	if err != nil || len(nodes) != 4 {
+		t.Errorf("Expected all 4 matching nodes, got %v, %v", nodeIDs(nodes), err)
+	}
+
+	none := MatchLabels(map[string]string{"disk": "nvme"})
+	if _, err := hr.GetNodesWithFilter("key", 1, none); !errors.Is(err, ErrNoMatchingNodes) {
+		t.Errorf("Expected ErrNoMatchingNodes, got %v", err)
+	}
+	if nodes, err := hr.GetNodesWithFilter("key", 2, nil); err != nil || len(nodes) != 2 {
+		t.Errorf("Expected a nil filter to match every node, got %v, %v", nodeIDs(nodes), err)
+	}
+}
+
+func TestNodeMeta(t *testing.T) {
+	hr := newLabeledRing(t)
+
+	page, total, err := hr.ListNodes(NodeFilter{Labels: map[string]string{"disk": "ssd", "region": "r0"}}, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to list nodes: %v", err)
+	}
+	if total != 2 || fmt.Sprint(nodeIDs(page)) != "[node0 node6]" {
+		t.Errorf("Expected node0 and node6, got %v", nodeIDs(page))
+	}
+
+	// Changed metadata is a member update
+	members := hr.GetAllNodes()
+	updated := *members[0]
+	updated.Meta = map[string]string{"disk": "nvme"}
+	members[0] = &updated
+	report, err := hr.SetMembers(members)
+	if err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	if fmt.Sprint(report.Updated) != "[node0]" {
+		t.Errorf("Expected node0 to be updated, got %v", report.Updated)
+	}
+
+	// Copies handed out don't share labels with the ring
+	snapshot := hr.Snapshot()
+	snapshot.Nodes[0].Meta["disk"] = "hdd"
+	membership := hr.Membership()
+	membership.Nodes[0].Meta["disk"] = "hdd"
+	if node, _ := hr.GetNodeByID("node0"); node.Meta["disk"] != "nvme" {
+		t.Errorf("Expected node0's labels to be unchanged, got %v", node.Meta)
+	}
+}
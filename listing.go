@@ -0,0 +1,151 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// NodeFilter selects nodes for ListNodes. Zero fields match every node.
+type NodeFilter struct {
+	Zone   string
+	States []NodeState       // Match any of these states
+	Labels map[string]string // Match nodes with all of these metadata labels
+	// Match is an extra predicate, for example on addresses. It runs under the
+	// ring's read lock and must not call back into the ring.
+	Match func(*Node) bool
+}
+
+// matches reports whether a node with the given state passes the filter
+func (f NodeFilter) matches(node *Node, state NodeState) bool {
+	if f.Zone != "" && node.Zone != f.Zone {
+		return false
+	}
+	if len(f.States) > 0 && !slices.Contains(f.States, state) {
+		return false
+	}
+	if len(f.Labels) > 0 && !MatchLabels(f.Labels)(node) {
+		return false
+	}
+	return f.Match == nil || f.Match(node)
+}
+
+// sortedNodes caches the membership sorted by ID for one generation
+type sortedNodes struct {
+	generation uint64
+	nodes      []*Node
+}
+
+// ListNodes returns one page of the nodes matching filter, in ID order,
+// together with the total number of matches. A limit of zero returns every
+// match after offset. The membership is sorted once per generation rather
+// than on every call, so paging through a large ring stays cheap.
+func (hr *HashRing) ListNodes(filter NodeFilter, offset, limit int) ([]*Node, int, error) {
+	if offset < 0 || limit < 0 {
+		return nil, 0, errors.New("offset and limit cannot be negative")
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	var page []*Node
+	total := 0
+	for _, node := range hr.sortedViewLocked() {
+		if !filter.matches(node, hr.states[node.ID]) {
+			continue
+		}
+		if total >= offset && (limit == 0 || len(page) < limit) {
+			page = append(page, node)
+		}
+		total++
+	}
+
+	return page, total, nil
+}
+
+// sortedViewLocked returns the members sorted by ID. The slice is shared
+// between callers and must not be modified. Callers must hold hr.mu.
+func (hr *HashRing) sortedViewLocked() []*Node {
+	if cached := hr.sorted.Load(); cached != nil && cached.generation == hr.generation {
+		return cached.nodes
+	}
+
+	nodes := make([]*Node, 0, len(hr.nodes))
+	for _, node := range hr.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	// Concurrent readers may race to fill the cache, but they build the same
+	// slice for a generation
+	hr.sorted.Store(&sortedNodes{generation: hr.generation, nodes: nodes})
+	return nodes
+}
+
+// RankBy selects the measure RankNodes orders by
+type RankBy int
+
+const (
+	RankByOwnership RankBy = iota // Share of the hash space
+	RankByLookups                 // Sampled lookups served (requires WithKeySampling)
+	RankByLoad                    // Tracked load (requires WithLoadTracking)
+)
+
+// NodeRank is a node and its value in a ranking
+type NodeRank struct {
+	Node  *Node
+	Value float64
+}
+
+// RankNodes lists every node ordered by the given measure, most loaded
+// first, so dashboards can show the extremes without computing statistics
+// themselves. Ties are broken by ID.
+func (hr *HashRing) RankNodes(by RankBy) ([]NodeRank, error) {
+	// Sampler and load tracker have their own locks, so read them first
+	var values map[string]float64
+	switch by {
+	case RankByOwnership:
+	case RankByLookups:
+		counts, err := hr.LookupCounts()
+		if err != nil {
+			return nil, err
+		}
+		values = make(map[string]float64, len(counts))
+		for id, count := range counts {
+			values[id] = float64(count)
+		}
+	case RankByLoad:
+		if hr.loads == nil {
+			return nil, ErrLoadTrackingDisabled
+		}
+		hr.loads.mu.Lock()
+		values = make(map[string]float64, len(hr.loads.load))
+		for id := range hr.loads.load {
+			values[id] = hr.loads.valueLocked(id)
+		}
+		hr.loads.mu.Unlock()
+	default:
+		return nil, fmt.Errorf("unknown ranking %d", by)
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if by == RankByOwnership {
+		values = hr.ownershipLocked()
+	}
+
+	ranks := make([]NodeRank, 0, len(hr.nodes))
+	for _, node := range hr.sortedViewLocked() {
+		ranks = append(ranks, NodeRank{Node: node, Value: values[node.ID]})
+	}
+	// The input is in ID order, so a stable sort keeps ties by ID
+	sort.SliceStable(ranks, func(i, j int) bool {
+		return ranks[i].Value > ranks[j].Value
+	})
+
+	return ranks, nil
+}
@@ -0,0 +1,125 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestListNodes(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		zone := "us-east-1a"
+		if i%2 == 1 {
+			zone = "us-east-1b"
+		}
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%02d", i), Host: "localhost", Port: 8080 + i, Zone: zone})
+	}
+
+	page, total, err := ring.ListNodes(NodeFilter{}, 3, 4)
+	if err != nil {
+		t.Fatalf("Failed to list nodes: %v", err)
+	}
+	if total != 10 || len(page) != 4 || page[0].ID != "node03" || page[3].ID != "node06" {
+		t.Errorf("Unexpected page %v of %d", page, total)
+	}
+
+	page, total, _ = ring.ListNodes(NodeFilter{Zone: "us-east-1b"}, 0, 0)
+	if total != 5 || len(page) != 5 || page[0].ID != "node01" {
+		t.Errorf("Expected the 5 nodes of us-east-1b, got %v of %d", page, total)
+	}
+
+	ring.SetNodeState("node02", NodeDown)
+	ring.SetNodeState("node04", NodeDraining)
+	page, total, _ = ring.ListNodes(NodeFilter{States: []NodeState{NodeDown, NodeDraining}}, 0, 1)
+	if total != 2 || len(page) != 1 || page[0].ID != "node02" {
+		t.Errorf("Expected node02 of 2 unhealthy nodes, got %v of %d", page, total)
+	}
+
+	page, total, _ = ring.ListNodes(NodeFilter{
+		Zone:  "us-east-1a",
+		Match: func(n *Node) bool { return strings.HasSuffix(n.ID, "8") },
+	}, 0, 10)
+	if total != 1 || page[0].ID != "node08" {
+		t.Errorf("Expected node08, got %v of %d", page, total)
+	}
+
+	// Past the end returns an empty page but the full count
+	if page, total, _ := ring.ListNodes(NodeFilter{}, 20, 5); len(page) != 0 || total != 10 {
+		t.Errorf("Expected an empty page of 10, got %v of %d", page, total)
+	}
+
+	if _, _, err := ring.ListNodes(NodeFilter{}, -1, 0); err == nil {
+		t.Error("Expected error for negative offset")
+	}
+}
+
+func TestListNodesFollowsMembership(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	ring.AddNode(&Node{ID: "b", Host: "localhost", Port: 8081})
+	ring.ListNodes(NodeFilter{}, 0, 0)
+
+	ring.AddNode(&Node{ID: "a", Host: "localhost", Port: 8080})
+	page, _, _ := ring.ListNodes(NodeFilter{}, 0, 0)
+	if len(page) != 2 || page[0].ID != "a" {
+		t.Errorf("Expected the cached order to be rebuilt, got %v", page)
+	}
+
+	// Callers of GetAllNodes get their own copy
+	all := ring.GetAllNodes()
+	all[0] = nil
+	if page, _, _ := ring.ListNodes(NodeFilter{}, 0, 1); page[0] == nil {
+		t.Error("Expected GetAllNodes to return a copy")
+	}
+}
+
+func TestRankNodes(t *testing.T) {
+	ring, _ := NewHashRing(50, WithKeySampling(10), WithLoadTracking(nil))
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i, Weight: i + 1})
+	}
+
+	ranks, err := ring.RankNodes(RankByOwnership)
+	if err != nil {
+		t.Fatalf("Failed to rank nodes: %v", err)
+	}
+	if len(ranks) != 4 || ranks[0].Node.ID != "node3" || ranks[3].Node.ID != "node0" {
+		t.Errorf("Expected heaviest node first, got %v", ranks)
+	}
+	for i := 1; i < len(ranks); i++ {
+		if ranks[i].Value > ranks[i-1].Value {
+			t.Errorf("Ranks out of order at %d: %v", i, ranks)
+		}
+	}
+
+	ring.RecordLoad("node1", 5)
+	ring.RecordLoad("node2", 9)
+	ranks, _ = ring.RankNodes(RankByLoad)
+	if ranks[0].Node.ID != "node2" || ranks[1].Node.ID != "node1" || ranks[2].Node.ID != "node0" || ranks[0].Value != 9 {
+		t.Errorf("Expected node2, node1 then ties by ID, got %v", ranks)
+	}
+
+	for i := 0; i < 1000; i++ {
+		ring.GetNode(fmt.Sprintf("key%d", i))
+	}
+	ranks, _ = ring.RankNodes(RankByLookups)
+	counts, _ := ring.LookupCounts()
+	if ranks[0].Value != float64(counts[ranks[0].Node.ID]) {
+		t.Errorf("Expected lookup counts as values, got %v", ranks)
+	}
+
+	plain, _ := NewHashRing(10)
+	if _, err := plain.RankNodes(RankByLookups); !errors.Is(err, ErrSamplingDisabled) {
+		t.Errorf("Expected ErrSamplingDisabled, got %v", err)
+	}
+	if _, err := plain.RankNodes(RankByLoad); !errors.Is(err, ErrLoadTrackingDisabled) {
+		t.Errorf("Expected ErrLoadTrackingDisabled, got %v", err)
+	}
+	if _, err := plain.RankNodes(RankBy(9)); err == nil {
+		t.Error("Expected error for an unknown ranking")
+	}
+}
@@ -0,0 +1,174 @@
+package consistenthashing
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrLoadTrackingDisabled is returned by load APIs when the ring was created
+// without WithLoadTracking
+var ErrLoadTrackingDisabled = errors.New("load tracking is not enabled")
+
+// Overload describes a node whose tracked load rose above its capacity
+type Overload struct {
+	NodeID   string
+	Load     int64
+	Capacity int64
+}
+
+// WithLoadTracking enables per-node load counters fed by RecordLoad.
+// onOverload, if not nil, is called when a node's load rises above the
+// capacity set with SetNodeCapacity, so callers can shed load or scale out
+// before errors start. It fires once per crossing, outside any ring lock.
+func WithLoadTracking(onOverload func(Overload)) Option {
+	return func(hr *HashRing) {
+		hr.loads = &loadTracker{
+			load:       make(map[string]loadCounter),
+			capacity:   make(map[string]int64),
+			overloaded: make(map[string]bool),
+			onOverload: onOverload,
+		}
+	}
+}
+
+// WithLoadDecay makes the load counters decay exponentially with the given
+// half-life, so load recorded halfLife ago counts half as much as load
+// recorded now. Routing and overload checks then follow recent load rather
+// than lifetime totals. Decayed counters suit rates of work, recorded with
+// positive deltas as it arrives, rather than in-flight counts. Time comes
+// from the ring's clock.
+func WithLoadDecay(halfLife time.Duration) Option {
+	return func(hr *HashRing) {
+		hr.loadHalfLife = halfLife
+	}
+}
+
+// loadTracker holds the load counters. Like keySampler it has its own lock,
+// so recording load never waits for the ring lock.
+type loadTracker struct {
+	mu         optionalMutex
+	load       map[string]loadCounter
+	halfLife   time.Duration    // Zero keeps raw counts
+	clock      Clock            // Set by configure
+	capacity   map[string]int64 // Zero or missing means unlimited
+	overloaded map[string]bool
+	onOverload func(Overload)
+}
+
+// loadCounter is a node's load as of the time it last changed
+type loadCounter struct {
+	value float64
+	at    time.Time // Only set when decaying
+}
+
+// valueLocked returns a node's current load, decayed to now. Callers must
+// hold t.mu.
+func (t *loadTracker) valueLocked(nodeID string) float64 {
+	c := t.load[nodeID]
+	if t.halfLife == 0 || c.value == 0 {
+		return c.value
+	}
+	elapsed := t.clock.Now().Sub(c.at)
+	return c.value * math.Exp2(-float64(elapsed)/float64(t.halfLife))
+}
+
+// addLocked adds delta to a node's current load. Callers must hold t.mu.
+func (t *loadTracker) addLocked(nodeID string, delta int64) {
+	c := loadCounter{value: t.valueLocked(nodeID) + float64(delta)}
+	if t.halfLife > 0 {
+		c.at = t.clock.Now()
+	}
+	t.load[nodeID] = c
+}
+
+// update applies fn to the counters and reports whether the node just
+// crossed its capacity
+func (t *loadTracker) update(nodeID string, fn func(id string)) (Overload, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fn(nodeID)
+	load, capacity := int64(math.Round(t.valueLocked(nodeID))), t.capacity[nodeID]
+	if capacity == 0 || load <= capacity {
+		delete(t.overloaded, nodeID)
+		return Overload{}, false
+	}
+	if t.overloaded[nodeID] {
+		return Overload{}, false
+	}
+	t.overloaded[nodeID] = true
+	return Overload{NodeID: nodeID, Load: load, Capacity: capacity}, true
+}
+
+// forget drops the counters of a node that left the ring
+func (t *loadTracker) forget(nodeID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.load, nodeID)
+	delete(t.capacity, nodeID)
+	delete(t.overloaded, nodeID)
+}
+
+// RecordLoad adds delta to a node's tracked load. Use positive deltas when
+// work starts and negative ones when it finishes to track in-flight load.
+func (hr *HashRing) RecordLoad(nodeID string, delta int64) error {
+	return hr.updateLoad(nodeID, func(id string) {
+		hr.loads.addLocked(id, delta)
+	})
+}
+
+// SetNodeCapacity sets the load above which a node counts as overloaded.
+// Zero removes the limit.
+func (hr *HashRing) SetNodeCapacity(nodeID string, capacity int64) error {
+	if capacity < 0 {
+		return errors.New("capacity cannot be negative")
+	}
+
+	return hr.updateLoad(nodeID, func(id string) {
+		if capacity == 0 {
+			delete(hr.loads.capacity, id)
+		} else {
+			hr.loads.capacity[id] = capacity
+		}
+	})
+}
+
+// updateLoad applies fn to the counters of a member and runs the overload
+// callback once the ring lock is released. fn gets the member's own ID, so the
+// counters share its memory rather than holding the caller's copy.
+func (hr *HashRing) updateLoad(nodeID string, fn func(id string)) error {
+	if hr.loads == nil {
+		return ErrLoadTrackingDisabled
+	}
+
+	// Holding the read lock keeps a concurrent removal from leaving counters
+	// behind for a departed node
+	hr.mu.RLock()
+	node, exists := hr.nodes[nodeID]
+	if !exists {
+		hr.mu.RUnlock()
+		return ErrNodeNotFound
+	}
+	overload, crossed := hr.loads.update(node.ID, fn)
+	hr.mu.RUnlock()
+
+	if crossed && hr.loads.onOverload != nil {
+		hr.loads.onOverload(overload)
+	}
+	return nil
+}
+
+// NodeLoad returns a node's tracked load, rounded and decayed to now under
+// WithLoadDecay
+func (hr *HashRing) NodeLoad(nodeID string) (int64, error) {
+	if hr.loads == nil {
+		return 0, ErrLoadTrackingDisabled
+	}
+
+	hr.loads.mu.Lock()
+	defer hr.loads.mu.Unlock()
+
+	return int64(math.Round(hr.loads.valueLocked(nodeID))), nil
+}
@@ -0,0 +1,128 @@
+package consistenthashing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadTracking(t *testing.T) {
+	var overloads []Overload
+	ring, err := NewHashRing(10, WithLoadTracking(func(o Overload) {
+		overloads = append(overloads, o)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+
+	if err := ring.SetNodeCapacity("node0", 2); err != nil {
+		t.Fatalf("Failed to set capacity: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		ring.RecordLoad("node0", 1)
+	}
+	if load, _ := ring.NodeLoad("node0"); load != 4 {
+		t.Errorf("Expected load 4, got %d", load)
+	}
+	if len(overloads) != 1 || overloads[0] != (Overload{NodeID: "node0", Load: 3, Capacity: 2}) {
+		t.Fatalf("Expected one overload at load 3, got %+v", overloads)
+	}
+
+	// Falling back under capacity re-arms the callback
+	ring.RecordLoad("node0", -3)
+	ring.RecordLoad("node0", 2)
+	if len(overloads) != 2 {
+		t.Errorf("Expected a second overload, got %+v", overloads)
+	}
+
+	// Lowering the capacity can overload a node too
+	ring.RecordLoad("node0", -2)
+	ring.SetNodeCapacity("node0", 0)
+	ring.RecordLoad("node0", 100)
+	ring.SetNodeCapacity("node0", 50)
+	if len(overloads) != 3 || overloads[2].Capacity != 50 {
+		t.Errorf("Expected an overload from the new capacity, got %+v", overloads)
+	}
+
+	// Counters are dropped when the node leaves
+	ring.RemoveNode("node0")
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+	if load, _ := ring.NodeLoad("node0"); load != 0 {
+		t.Errorf("Expected a rejoined node to start at zero, got %d", load)
+	}
+
+	if err := ring.RecordLoad("missing", 1); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if err := ring.SetNodeCapacity("node0", -1); err == nil {
+		t.Error("Expected error for negative capacity")
+	}
+}
+
+func TestLoadTrackingDisabled(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+
+	if err := ring.RecordLoad("node0", 1); !errors.Is(err, ErrLoadTrackingDisabled) {
+		t.Errorf("Expected ErrLoadTrackingDisabled, got %v", err)
+	}
+	if _, err := ring.NodeLoad("node0"); !errors.Is(err, ErrLoadTrackingDisabled) {
+		t.Errorf("Expected ErrLoadTrackingDisabled, got %v", err)
+	}
+}
+
+func TestLoadDecay(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var overloads []Overload
+	ring, err := NewHashRing(10, WithLoadDecay(time.Minute), WithClock(clock), WithLoadTracking(func(o Overload) {
+		overloads = append(overloads, o)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8081})
+
+	ring.RecordLoad("node0", 800)
+	if load, _ := ring.NodeLoad("node0"); load != 800 {
+		t.Errorf("Expected load 800, got %d", load)
+	}
+
+	// Every half-life halves the load
+	clock.now = clock.now.Add(time.Minute)
+	if load, _ := ring.NodeLoad("node0"); load != 400 {
+		t.Errorf("Expected load 400 after one half-life, got %d", load)
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	if load, _ := ring.NodeLoad("node0"); load != 100 {
+		t.Errorf("Expected load 100 after three half-lives, got %d", load)
+	}
+
+	// New load adds to what is left, and recent load outranks old load
+	ring.RecordLoad("node1", 150)
+	ring.RecordLoad("node0", 20)
+	if load, _ := ring.NodeLoad("node0"); load != 120 {
+		t.Errorf("Expected load 120, got %d", load)
+	}
+	ranks, _ := ring.RankNodes(RankByLoad)
+	if ranks[0].Node.ID != "node1" {
+		t.Errorf("Expected node1 to rank first by recent load, got %+v", ranks)
+	}
+
+	// Overloads are judged on the decayed load
+	ring.SetNodeCapacity("node0", 100)
+	if len(overloads) != 1 || overloads[0].Load != 120 {
+		t.Fatalf("Expected an overload at load 120, got %+v", overloads)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	ring.RecordLoad("node0", 1)
+	ring.RecordLoad("node0", 50)
+	if len(overloads) != 2 || overloads[1].Load != 111 {
+		t.Errorf("Expected a second overload at load 111 after decaying under capacity, got %+v", overloads)
+	}
+
+	if _, err := NewHashRing(10, WithLoadTracking(nil), WithLoadDecay(-time.Second)); err == nil {
+		t.Error("Expected error for a negative half-life")
+	}
+}
@@ -0,0 +1,187 @@
+package consistenthashing
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrLockMetricsDisabled is returned by LockStats when the ring was created
+// without WithLockMetrics
+var ErrLockMetricsDisabled = errors.New("lock metrics are not enabled")
+
+// WithLockMetrics records how often and how long lookups and mutations wait
+// for the ring lock, so contention from frequent membership changes is visible
+func WithLockMetrics() Option {
+	return func(hr *HashRing) {
+		hr.mu.stats = &lockCounters{}
+	}
+}
+
+// WithNoLocking removes all internal locking from the ring, including the key
+// sampler's. Use it only when the caller guarantees that the ring is never
+// accessed from more than one goroutine at a time, such as a single-goroutine
+// control loop; concurrent use of an unlocked ring is a data race.
+func WithNoLocking() Option {
+	return func(hr *HashRing) {
+		hr.mu.disabled = true
+	}
+}
+
+// LockStats summarizes contention on the ring lock. An acquisition is
+// contended when the lock was not immediately available.
+type LockStats struct {
+	ReadAcquisitions  uint64
+	ReadContended     uint64
+	ReadWait          time.Duration // Total time readers spent waiting
+	MaxReadWait       time.Duration
+	WriteAcquisitions uint64
+	WriteContended    uint64
+	WriteWait         time.Duration // Total time writers spent waiting
+	MaxWriteWait      time.Duration
+}
+
+// LockStats returns the lock contention counters collected since the ring was
+// created or the counters were last reset
+func (hr *HashRing) LockStats() (LockStats, error) {
+	c := hr.mu.stats
+	if c == nil {
+		return LockStats{}, ErrLockMetricsDisabled
+	}
+
+	return LockStats{
+		ReadAcquisitions:  c.read.acquisitions.Load(),
+		ReadContended:     c.read.contended.Load(),
+		ReadWait:          time.Duration(c.read.wait.Load()),
+		MaxReadWait:       time.Duration(c.read.maxWait.Load()),
+		WriteAcquisitions: c.write.acquisitions.Load(),
+		WriteContended:    c.write.contended.Load(),
+		WriteWait:         time.Duration(c.write.wait.Load()),
+		MaxWriteWait:      time.Duration(c.write.maxWait.Load()),
+	}, nil
+}
+
+// ResetLockStats zeroes the lock contention counters
+func (hr *HashRing) ResetLockStats() error {
+	c := hr.mu.stats
+	if c == nil {
+		return ErrLockMetricsDisabled
+	}
+
+	c.read.reset()
+	c.write.reset()
+	return nil
+}
+
+// waitCounters accumulates acquisitions of one lock mode
+type waitCounters struct {
+	acquisitions atomic.Uint64
+	contended    atomic.Uint64
+	wait         atomic.Int64
+	maxWait      atomic.Int64
+}
+
+func (c *waitCounters) acquired(wait time.Duration) {
+	c.acquisitions.Add(1)
+	if wait <= 0 {
+		return
+	}
+
+	c.contended.Add(1)
+	c.wait.Add(int64(wait))
+	for {
+		current := c.maxWait.Load()
+		if int64(wait) <= current || c.maxWait.CompareAndSwap(current, int64(wait)) {
+			return
+		}
+	}
+}
+
+func (c *waitCounters) reset() {
+	c.acquisitions.Store(0)
+	c.contended.Store(0)
+	c.wait.Store(0)
+	c.maxWait.Store(0)
+}
+
+// lockCounters holds the read and write counters of a ringMutex
+type lockCounters struct {
+	read  waitCounters
+	write waitCounters
+}
+
+// ringMutex is the ring's read-write lock. When metrics are enabled it first
+// tries to take the lock without blocking, so uncontended acquisitions don't
+// pay for reading the clock.
+type ringMutex struct {
+	rw       sync.RWMutex
+	stats    *lockCounters // Optional contention counters
+	disabled bool          // Set by WithNoLocking
+}
+
+func (m *ringMutex) Lock() {
+	if m.disabled {
+		return
+	}
+	if m.stats == nil {
+		m.rw.Lock()
+		return
+	}
+	if m.rw.TryLock() {
+		m.stats.write.acquired(0)
+		return
+	}
+
+	start := time.Now()
+	m.rw.Lock()
+	m.stats.write.acquired(time.Since(start))
+}
+
+func (m *ringMutex) Unlock() {
+	if !m.disabled {
+		m.rw.Unlock()
+	}
+}
+
+func (m *ringMutex) RLock() {
+	if m.disabled {
+		return
+	}
+	if m.stats == nil {
+		m.rw.RLock()
+		return
+	}
+	if m.rw.TryRLock() {
+		m.stats.read.acquired(0)
+		return
+	}
+
+	start := time.Now()
+	m.rw.RLock()
+	m.stats.read.acquired(time.Since(start))
+}
+
+func (m *ringMutex) RUnlock() {
+	if !m.disabled {
+		m.rw.RUnlock()
+	}
+}
+
+// optionalMutex is a mutex that becomes a no-op once disabled
+type optionalMutex struct {
+	mu       sync.Mutex
+	disabled bool
+}
+
+func (m *optionalMutex) Lock() {
+	if !m.disabled {
+		m.mu.Lock()
+	}
+}
+
+func (m *optionalMutex) Unlock() {
+	if !m.disabled {
+		m.mu.Unlock()
+	}
+}
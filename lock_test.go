@@ -0,0 +1,135 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockStatsDisabled(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if _, err := ring.LockStats(); !errors.Is(err, ErrLockMetricsDisabled) {
+		t.Errorf("Expected ErrLockMetricsDisabled, got %v", err)
+	}
+	if err := ring.ResetLockStats(); !errors.Is(err, ErrLockMetricsDisabled) {
+		t.Errorf("Expected ErrLockMetricsDisabled, got %v", err)
+	}
+}
+
+func TestLockStats(t *testing.T) {
+	ring, err := NewHashRing(10, WithLockMetrics())
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.GetNode("key")
+	ring.GetNode("other")
+
+	stats, err := ring.LockStats()
+	if err != nil {
+		t.Fatalf("Failed to get lock stats: %v", err)
+	}
+	if stats.WriteAcquisitions != 1 || stats.ReadAcquisitions != 2 {
+		t.Errorf("Expected 1 write and 2 read acquisitions, got %+v", stats)
+	}
+	if stats.ReadContended != 0 || stats.WriteContended != 0 {
+		t.Errorf("Expected no contention, got %+v", stats)
+	}
+
+	// Hold the write lock so a lookup has to wait for it
+	ring.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		ring.GetNode("key")
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	ring.mu.Unlock()
+	<-done
+
+	stats, _ = ring.LockStats()
+	if stats.ReadContended != 1 {
+		t.Errorf("Expected 1 contended read, got %d", stats.ReadContended)
+	}
+	if stats.ReadWait <= 0 || stats.MaxReadWait <= 0 || stats.MaxReadWait > stats.ReadWait {
+		t.Errorf("Expected positive read wait bounded by the total, got %+v", stats)
+	}
+
+	if err := ring.ResetLockStats(); err != nil {
+		t.Fatalf("Failed to reset lock stats: %v", err)
+	}
+	if stats, _ = ring.LockStats(); stats != (LockStats{}) {
+		t.Errorf("Expected zeroed stats after reset, got %+v", stats)
+	}
+}
+
+func TestWriteMetricsLockStats(t *testing.T) {
+	ring, err := NewHashRing(10, WithLockMetrics())
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+
+	var buf bytes.Buffer
+	if err := ring.WriteMetrics(&buf); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+
+	for _, line := range []string{
+		"consistent_hash_lock_acquisitions_total{mode=\"write\"} 1\n",
+		"# TYPE consistent_hash_lock_wait_seconds counter\n",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Expected metrics output to contain %q", line)
+		}
+	}
+}
+
+func TestWithNoLocking(t *testing.T) {
+	ring, err := NewHashRing(10, WithKeySampling(5), WithNoLocking(), WithLockMetrics())
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if !ring.sampler.mu.disabled {
+		t.Error("Expected WithNoLocking to disable the sampler lock regardless of option order")
+	}
+
+	// The internal lock is a no-op, so holding it must not block the ring
+	ring.mu.Lock()
+	if err := ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	if node, err := ring.GetNode("key"); err != nil || node.ID != "node1" {
+		t.Errorf("Expected node1, got %v (%v)", node, err)
+	}
+	ring.mu.Unlock()
+
+	if counts, err := ring.LookupCounts(); err != nil || counts["node1"] != 1 {
+		t.Errorf("Expected one sampled lookup, got %v (%v)", counts, err)
+	}
+
+	// No locks are taken, so there is nothing to count
+	if stats, _ := ring.LockStats(); stats != (LockStats{}) {
+		t.Errorf("Expected no lock acquisitions, got %+v", stats)
+	}
+}
+
+func BenchmarkGetNodeNoLocking(b *testing.B) {
+	ring, _ := NewHashRing(150, WithNoLocking())
+	for i := 0; i < 10; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ring.GetNode("benchmark-key")
+	}
+}
@@ -0,0 +1,234 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultMaglevTableSize is the lookup table size used when none is given.
+// It is prime, as Maglev requires, and comfortably above 100 times the node
+// count of typical clusters.
+const DefaultMaglevTableSize = 65537
+
+// maglevSkipSalt decorrelates a node's permutation skip from its offset
+const maglevSkipSalt = 0x9e3779b97f4a7c15
+
+// ErrInvalidTableSize is returned for Maglev table sizes that are not prime
+var ErrInvalidTableSize = errors.New("maglev table size must be prime")
+
+// MaglevRing places keys with Maglev hashing: every node fills slots of a
+// fixed-size lookup table following its own permutation, so lookups are a
+// single table read and nodes own near-equal shares of the table. Membership
+// changes rebuild the table and move slightly more keys than a vnode ring.
+// Lookups don't take locks. It implements Ring.
+type MaglevRing struct {
+	hasher HashFunction
+	size   int
+	nodes  map[string]*Node
+	table  atomic.Pointer[[]*Node] // Slot to owner; nil while empty
+	mu     sync.Mutex              // Serializes membership changes
+}
+
+// MaglevRing must satisfy Ring
+var _ Ring = (*MaglevRing)(nil)
+
+// NewMaglevRing creates an empty Maglev ring with a lookup table of tableSize
+// slots, which must be prime and should be well above 100 times the node
+// count for good balance. Zero means DefaultMaglevTableSize, and a nil
+// hasher means FNVHasher.
+func NewMaglevRing(tableSize int, hasher HashFunction) (*MaglevRing, error) {
+	if tableSize == 0 {
+		tableSize = DefaultMaglevTableSize
+	}
+	if tableSize < 2 || !big.NewInt(int64(tableSize)).ProbablyPrime(0) {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidTableSize, tableSize)
+	}
+	if hasher == nil {
+		hasher = &FNVHasher{}
+	}
+	return &MaglevRing{hasher: hasher, size: tableSize, nodes: make(map[string]*Node)}, nil
+}
+
+// TableSize returns the number of slots in the lookup table
+func (m *MaglevRing) TableSize() int {
+	return m.size
+}
+
+// AddNode adds a node and rebuilds the table. Adding an existing ID is a
+// no-op, as with HashRing.
+func (m *MaglevRing) AddNode(node *Node) error {
+	if node == nil {
+		return errors.New("node cannot be nil")
+	}
+	if err := node.Validate(); err != nil {
+		return fmt.Errorf("invalid node: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.nodes[node.ID]; exists {
+		return nil
+	}
+	if len(m.nodes) >= m.size {
+		return fmt.Errorf("maglev table of %d slots is full", m.size)
+	}
+
+	m.nodes[node.ID] = node
+	m.rebuildLocked()
+	return nil
+}
+
+// RemoveNode removes a node and rebuilds the table
+func (m *MaglevRing) RemoveNode(nodeID string) error {
+	if strings.TrimSpace(nodeID) == "" {
+		return ErrInvalidNodeID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.nodes[nodeID]; !exists {
+		return ErrNodeNotFound
+	}
+
+	delete(m.nodes, nodeID)
+	m.rebuildLocked()
+	return nil
+}
+
+// GetNode returns the owner of key's table slot
+func (m *MaglevRing) GetNode(key string) (*Node, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	table := m.table.Load()
+	if table == nil {
+		return nil, ErrEmptyRing
+	}
+	return (*table)[m.slot(key)], nil
+}
+
+// GetNodes returns up to count distinct nodes for key: the owner of its slot
+// followed by the owners of the next slots
+func (m *MaglevRing) GetNodes(key string, count int) ([]*Node, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+
+	table := m.table.Load()
+	if table == nil {
+		return nil, ErrEmptyRing
+	}
+
+	slots := *table
+	nodes := make([]*Node, 0, count)
+	seen := make(map[*Node]bool, count)
+	for i, start := 0, m.slot(key); i < len(slots) && len(nodes) < count; i++ {
+		node := slots[(start+i)%len(slots)]
+		if !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// GetAllNodes returns all nodes sorted by ID
+func (m *MaglevRing) GetAllNodes() []*Node {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.sortedLocked()
+}
+
+// GetNodeByID returns a node by its ID
+func (m *MaglevRing) GetNodeByID(nodeID string) (*Node, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, exists := m.nodes[nodeID]
+	if !exists {
+		return nil, ErrNodeNotFound
+	}
+	return node, nil
+}
+
+// HasNode reports whether a node is in the ring
+func (m *MaglevRing) HasNode(nodeID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, exists := m.nodes[nodeID]
+	return exists
+}
+
+// Size returns the number of nodes
+func (m *MaglevRing) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.nodes)
+}
+
+// slot returns the table slot of a key
+func (m *MaglevRing) slot(key string) int {
+	return int(mix64(m.hasher.Hash(key)) % uint64(m.size))
+}
+
+// sortedLocked returns the nodes sorted by ID
+func (m *MaglevRing) sortedLocked() []*Node {
+	nodes := make([]*Node, 0, len(m.nodes))
+	for _, node := range m.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes
+}
+
+// rebuildLocked fills a new lookup table and publishes it. Nodes take turns
+// claiming the next free slot of their permutation, with weight turns per
+// round, so each owns a share of slots proportional to its weight.
+func (m *MaglevRing) rebuildLocked() {
+	if len(m.nodes) == 0 {
+		m.table.Store(nil)
+		return
+	}
+
+	nodes := m.sortedLocked()
+	size := uint64(m.size)
+	positions := make([]uint64, len(nodes))
+	skips := make([]uint64, len(nodes))
+	for i, node := range nodes {
+		h := mix64(m.hasher.Hash(node.ID))
+		positions[i] = h % size
+		skips[i] = mix64(h^maglevSkipSalt)%(size-1) + 1
+	}
+
+	table := make([]*Node, m.size)
+	for filled := 0; ; {
+		for i, node := range nodes {
+			for turn := nodeWeight(node); turn > 0; turn-- {
+				for table[positions[i]] != nil {
+					positions[i] = (positions[i] + skips[i]) % size
+				}
+				table[positions[i]] = node
+				if filled++; filled == m.size {
+					m.table.Store(&table)
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMaglevRing(t *testing.T) {
+	ring, err := NewMaglevRing(0, nil)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if ring.TableSize() != DefaultMaglevTableSize {
+		t.Errorf("Expected default table size, got %d", ring.TableSize())
+	}
+	if _, err := ring.GetNode("key"); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i}); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+
+	// Each node owns a near-equal share of the table
+	slots := make(map[string]int)
+	for _, node := range *ring.table.Load() {
+		slots[node.ID]++
+	}
+	for id, count := range slots {
+		if count < 6500 || count > 6600 {
+			t.Errorf("Node %s owns %d of %d slots, expected about 6554", id, count, ring.TableSize())
+		}
+	}
+
+	keys := make([]string, 10000)
+	owners := make(map[string]string, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user:%d", i)
+		node, err := ring.GetNode(keys[i])
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		owners[keys[i]] = node.ID
+
+		nodes, _ := ring.GetNodes(keys[i], 3)
+		if len(nodes) != 3 || nodes[0] != node || nodes[1] == nodes[2] || nodes[0] == nodes[1] {
+			t.Fatalf("Expected 3 distinct replicas led by the owner for %s", keys[i])
+		}
+	}
+
+	// Removing a node moves its keys and only a little more
+	if err := ring.RemoveNode("node4"); err != nil {
+		t.Fatalf("Failed to remove node: %v", err)
+	}
+	moved := 0
+	for _, key := range keys {
+		node, _ := ring.GetNode(key)
+		if owners[key] != "node4" && node.ID != owners[key] {
+			moved++
+		}
+	}
+	if moved > len(keys)/50 {
+		t.Errorf("Expected under 2%% of other keys to move, got %d of %d", moved, len(keys))
+	}
+	if nodes, _ := ring.GetNodes("key", 20); len(nodes) != 9 {
+		t.Errorf("Expected GetNodes to cap at the ring size, got %d", len(nodes))
+	}
+}
+
+func TestMaglevRingWeights(t *testing.T) {
+	ring, err := NewMaglevRing(10007, &SHA256Hasher{})
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "light", Host: "localhost", Port: 8080, Weight: 1})
+	ring.AddNode(&Node{ID: "heavy", Host: "localhost", Port: 8081, Weight: 3})
+
+	heavy := 0
+	for _, node := range *ring.table.Load() {
+		if node.ID == "heavy" {
+			heavy++
+		}
+	}
+	if heavy < 7400 || heavy > 7600 {
+		t.Errorf("Expected heavy node to own about 75%% of 10007 slots, got %d", heavy)
+	}
+}
+
+func TestMaglevRingTableSize(t *testing.T) {
+	for _, size := range []int{-1, 1, 4, 65536} {
+		if _, err := NewMaglevRing(size, nil); !errors.Is(err, ErrInvalidTableSize) {
+			t.Errorf("Expected ErrInvalidTableSize for %d, got %v", size, err)
+		}
+	}
+
+	ring, err := NewMaglevRing(3, nil)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	if err := ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 8083}); err == nil {
+		t.Error("Expected a full table to reject more nodes")
+	}
+}
+
+func BenchmarkMaglevGetNode(b *testing.B) {
+	ring, _ := NewMaglevRing(0, nil)
+
+	// Add nodes
+	for i := 0; i < 10; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := fmt.Sprintf("key%d", i%1000)
+		ring.GetNode(key)
+	}
+}
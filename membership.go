@@ -0,0 +1,214 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrMemberConflict is returned by SetMembers under ConflictError when a node
+// differs from the member with the same ID
+var ErrMemberConflict = errors.New("node conflicts with existing member")
+
+// ConflictPolicy decides what SetMembers does with a node whose ID is already
+// a member but whose weight, address, zone, class or metadata differ
+type ConflictPolicy int
+
+const (
+	// ConflictUpdate updates the member in place, keeping its state and load
+	// counters. Only the replicas added or dropped by a weight change move
+	// keys. This is the default.
+	ConflictUpdate ConflictPolicy = iota
+	// ConflictReplace treats the node as a new machine under the old ID: the
+	// member's state and load counters are reset, and watchers see it removed
+	// and added again, so they can drop anything cached for it
+	ConflictReplace
+	// ConflictError rejects the whole update with ErrMemberConflict
+	ConflictError
+)
+
+// WithConflictPolicy sets how SetMembers and ReloadFromConfig handle nodes
+// that changed under an existing ID
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(hr *HashRing) {
+		hr.conflictPolicy = policy
+	}
+}
+
+// ChurnReport describes the effect of a membership change
+type ChurnReport struct {
+	Added         []string // IDs of added nodes, sorted
+	Removed       []string // IDs of removed nodes, sorted
+	Updated       []string // IDs of nodes whose weight, address, zone, class or metadata changed, sorted
+	Replaced      []string // IDs of changed nodes replaced under ConflictReplace, sorted
+	MovedFraction float64  // Share of the hash space that changed owner
+	Generation    uint64   // Ring generation after the change
+}
+
+// Changed reports whether the membership changed at all
+func (c ChurnReport) Changed() bool {
+	return len(c.Added) > 0 || len(c.Removed) > 0 || len(c.Updated) > 0 || len(c.Replaced) > 0
+}
+
+// SetMembers atomically replaces the ring's membership with nodes. Nodes are
+// matched by ID: new IDs are added, missing ones removed, and nodes whose
+// fields changed are handled by the ring's ConflictPolicy. Lookups never
+// observe a partial update, and invalid input leaves the ring untouched.
+func (hr *HashRing) SetMembers(nodes []*Node) (ChurnReport, error) {
+	members := make(map[string]*Node, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			return ChurnReport{}, errors.New("node cannot be nil")
+		}
+		if err := node.Validate(); err != nil {
+			return ChurnReport{}, fmt.Errorf("invalid node: %w", err)
+		}
+		if _, exists := members[node.ID]; exists {
+			return ChurnReport{}, fmt.Errorf("duplicate node ID %s", node.ID)
+		}
+		members[node.ID] = node
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	// Replica counts depend on the ring's capacity classes
+	for _, node := range nodes {
+		if _, err := hr.virtualCount(node); err != nil {
+			return ChurnReport{}, err
+		}
+	}
+
+	var report ChurnReport
+	for id, current := range hr.nodes {
+		node, keep := members[id]
+		switch {
+		case !keep:
+			report.Removed = append(report.Removed, id)
+		case !node.Equal(current) && hr.conflictPolicy == ConflictReplace:
+			report.Replaced = append(report.Replaced, id)
+		case !node.Equal(current):
+			report.Updated = append(report.Updated, id)
+		}
+	}
+	if hr.conflictPolicy == ConflictError && len(report.Updated) > 0 {
+		sort.Strings(report.Updated)
+		return ChurnReport{}, fmt.Errorf("%w: %s", ErrMemberConflict, strings.Join(report.Updated, ", "))
+	}
+	for id := range members {
+		if _, exists := hr.nodes[id]; !exists {
+			report.Added = append(report.Added, id)
+		}
+	}
+	sort.Strings(report.Added)
+	sort.Strings(report.Removed)
+	sort.Strings(report.Updated)
+	sort.Strings(report.Replaced)
+
+	if !report.Changed() {
+		report.Generation = hr.generation
+		return report, nil
+	}
+
+	before := slices.Clone(hr.virtualNodes)
+	gone := slices.Concat(report.Removed, report.Replaced)
+	removed := make(map[string]*Node, len(gone))
+	for _, id := range gone {
+		removed[id] = hr.nodes[id]
+		delete(hr.states, id)
+		delete(hr.drains, id)
+		if hr.loads != nil {
+			hr.loads.forget(id)
+		}
+		if hr.sampler != nil {
+			hr.sampler.forget(id)
+		}
+	}
+
+	// An updated or replaced node is re-added under the same ID, so its
+	// unchanged replicas land on the same positions. Updated nodes keep their
+	// load, state and sampled keys. The whole change is applied to hr.nodes
+	// first, then to the virtual nodes in one pass. The ring keeps copies of
+	// the members, so callers can't change them behind its back.
+	var dropped, placed []*Node
+	for _, id := range slices.Concat(gone, report.Updated) {
+		dropped = append(dropped, hr.nodes[id])
+		delete(hr.nodes, id)
+	}
+	for _, id := range slices.Concat(report.Added, report.Replaced, report.Updated) {
+		node := members[id].clone()
+		members[id] = &node
+		placed = append(placed, &node)
+		hr.nodes[id] = &node
+	}
+	if !hr.rescaleLocked() {
+		hr.dropVirtualNodesLocked(dropped...)
+		hr.placeMembersLocked(placed)
+	}
+	hr.advanceGenerationLocked()
+
+	for _, id := range report.Added {
+		hr.emitLocked(EventNodeAdded, members[id])
+	}
+	for _, id := range report.Removed {
+		hr.emitLocked(EventNodeRemoved, removed[id])
+	}
+	for _, id := range report.Updated {
+		hr.emitLocked(EventNodeUpdated, members[id])
+	}
+	for _, id := range report.Replaced {
+		hr.emitLocked(EventNodeRemoved, removed[id])
+		hr.emitLocked(EventNodeAdded, members[id])
+	}
+
+	report.MovedFraction = movedFraction(before, hr.virtualNodes)
+	report.Generation = hr.generation
+	return report, nil
+}
+
+// movedFraction returns the share of the hash space whose owner differs
+// between two sorted sets of virtual nodes. Between consecutive positions of
+// either set, each set has a single owner, so comparing the owners at every
+// position covers the whole ring.
+func movedFraction(before, after []VirtualNode) float64 {
+	switch {
+	case len(before) == 0 && len(after) == 0:
+		return 0
+	case len(before) == 0 || len(after) == 0:
+		return 1
+	}
+
+	bounds := make([]uint64, 0, len(before)+len(after))
+	for _, vnode := range before {
+		bounds = append(bounds, vnode.Hash)
+	}
+	for _, vnode := range after {
+		bounds = append(bounds, vnode.Hash)
+	}
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	moved := 0.0
+	for i, end := range bounds {
+		arc := HashRange{Start: bounds[(i+len(bounds)-1)%len(bounds)], End: end}
+		oldOwner := before[searchVirtualNodes(before, end)].Node.ID
+		newOwner := after[searchVirtualNodes(after, end)].Node.ID
+		if oldOwner != newOwner {
+			moved += arc.Fraction()
+		}
+	}
+
+	return moved
+}
+
+// String summarizes the report for logs
+func (c ChurnReport) String() string {
+	replaced := ""
+	if len(c.Replaced) > 0 {
+		replaced = fmt.Sprintf(", replaced [%s]", strings.Join(c.Replaced, ", "))
+	}
+	return fmt.Sprintf("added [%s], removed [%s], updated [%s]%s, %.2f%% of keys moved",
+		strings.Join(c.Added, ", "), strings.Join(c.Removed, ", "), strings.Join(c.Updated, ", "), replaced, c.MovedFraction*100)
+}
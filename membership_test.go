@@ -0,0 +1,204 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSetMembers(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	members := func(ids ...string) []*Node {
+		nodes := make([]*Node, len(ids))
+		for i, id := range ids {
+			nodes[i] = &Node{ID: id, Host: "localhost", Port: 8080 + i}
+		}
+		return nodes
+	}
+
+	report, err := ring.SetMembers(members("a", "b", "c", "d"))
+	if err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	if len(report.Added) != 4 || report.MovedFraction != 1 {
+		t.Errorf("Expected 4 additions moving the whole ring, got %+v", report)
+	}
+
+	// Invalid input leaves the ring untouched
+	generation := ring.Generation()
+	if _, err := ring.SetMembers(append(members("x"), &Node{ID: "x", Host: "h", Port: 1})); err == nil {
+		t.Error("Expected error for duplicate IDs")
+	}
+	if _, err := ring.SetMembers([]*Node{{ID: "bad"}}); err == nil {
+		t.Error("Expected error for invalid node")
+	}
+	if ring.Generation() != generation || ring.Size() != 4 {
+		t.Error("Expected failed SetMembers to leave the ring untouched")
+	}
+
+	ownership, _ := ring.EstimateKeys(1 << 20)
+	nodes := members("a", "b", "c", "e")
+	nodes[1].Weight = 2
+	report, err = ring.SetMembers(nodes)
+	if err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	if fmt.Sprint(report.Added, report.Removed, report.Updated) != "[e] [d] [b]" {
+		t.Errorf("Unexpected churn report %+v", report)
+	}
+	if report.Generation != ring.Generation() || ring.Generation() != generation+1 {
+		t.Errorf("Expected a single generation bump, got %d", report.Generation)
+	}
+	if err := ring.ValidateRingDeep(); err != nil {
+		t.Fatalf("Ring invalid after SetMembers: %v", err)
+	}
+
+	// The removed node's whole share moves, plus part of the others'
+	removedShare := float64(ownership["d"]) / (1 << 20)
+	if report.MovedFraction < removedShare-1e-9 || report.MovedFraction > 1 {
+		t.Errorf("Expected moved fraction of at least %f, got %f", removedShare, report.MovedFraction)
+	}
+
+	// The moved fraction matches what sampled keys observe
+	before := make(map[string]string)
+	for i := 0; i < 20000; i++ {
+		node, _ := ring.GetNode(fmt.Sprintf("key%d", i))
+		before[fmt.Sprintf("key%d", i)] = node.ID
+	}
+	report, _ = ring.SetMembers(members("a", "b", "c"))
+	moved := 0
+	for key, owner := range before {
+		if node, _ := ring.GetNode(key); node.ID != owner {
+			moved++
+		}
+	}
+	if observed := float64(moved) / float64(len(before)); math.Abs(observed-report.MovedFraction) > 0.03 {
+		t.Errorf("Reported moved fraction %f, observed %f", report.MovedFraction, observed)
+	}
+
+	// Setting the same members again is a no-op
+	generation = ring.Generation()
+	report, _ = ring.SetMembers(members("a", "b", "c"))
+	if report.Changed() || report.MovedFraction != 0 || ring.Generation() != generation {
+		t.Errorf("Expected no change, got %+v", report)
+	}
+}
+
+func TestMovedFraction(t *testing.T) {
+	a := &Node{ID: "a"}
+	b := &Node{ID: "b"}
+	before := []VirtualNode{{Hash: 1 << 62, Node: a}, {Hash: 1 << 63, Node: b}}
+
+	if f := movedFraction(before, before); f != 0 {
+		t.Errorf("Expected no movement, got %f", f)
+	}
+	if f := movedFraction(nil, before); f != 1 {
+		t.Errorf("Expected full movement from an empty ring, got %f", f)
+	}
+
+	// Once b owns both positions, the three quarters a owned across zero move
+	after := []VirtualNode{{Hash: 1 << 62, Node: b}, {Hash: 1 << 63, Node: b}}
+	if f := movedFraction(before, after); f != 0.75 {
+		t.Errorf("Expected 0.75 moved, got %f", f)
+	}
+}
+
+func TestSetMembersConflictPolicy(t *testing.T) {
+	initial := []*Node{
+		{ID: "node1", Host: "10.0.0.1", Port: 8080},
+		{ID: "node2", Host: "10.0.0.2", Port: 8080},
+	}
+	changed := []*Node{
+		{ID: "node1", Host: "10.0.0.9", Port: 8080},
+		{ID: "node2", Host: "10.0.0.2", Port: 8080},
+	}
+
+	newRing := func(policy ConflictPolicy) *HashRing {
+		ring, err := NewHashRing(20, WithConflictPolicy(policy), WithKeySampling(100))
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+		if _, err := ring.SetMembers(initial); err != nil {
+			t.Fatalf("Failed to set members: %v", err)
+		}
+		ring.SetNodeState("node1", NodeDraining)
+		return ring
+	}
+
+	// Update keeps the member's state and sampled keys
+	ring := newRing(ConflictUpdate)
+	for i := 0; i < 1000; i++ {
+		ring.GetNode(fmt.Sprintf("key%d", i))
+	}
+	sampled, _ := ring.SampledKeys()
+	report, err := ring.SetMembers(changed)
+	if err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	if fmt.Sprint(report.Updated) != "[node1]" || len(report.Replaced) != 0 {
+		t.Errorf("Expected node1 updated, got %v", report)
+	}
+	if state, _ := ring.NodeState("node1"); state != NodeDraining {
+		t.Errorf("Expected update to keep the draining state, got %v", state)
+	}
+	if after, _ := ring.SampledKeys(); len(after["node1"]) == 0 || len(after["node1"]) != len(sampled["node1"]) {
+		t.Errorf("Expected update to keep node1's %d sampled keys, got %d", len(sampled["node1"]), len(after["node1"]))
+	}
+
+	// The ring keeps its own copy of each member
+	update := *changed[0]
+	update.Host = "10.0.0.8"
+	if _, err := ring.SetMembers([]*Node{&update, changed[1]}); err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	update.Host = "10.0.0.7"
+	if node, _ := ring.GetNodeByID("node1"); node.Host != "10.0.0.8" {
+		t.Errorf("Expected the ring's copy of node1 to keep its host, got %s", node.Host)
+	}
+
+	// Replace resets state and is seen as a removal and an addition
+	ring = newRing(ConflictReplace)
+	events, cancel := ring.Watch(4)
+	defer cancel()
+	report, err = ring.SetMembers(changed)
+	if err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	if fmt.Sprint(report.Replaced) != "[node1]" || len(report.Updated) != 0 {
+		t.Errorf("Expected node1 replaced, got %v", report)
+	}
+	if state, _ := ring.NodeState("node1"); state != NodeActive {
+		t.Errorf("Expected replace to reset the state, got %v", state)
+	}
+	for _, want := range []EventType{EventNodeRemoved, EventNodeAdded} {
+		if event := <-events; event.Type != want || event.Node.ID != "node1" {
+			t.Errorf("Expected %v for node1, got %v for %v", want, event.Type, event.Node)
+		}
+	}
+	if node, _ := ring.GetNodeByID("node1"); node.Host != "10.0.0.9" {
+		t.Errorf("Expected replaced node to have the new host, got %s", node.Host)
+	}
+	if err := ring.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+
+	// Error rejects the update and leaves the ring untouched
+	ring = newRing(ConflictError)
+	generation := ring.Generation()
+	if _, err := ring.SetMembers(changed); !errors.Is(err, ErrMemberConflict) {
+		t.Errorf("Expected ErrMemberConflict, got %v", err)
+	}
+	if node, _ := ring.GetNodeByID("node1"); node.Host != "10.0.0.1" || ring.Generation() != generation {
+		t.Error("Expected a rejected update to leave the ring untouched")
+	}
+
+	// Additions and removals are never conflicts
+	if _, err := ring.SetMembers(initial[:1]); err != nil {
+		t.Errorf("Expected removal to succeed under ConflictError, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package consistenthashing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// metricsPrefix is prepended to every metric written by WriteMetrics
+const metricsPrefix = "consistent_hash"
+
+// labelEscaper escapes label values as required by the exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteMetrics writes ring statistics to w in the OpenMetrics/Prometheus text
+// exposition format, so they can be appended to an existing /metrics handler
+// without pulling in a metrics client library. The trailing "# EOF" marker is
+// not written; callers serving a standalone OpenMetrics response add it.
+func (hr *HashRing) WriteMetrics(w io.Writer) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+
+	reports := hr.nodeReports()
+	virtualNodes := 0
+	for _, r := range reports {
+		virtualNodes += r.VirtualNodes
+	}
+
+	bw := bufio.NewWriter(w)
+
+	writeFamily(bw, "physical_nodes", "gauge", "Number of physical nodes in the ring.")
+	fmt.Fprintf(bw, "%s_physical_nodes %d\n", metricsPrefix, len(reports))
+
+	writeFamily(bw, "virtual_nodes", "gauge", "Number of virtual nodes in the ring.")
+	fmt.Fprintf(bw, "%s_virtual_nodes %d\n", metricsPrefix, virtualNodes)
+
+	writeFamily(bw, "node_info", "gauge", "Zone, state, class and labels of each node, always 1.")
+	for _, r := range reports {
+		fmt.Fprintf(bw, "%s_node_info{node=\"%s\",zone=\"%s\",state=\"%s\"%s} 1\n", metricsPrefix,
+			labelEscaper.Replace(r.ID), labelEscaper.Replace(r.Zone), r.State, infoLabels(r))
+	}
+
+	writeFamily(bw, "node_weight", "gauge", "Configured weight of each node.")
+	for _, r := range reports {
+		fmt.Fprintf(bw, "%s_node_weight{node=\"%s\"} %d\n", metricsPrefix, labelEscaper.Replace(r.ID), r.Weight)
+	}
+
+	writeFamily(bw, "node_virtual_nodes", "gauge", "Number of virtual nodes owned by each node.")
+	for _, r := range reports {
+		fmt.Fprintf(bw, "%s_node_virtual_nodes{node=\"%s\"} %d\n", metricsPrefix, labelEscaper.Replace(r.ID), r.VirtualNodes)
+	}
+
+	writeFamily(bw, "node_ownership_ratio", "gauge", "Share of the hash space owned by each node.")
+	for _, r := range reports {
+		fmt.Fprintf(bw, "%s_node_ownership_ratio{node=\"%s\"} %g\n", metricsPrefix, labelEscaper.Replace(r.ID), r.Ownership)
+	}
+
+	// Lookup counters are only meaningful when key sampling is enabled
+	if hr.sampler != nil {
+		writeFamily(bw, "node_lookups", "counter", "Lookups served by each node since sampling was last reset.")
+		for _, r := range reports {
+			fmt.Fprintf(bw, "%s_node_lookups_total{node=\"%s\"} %d\n", metricsPrefix, labelEscaper.Replace(r.ID), r.Lookups)
+		}
+	}
+
+	// Lock contention counters are only collected with WithLockMetrics
+	if stats, err := hr.LockStats(); err == nil {
+		writeFamily(bw, "lock_acquisitions", "counter", "Ring lock acquisitions by mode.")
+		fmt.Fprintf(bw, "%s_lock_acquisitions_total{mode=\"read\"} %d\n", metricsPrefix, stats.ReadAcquisitions)
+		fmt.Fprintf(bw, "%s_lock_acquisitions_total{mode=\"write\"} %d\n", metricsPrefix, stats.WriteAcquisitions)
+
+		writeFamily(bw, "lock_contended", "counter", "Ring lock acquisitions that had to wait, by mode.")
+		fmt.Fprintf(bw, "%s_lock_contended_total{mode=\"read\"} %d\n", metricsPrefix, stats.ReadContended)
+		fmt.Fprintf(bw, "%s_lock_contended_total{mode=\"write\"} %d\n", metricsPrefix, stats.WriteContended)
+
+		writeFamily(bw, "lock_wait_seconds", "counter", "Time spent waiting for the ring lock, by mode.")
+		fmt.Fprintf(bw, "%s_lock_wait_seconds_total{mode=\"read\"} %g\n", metricsPrefix, stats.ReadWait.Seconds())
+		fmt.Fprintf(bw, "%s_lock_wait_seconds_total{mode=\"write\"} %g\n", metricsPrefix, stats.WriteWait.Seconds())
+	}
+
+	return bw.Flush()
+}
+
+// writeFamily writes the HELP and TYPE lines of a metric family
+func writeFamily(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s_%s %s\n", metricsPrefix, name, help)
+	fmt.Fprintf(w, "# TYPE %s_%s %s\n", metricsPrefix, name, metricType)
+}
+
+// infoLabels formats a node's class and Meta labels as extra node_info
+// labels, each starting with a comma. Meta keys become meta_<key>, with
+// characters label names can't hold replaced by underscores; of keys that
+// clash after replacement, the first in sorted order wins.
+func infoLabels(r NodeReport) string {
+	var b strings.Builder
+	if r.Class != "" {
+		fmt.Fprintf(&b, ",class=\"%s\"", labelEscaper.Replace(r.Class))
+	}
+	seen := make(map[string]bool, len(r.Meta))
+	for _, k := range sortedMetaKeys(r.Meta) {
+		name := "meta_" + strings.Map(func(c rune) rune {
+			if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+				return c
+			}
+			return '_'
+		}, k)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		fmt.Fprintf(&b, ",%s=\"%s\"", name, labelEscaper.Replace(r.Meta[k]))
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	ring, err := NewHashRing(10, WithCapacityClasses(map[string]int{"large": 2}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080, Zone: "us-east-1a"})
+	ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 8082, Class: "large",
+		Meta: map[string]string{"disk": "ssd", "k8s.io/rack": `r"1`}})
+	ring.AddNode(&Node{ID: `odd"id`, Host: "localhost", Port: 8081, Weight: 3})
+	ring.SetNodeState(`odd"id`, NodeDraining)
+
+	var buf bytes.Buffer
+	if err := ring.WriteMetrics(&buf); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"# TYPE consistent_hash_physical_nodes gauge\n",
+		"consistent_hash_physical_nodes 3\n",
+		"consistent_hash_virtual_nodes 60\n",
+		"consistent_hash_node_info{node=\"node1\",zone=\"us-east-1a\",state=\"active\"} 1\n",
+		"consistent_hash_node_info{node=\"odd\\\"id\",zone=\"\",state=\"draining\"} 1\n",
+		"consistent_hash_node_info{node=\"node3\",zone=\"\",state=\"active\",class=\"large\",meta_disk=\"ssd\",meta_k8s_io_rack=\"r\\\"1\"} 1\n",
+		"consistent_hash_node_weight{node=\"node1\"} 1\n",
+		"consistent_hash_node_virtual_nodes{node=\"odd\\\"id\"} 30\n",
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected metrics output to contain %q", line)
+		}
+	}
+
+	// Lookup counters are only emitted when sampling is enabled
+	if strings.Contains(output, "node_lookups") {
+		t.Error("Expected no lookup counters without key sampling")
+	}
+}
+
+func TestWriteMetricsWithSampling(t *testing.T) {
+	ring, err := NewHashRing(10, WithKeySampling(4))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.GetNode("key1")
+
+	var buf bytes.Buffer
+	if err := ring.WriteMetrics(&buf); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "consistent_hash_node_lookups_total{node=\"node1\"} 1\n") {
+		t.Errorf("Expected lookup counter in output, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,84 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Mover copies a key's data between nodes during a migration. Moves should be
+// idempotent so an interrupted migration can simply be run again.
+type Mover interface {
+	Move(ctx context.Context, key string, from, to *Node) error
+}
+
+// MoverFunc adapts a function to the Mover interface
+type MoverFunc func(ctx context.Context, key string, from, to *Node) error
+
+// Move calls f
+func (f MoverFunc) Move(ctx context.Context, key string, from, to *Node) error {
+	return f(ctx, key, from, to)
+}
+
+// KeyMove is a key whose owner differs between two rings
+type KeyMove struct {
+	Key  string
+	From *Node // Owner on the ring being migrated from
+	To   *Node // Owner on the ring being migrated to
+}
+
+// PlanMigration returns the keys whose owner differs between two rings with
+// the same membership but different hash functions or replica settings, in
+// the order given. Owners are compared by node ID. Rings using different hash
+// functions place keys in unrelated parts of the hash space, so the plan is
+// built from keys rather than ranges. Empty keys are skipped.
+func PlanMigration(from, to *HashRing, keys []string) ([]KeyMove, error) {
+	if from == nil || to == nil {
+		return nil, errors.New("rings cannot be nil")
+	}
+
+	var moves []KeyMove
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		prev, err := from.GetNode(key)
+		if err != nil {
+			return nil, err
+		}
+		next, err := to.GetNode(key)
+		if err != nil {
+			return nil, err
+		}
+		if prev.ID != next.ID {
+			moves = append(moves, KeyMove{Key: key, From: prev, To: next})
+		}
+	}
+
+	return moves, nil
+}
+
+// Migrate plans the migration of keys from one ring to another and drives
+// mover through the moves in order. It stops at the first failed move or
+// when ctx is done, returning the number of keys moved so far.
+func Migrate(ctx context.Context, from, to *HashRing, keys []string, mover Mover) (int, error) {
+	if mover == nil {
+		return 0, errors.New("mover cannot be nil")
+	}
+
+	moves, err := PlanMigration(from, to, keys)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, move := range moves {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if err := mover.Move(ctx, move.Key, move.From, move.To); err != nil {
+			return i, fmt.Errorf("failed to move %s from %s to %s: %w", move.Key, move.From.ID, move.To.ID, err)
+		}
+	}
+
+	return len(moves), nil
+}
@@ -0,0 +1,90 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPlanMigration(t *testing.T) {
+	old, new := newTransitionRings(t)
+
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+
+	moves, err := PlanMigration(old, new, append(keys, ""))
+	if err != nil {
+		t.Fatalf("Failed to plan migration: %v", err)
+	}
+	if len(moves) == 0 || len(moves) == len(keys) {
+		t.Fatalf("Expected some but not all keys to move, got %d of %d", len(moves), len(keys))
+	}
+
+	planned := make(map[string]KeyMove, len(moves))
+	for _, move := range moves {
+		planned[move.Key] = move
+	}
+	for _, key := range keys {
+		prev, _ := old.GetNode(key)
+		next, _ := new.GetNode(key)
+		move, ok := planned[key]
+		if ok != (prev.ID != next.ID) {
+			t.Errorf("Key %q: planned %v, owners %s and %s", key, ok, prev.ID, next.ID)
+		}
+		if ok && (move.From != prev || move.To != next) {
+			t.Errorf("Key %q: expected %s -> %s, got %s -> %s", key, prev.ID, next.ID, move.From.ID, move.To.ID)
+		}
+	}
+
+	// Identical rings need no moves
+	if moves, _ := PlanMigration(old, old, keys); len(moves) != 0 {
+		t.Errorf("Expected no moves between identical rings, got %d", len(moves))
+	}
+
+	empty, _ := NewHashRing(10)
+	if _, err := PlanMigration(old, empty, keys); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	old, new := newTransitionRings(t)
+	keys := make([]string, 200)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+	moves, _ := PlanMigration(old, new, keys)
+
+	var moved []string
+	mover := MoverFunc(func(ctx context.Context, key string, from, to *Node) error {
+		moved = append(moved, key)
+		return nil
+	})
+	n, err := Migrate(context.Background(), old, new, keys, mover)
+	if err != nil || n != len(moves) || len(moved) != len(moves) {
+		t.Fatalf("Expected %d moves, got %d (%v)", len(moves), n, err)
+	}
+
+	// Migration stops at the first failure
+	failure := errors.New("copy failed")
+	calls := 0
+	n, err = Migrate(context.Background(), old, new, keys, MoverFunc(func(ctx context.Context, key string, from, to *Node) error {
+		calls++
+		if calls == 3 {
+			return failure
+		}
+		return nil
+	}))
+	if !errors.Is(err, failure) || n != 2 {
+		t.Errorf("Expected to stop after 2 moves with the mover's error, got %d (%v)", n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if n, err := Migrate(ctx, old, new, keys, mover); !errors.Is(err, context.Canceled) || n != 0 {
+		t.Errorf("Expected context.Canceled before any move, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,177 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// MovedRange is a hash range whose keys would change owner
+type MovedRange struct {
+	Range HashRange
+	From  string // Current owner
+	To    string // Owner after the change
+}
+
+// MovementReport previews the effect of a membership change
+type MovementReport struct {
+	Ranges        []MovedRange       // Ranges changing owner, clockwise from zero
+	MovedFraction float64            // Share of the hash space changing owner
+	Gained        map[string]float64 // Share of the hash space each node takes over
+	Lost          map[string]float64 // Share of the hash space each node hands off
+}
+
+// EstimateMovement reports which hash ranges would change owner, and how much
+// of the keyspace would move, if add were added and remove removed, without
+// changing the ring. It lets operators preview a scale-out or decommission
+// before committing it.
+func (hr *HashRing) EstimateMovement(add []*Node, remove []string) (MovementReport, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	placed, _, err := hr.placeChangeLocked(add, remove)
+	if err != nil {
+		return MovementReport{}, err
+	}
+
+	report := MovementReport{
+		Gained: make(map[string]float64),
+		Lost:   make(map[string]float64),
+	}
+	for _, r := range divergentRanges(hr.virtualNodes, placed) {
+		fraction := r.Range.Fraction()
+		report.Ranges = append(report.Ranges, MovedRange{Range: r.Range, From: r.Local, To: r.Remote})
+		report.MovedFraction += fraction
+		if r.Local != "" {
+			report.Lost[r.Local] += fraction
+		}
+		if r.Remote != "" {
+			report.Gained[r.Remote] += fraction
+		}
+	}
+	return report, nil
+}
+
+// ReplicaChurnReport measures how a membership change affects the replica
+// sets of a key sample
+type ReplicaChurnReport struct {
+	Keys           int     // Keys sampled
+	PrimaryChanged int     // Keys whose primary changes
+	SetsChanged    int     // Keys whose replica set gains or loses a member
+	ReplicasMoved  int     // Replicas, over all keys, placed on a node that didn't hold them
+	MovedFraction  float64 // ReplicasMoved over the number of replicas after the change
+}
+
+// EstimateReplicaChurn reports, for a sample of keys, how many of their full
+// replica sets of replicas nodes would change if add were added and remove
+// removed, without changing the ring. Replication-aware systems copy data for
+// every replica that moves, not only for primaries, so this is the
+// rebalancing cost EstimateMovement leaves out. Replica order within a set
+// doesn't count as a change; only primaries are compared by position.
+func (hr *HashRing) EstimateReplicaChurn(keys []string, replicas int, add []*Node, remove []string) (ReplicaChurnReport, error) {
+	if keys == nil {
+		return ReplicaChurnReport{}, errors.New("keys slice cannot be nil")
+	}
+	if replicas <= 0 {
+		return ReplicaChurnReport{}, ErrInvalidCount
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	placed, members, err := hr.placeChangeLocked(add, remove)
+	if err != nil {
+		return ReplicaChurnReport{}, err
+	}
+
+	report := ReplicaChurnReport{Keys: len(keys)}
+	var before, after []*Node
+	total := 0
+	for _, key := range keys {
+		if hr.skipsEmptyKey(key) {
+			continue
+		}
+		if key == "" {
+			if _, handled, err := hr.routeEmptyKey(); handled {
+				if err != nil {
+					return ReplicaChurnReport{}, err
+				}
+				continue
+			}
+		}
+
+		hash := hr.keyHashLocked(key)
+		before, after = before[:0], after[:0]
+		if len(hr.virtualNodes) > 0 {
+			before = appendReplicas(before, hr.virtualNodes, len(hr.nodes), hash, replicas)
+		}
+		if len(placed) > 0 {
+			after = appendReplicas(after, placed, members, hash, replicas)
+		}
+		total += len(after)
+
+		if primaryID(before) != primaryID(after) {
+			report.PrimaryChanged++
+		}
+		moved := 0
+		for _, node := range after {
+			if !slices.ContainsFunc(before, func(n *Node) bool { return n.ID == node.ID }) {
+				moved++
+			}
+		}
+		report.ReplicasMoved += moved
+		if moved > 0 || len(before) != len(after) {
+			report.SetsChanged++
+		}
+	}
+	if total > 0 {
+		report.MovedFraction = float64(report.ReplicasMoved) / float64(total)
+	}
+	return report, nil
+}
+
+// primaryID returns the ID of the first node of a replica set, or "" for an
+// empty one
+func primaryID(nodes []*Node) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+	return nodes[0].ID
+}
+
+// placeChangeLocked returns the sorted virtual nodes the ring would have with
+// add added and remove removed, and how many members it would have, without
+// changing the ring
+func (hr *HashRing) placeChangeLocked(add []*Node, remove []string) ([]VirtualNode, int, error) {
+	removed := make(map[string]bool, len(remove))
+	for _, id := range remove {
+		if _, exists := hr.nodes[id]; !exists {
+			return nil, 0, fmt.Errorf("%w: %s", ErrNodeNotFound, id)
+		}
+		removed[id] = true
+	}
+
+	members := make([]*Node, 0, len(hr.nodes)+len(add))
+	for _, node := range hr.sortedViewLocked() {
+		if !removed[node.ID] {
+			members = append(members, node)
+		}
+	}
+	added := make(map[string]bool, len(add))
+	for _, node := range add {
+		if node == nil {
+			return nil, 0, errors.New("node cannot be nil")
+		}
+		if _, exists := hr.nodes[node.ID]; (exists && !removed[node.ID]) || added[node.ID] {
+			return nil, 0, fmt.Errorf("duplicate node ID %s", node.ID)
+		}
+		added[node.ID] = true
+		members = append(members, node)
+	}
+
+	placed, err := hr.placeLocked(members)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid node: %w", err)
+	}
+	return placed, len(members), nil
+}
@@ -0,0 +1,159 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEstimateMovement(t *testing.T) {
+	hr, err := NewHashRing(50, WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	generation := hr.Generation()
+
+	add := []*Node{{ID: "node4", Host: "localhost", Port: 8084}}
+	report, err := hr.EstimateMovement(add, []string{"node0"})
+	if err != nil {
+		t.Fatalf("Failed to estimate movement: %v", err)
+	}
+	if hr.Generation() != generation || hr.HasNode("node4") || !hr.HasNode("node0") {
+		t.Fatal("Expected the ring to be unchanged")
+	}
+
+	// node0 hands everything off and node4 only takes over
+	if _, ok := report.Gained["node0"]; ok || report.Lost["node0"] == 0 {
+		t.Errorf("Expected node0 to only lose ranges, got %v and %v", report.Gained, report.Lost)
+	}
+	if _, ok := report.Lost["node4"]; ok || report.Gained["node4"] == 0 {
+		t.Errorf("Expected node4 to only gain ranges, got %v and %v", report.Gained, report.Lost)
+	}
+	var gained, lost float64
+	for _, fraction := range report.Gained {
+		gained += fraction
+	}
+	for _, fraction := range report.Lost {
+		lost += fraction
+	}
+	if math.Abs(gained-report.MovedFraction) > 1e-9 || math.Abs(lost-report.MovedFraction) > 1e-9 {
+		t.Errorf("Expected gains and losses to add up to the moved fraction %.4f, got %.4f and %.4f",
+			report.MovedFraction, gained, lost)
+	}
+
+	// The estimate matches what the change actually does
+	before := make(map[string]string)
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		node, _ := hr.GetNode(key)
+		before[key] = node.ID
+	}
+	hr.RemoveNode("node0")
+	hr.AddNode(add[0])
+	for key, owner := range before {
+		node, _ := hr.GetNode(key)
+		if node.ID == owner {
+			continue
+		}
+		hash := hr.hash(key)
+		found := false
+		for _, r := range report.Ranges {
+			if r.Range.Contains(hash) {
+				found = r.From == owner && r.To == node.ID
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("Key %s moved from %s to %s outside the estimated ranges", key, owner, node.ID)
+		}
+	}
+
+	if _, err := hr.EstimateMovement(nil, []string{"missing"}); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if _, err := hr.EstimateMovement([]*Node{{ID: "node1", Host: "localhost", Port: 1}}, nil); err == nil {
+		t.Error("Expected adding an existing member to be rejected")
+	}
+
+	// Removing every node moves the whole keyspace to no one
+	report, err = hr.EstimateMovement(nil, []string{"node1", "node2", "node3", "node4"})
+	if err != nil || report.MovedFraction != 1 || len(report.Ranges) != 1 || report.Ranges[0].To != "" {
+		t.Errorf("Expected the whole keyspace to move, got %+v, %v", report, err)
+	}
+}
+
+func TestEstimateReplicaChurn(t *testing.T) {
+	hr, err := NewHashRing(50, WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	keys := make([]string, 2000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+
+	add := []*Node{{ID: "node5", Host: "localhost", Port: 8085}}
+	report, err := hr.EstimateReplicaChurn(keys, 3, add, nil)
+	if err != nil {
+		t.Fatalf("Failed to estimate replica churn: %v", err)
+	}
+	if hr.HasNode("node5") {
+		t.Fatal("Expected the ring to be unchanged")
+	}
+
+	// The estimate matches what the change actually does
+	before := make(map[string][]string, len(keys))
+	for _, key := range keys {
+		nodes, _ := hr.GetNodes(key, 3)
+		for _, node := range nodes {
+			before[key] = append(before[key], node.ID)
+		}
+	}
+	hr.AddNode(add[0])
+	var primaries, sets, moved int
+	for _, key := range keys {
+		nodes, _ := hr.GetNodes(key, 3)
+		if nodes[0].ID != before[key][0] {
+			primaries++
+		}
+		changed := 0
+		for _, node := range nodes {
+			if !slices.Contains(before[key], node.ID) {
+				changed++
+			}
+		}
+		moved += changed
+		if changed > 0 {
+			sets++
+		}
+	}
+	if report.Keys != len(keys) || report.PrimaryChanged != primaries || report.SetsChanged != sets || report.ReplicasMoved != moved {
+		t.Errorf("Expected %d primaries, %d sets and %d replicas to change, got %+v", primaries, sets, moved, report)
+	}
+
+	// A new node joins more replica sets than it takes primaries
+	if report.SetsChanged <= report.PrimaryChanged {
+		t.Errorf("Expected more replica sets than primaries to change, got %+v", report)
+	}
+	if expected := float64(moved) / float64(3*len(keys)); math.Abs(report.MovedFraction-expected) > 1e-9 {
+		t.Errorf("Expected moved fraction %f, got %f", expected, report.MovedFraction)
+	}
+
+	if _, err := hr.EstimateReplicaChurn(keys, 0, nil, nil); err != ErrInvalidCount {
+		t.Errorf("Expected ErrInvalidCount, got %v", err)
+	}
+	if _, err := hr.EstimateReplicaChurn(keys, 3, nil, []string{"missing"}); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if report, _ := hr.EstimateReplicaChurn(keys, 3, nil, nil); report.SetsChanged != 0 || report.MovedFraction != 0 {
+		t.Errorf("Expected no churn without a change, got %+v", report)
+	}
+}
@@ -0,0 +1,123 @@
+package consistenthashing
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// hashSpace is the size of the 64-bit hash space as a float, used to turn arc
+// lengths into fractions of the ring
+const hashSpace = 1 << 64
+
+// Ownership returns each node's exact share of the 64-bit hash space,
+// computed from the arcs between virtual nodes rather than from sample keys.
+// The shares sum to 1, and an empty ring returns an empty map.
+func (hr *HashRing) Ownership() map[string]float64 {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.ownershipLocked()
+}
+
+// ownershipLocked returns each node's share of the hash space, computed from the
+// ranges between consecutive virtual nodes. Callers must hold hr.mu.
+func (hr *HashRing) ownershipLocked() map[string]float64 {
+	shares := make(map[string]float64, len(hr.nodes))
+	for id := range hr.nodes {
+		shares[id] = 0
+	}
+
+	for _, owner := range hr.rangesLocked() {
+		shares[owner.Node.ID] += owner.Range.Fraction()
+	}
+
+	return shares
+}
+
+// EstimateKeys estimates how many of totalKeys uniformly hashed keys fall into
+// each node's owned ranges, based on the node's exact share of the hash space.
+// The estimates always sum to totalKeys, which makes them suitable for sizing
+// migration jobs before capacity is added.
+func (hr *HashRing) EstimateKeys(totalKeys int) (map[string]int, error) {
+	if totalKeys < 0 {
+		return nil, ErrInvalidCount
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	return apportion(hr.ownershipLocked(), totalKeys), nil
+}
+
+// EstimateKeysFromSample scales the distribution of a key sample up to
+// totalKeys. Unlike EstimateKeys it reflects the real key population, so it
+// captures skew from non-uniform keys. Empty keys in the sample are skipped.
+func (hr *HashRing) EstimateKeysFromSample(sample []string, totalKeys int) (map[string]int, error) {
+	if sample == nil {
+		return nil, errors.New("sample cannot be nil")
+	}
+	if totalKeys < 0 {
+		return nil, ErrInvalidCount
+	}
+
+	distribution, err := hr.GetLoadDistribution(sample)
+	if err != nil {
+		return nil, err
+	}
+
+	sampled := 0
+	for _, count := range distribution {
+		sampled += count
+	}
+	if sampled == 0 {
+		return nil, errors.New("sample contains no usable keys")
+	}
+
+	shares := make(map[string]float64, len(distribution))
+	for _, node := range hr.GetAllNodes() {
+		shares[node.ID] = float64(distribution[node.ID]) / float64(sampled)
+	}
+
+	return apportion(shares, totalKeys), nil
+}
+
+// apportion splits total across the given shares using the largest remainder
+// method so the integer results add up to exactly total
+func apportion(shares map[string]float64, total int) map[string]int {
+	type remainder struct {
+		id   string
+		frac float64
+	}
+
+	result := make(map[string]int, len(shares))
+	remainders := make([]remainder, 0, len(shares))
+	assigned := 0
+
+	for id, share := range shares {
+		exact := share * float64(total)
+		whole := math.Floor(exact)
+		result[id] = int(whole)
+		assigned += int(whole)
+		remainders = append(remainders, remainder{id: id, frac: exact - whole})
+	}
+
+	// Hand out what is left to the largest remainders, breaking ties by ID
+	// so the result is deterministic
+	sort.Slice(remainders, func(i, j int) bool {
+		if remainders[i].frac != remainders[j].frac {
+			return remainders[i].frac > remainders[j].frac
+		}
+		return remainders[i].id < remainders[j].id
+	})
+	for i := 0; assigned < total && len(remainders) > 0; i++ {
+		result[remainders[i%len(remainders)].id]++
+		assigned++
+	}
+
+	return result
+}
@@ -0,0 +1,111 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestOwnershipSumsToOne(t *testing.T) {
+	ring, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if shares := ring.Ownership(); len(shares) != 0 {
+		t.Fatalf("Expected no shares for an empty ring, got %v", shares)
+	}
+
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	shares := ring.Ownership()
+	if len(shares) != 4 {
+		t.Fatalf("Expected shares for 4 nodes, got %d", len(shares))
+	}
+
+	total := 0.0
+	for _, share := range shares {
+		total += share
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected shares to sum to 1, got %f", total)
+	}
+}
+
+func TestEstimateKeys(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	// Empty ring
+	if _, err := ring.EstimateKeys(100); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+
+	// Single node owns everything
+	estimates, err := ring.EstimateKeys(1000)
+	if err != nil {
+		t.Fatalf("Failed to estimate keys: %v", err)
+	}
+	if estimates["node1"] != 1000 {
+		t.Errorf("Expected node1 to own 1000 keys, got %d", estimates["node1"])
+	}
+
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+	ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 8082})
+
+	estimates, err = ring.EstimateKeys(1001)
+	if err != nil {
+		t.Fatalf("Failed to estimate keys: %v", err)
+	}
+	total := 0
+	for _, count := range estimates {
+		total += count
+	}
+	if total != 1001 {
+		t.Errorf("Expected estimates to sum to 1001, got %d", total)
+	}
+
+	if _, err := ring.EstimateKeys(-1); err != ErrInvalidCount {
+		t.Errorf("Expected ErrInvalidCount, got %v", err)
+	}
+}
+
+func TestEstimateKeysFromSample(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+
+	sample := make([]string, 100)
+	for i := range sample {
+		sample[i] = fmt.Sprintf("key_%d", i)
+	}
+
+	estimates, err := ring.EstimateKeysFromSample(sample, 10000)
+	if err != nil {
+		t.Fatalf("Failed to estimate keys: %v", err)
+	}
+
+	distribution, _ := ring.GetLoadDistribution(sample)
+	for nodeID, count := range distribution {
+		if estimates[nodeID] != count*100 {
+			t.Errorf("Expected %s estimate %d, got %d", nodeID, count*100, estimates[nodeID])
+		}
+	}
+
+	if _, err := ring.EstimateKeysFromSample(nil, 10); err == nil {
+		t.Error("Expected error for nil sample")
+	}
+	if _, err := ring.EstimateKeysFromSample([]string{""}, 10); err == nil {
+		t.Error("Expected error for sample without usable keys")
+	}
+}
@@ -0,0 +1,123 @@
+// Package partitioner spreads scheduled jobs across a fleet with a consistent
+// hash ring. Every process computes the same assignment from the same ring, so
+// each job has exactly one owner without any coordination, and membership
+// changes only move the jobs of the nodes involved.
+package partitioner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// Assignments maps each node to the jobs it owns at a ring generation
+type Assignments struct {
+	Generation uint64
+	ByNode     map[string][]string // Job IDs per node ID, sorted
+}
+
+// JobsFor returns the jobs assigned to a node
+func (a Assignments) JobsFor(nodeID string) []string {
+	return a.ByNode[nodeID]
+}
+
+// Partitioner assigns job IDs to the nodes of a ring
+type Partitioner struct {
+	ring *consistenthashing.HashRing
+
+	mu      sync.Mutex
+	jobs    []string
+	changed chan struct{} // Signals Run that the job list changed
+}
+
+// New creates a partitioner for a job list
+func New(ring *consistenthashing.HashRing, jobs []string) (*Partitioner, error) {
+	if ring == nil {
+		return nil, errors.New("ring cannot be nil")
+	}
+
+	p := &Partitioner{ring: ring, changed: make(chan struct{}, 1)}
+	if err := p.SetJobs(jobs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetJobs replaces the job list. Duplicate IDs are ignored.
+func (p *Partitioner) SetJobs(jobs []string) error {
+	for _, job := range jobs {
+		if job == "" {
+			return errors.New("job ID cannot be empty")
+		}
+	}
+
+	sorted := slices.Clone(jobs)
+	sort.Strings(sorted)
+	sorted = slices.Compact(sorted)
+
+	p.mu.Lock()
+	p.jobs = sorted
+	p.mu.Unlock()
+
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Owner returns the ID of the node owning a job
+func (p *Partitioner) Owner(job string) (string, error) {
+	return p.ring.GetOwnerID(job)
+}
+
+// Assign computes the current assignment of every job
+func (p *Partitioner) Assign() (Assignments, error) {
+	p.mu.Lock()
+	jobs := p.jobs
+	p.mu.Unlock()
+
+	assignments := Assignments{
+		Generation: p.ring.Generation(),
+		ByNode:     make(map[string][]string),
+	}
+	for _, job := range jobs {
+		owner, err := p.ring.GetOwnerID(job)
+		if err != nil {
+			return Assignments{}, err
+		}
+		assignments.ByNode[owner] = append(assignments.ByNode[owner], job)
+	}
+	return assignments, nil
+}
+
+// Run publishes the assignment immediately and again after every membership
+// change or job list change, until ctx is done. Assignments that fail, such
+// as on an empty ring, are skipped until the next change.
+func (p *Partitioner) Run(ctx context.Context, publish func(Assignments)) {
+	events, unsubscribe := p.ring.Watch(1)
+	defer unsubscribe()
+
+	// The first assignment covers any job change made before Run
+	select {
+	case <-p.changed:
+	default:
+	}
+
+	for {
+		if assignments, err := p.Assign(); err == nil {
+			publish(assignments)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-events:
+		case <-p.changed:
+		}
+	}
+}
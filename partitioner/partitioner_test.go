@@ -0,0 +1,97 @@
+package partitioner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func newTestRing(t *testing.T, nodes int) *consistenthashing.HashRing {
+	t.Helper()
+	ring, err := consistenthashing.NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < nodes; i++ {
+		ring.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	return ring
+}
+
+func jobIDs(n int) []string {
+	jobs := make([]string, n)
+	for i := range jobs {
+		jobs[i] = fmt.Sprintf("job-%d", i)
+	}
+	return jobs
+}
+
+func TestAssign(t *testing.T) {
+	ring := newTestRing(t, 3)
+	if _, err := New(ring, []string{""}); err == nil {
+		t.Error("Expected error for empty job ID")
+	}
+
+	p, err := New(ring, append(jobIDs(300), "job-1"))
+	if err != nil {
+		t.Fatalf("Failed to create partitioner: %v", err)
+	}
+
+	assignments, err := p.Assign()
+	if err != nil {
+		t.Fatalf("Failed to assign: %v", err)
+	}
+
+	// Every job is assigned exactly once, to its ring owner
+	total := 0
+	for node, jobs := range assignments.ByNode {
+		total += len(jobs)
+		for _, job := range jobs {
+			if owner, _ := p.Owner(job); owner != node {
+				t.Errorf("Job %s assigned to %s, owned by %s", job, node, owner)
+			}
+		}
+		if len(jobs) == 0 {
+			t.Errorf("Expected %s to get jobs", node)
+		}
+	}
+	if total != 300 {
+		t.Errorf("Expected 300 assigned jobs, got %d", total)
+	}
+	if assignments.Generation != ring.Generation() {
+		t.Errorf("Expected generation %d, got %d", ring.Generation(), assignments.Generation)
+	}
+}
+
+func TestRunRepublishes(t *testing.T) {
+	ring := newTestRing(t, 2)
+	p, _ := New(ring, jobIDs(50))
+
+	published := make(chan Assignments, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx, func(a Assignments) { published <- a })
+		close(done)
+	}()
+
+	initial := <-published
+	if len(initial.ByNode) != 2 {
+		t.Errorf("Expected jobs on 2 nodes, got %d", len(initial.ByNode))
+	}
+
+	ring.AddNode(&consistenthashing.Node{ID: "node2", Host: "localhost", Port: 9000})
+	if next := <-published; len(next.JobsFor("node2")) == 0 || next.Generation <= initial.Generation {
+		t.Errorf("Expected node2 to get jobs at a newer generation, got %+v", next)
+	}
+
+	p.SetJobs([]string{"only"})
+	if next := <-published; len(next.ByNode) != 1 {
+		t.Errorf("Expected a single assigned job, got %+v", next)
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,66 @@
+package consistenthashing
+
+import "errors"
+
+// PlacementPolicy describes the durability requirements of a replica set
+type PlacementPolicy struct {
+	Replicas int // Number of replicas per key
+	MinZones int // Minimum number of distinct zones per replica set
+	MinHosts int // Minimum number of distinct hosts per replica set
+}
+
+// PlacementViolation reports a key whose replica set does not meet the policy
+type PlacementViolation struct {
+	Key   string
+	Nodes []*Node
+	Zones int // Distinct zones in the replica set
+	Hosts int // Distinct hosts in the replica set
+}
+
+// ValidatePlacement checks that the replica set of every sampled key spans at
+// least MinZones zones and MinHosts hosts, and returns the keys that do not.
+// Nodes without a zone do not count towards MinZones. Empty keys are skipped.
+func (hr *HashRing) ValidatePlacement(keys []string, policy PlacementPolicy) ([]PlacementViolation, error) {
+	if keys == nil {
+		return nil, errors.New("keys slice cannot be nil")
+	}
+	if policy.Replicas <= 0 {
+		return nil, ErrInvalidCount
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	var violations []PlacementViolation
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		nodes := hr.collectNodesLocked(hr.keyHashLocked(key), policy.Replicas)
+
+		zones := make(map[string]struct{}, len(nodes))
+		hosts := make(map[string]struct{}, len(nodes))
+		for _, node := range nodes {
+			if node.Zone != "" {
+				zones[node.Zone] = struct{}{}
+			}
+			hosts[node.Host] = struct{}{}
+		}
+
+		if len(zones) < policy.MinZones || len(hosts) < policy.MinHosts {
+			violations = append(violations, PlacementViolation{
+				Key:   key,
+				Nodes: nodes,
+				Zones: len(zones),
+				Hosts: len(hosts),
+			})
+		}
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,55 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValidatePlacement(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	// Empty ring
+	if _, err := ring.ValidatePlacement([]string{"key"}, PlacementPolicy{Replicas: 2}); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	// Two zones, but node1 and node2 share a host
+	ring.AddNode(&Node{ID: "node1", Host: "host-a", Port: 8080, Zone: "zone-a"})
+	ring.AddNode(&Node{ID: "node2", Host: "host-a", Port: 8081, Zone: "zone-b"})
+	ring.AddNode(&Node{ID: "node3", Host: "host-b", Port: 8080, Zone: "zone-b"})
+
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key_%d", i)
+	}
+
+	// Three replicas always cover every zone and host
+	violations, err := ring.ValidatePlacement(keys, PlacementPolicy{Replicas: 3, MinZones: 2, MinHosts: 2})
+	if err != nil {
+		t.Fatalf("Failed to validate placement: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Expected no violations with 3 replicas, got %d", len(violations))
+	}
+
+	// Two replicas cannot always span two hosts and two zones
+	violations, err = ring.ValidatePlacement(keys, PlacementPolicy{Replicas: 2, MinZones: 2, MinHosts: 2})
+	if err != nil {
+		t.Fatalf("Failed to validate placement: %v", err)
+	}
+	if len(violations) == 0 {
+		t.Error("Expected violations with 2 replicas")
+	}
+	for _, v := range violations {
+		if v.Zones >= 2 && v.Hosts >= 2 {
+			t.Errorf("Key %s reported as violation but spans %d zones and %d hosts", v.Key, v.Zones, v.Hosts)
+		}
+	}
+
+	if _, err := ring.ValidatePlacement(keys, PlacementPolicy{}); err != ErrInvalidCount {
+		t.Errorf("Expected ErrInvalidCount, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package consistenthashing
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Proximity scores how far a node is from the caller. Lower scores are nearer.
+type Proximity func(node *Node) float64
+
+// WithProximity orders GetNodes results by the given proximity functions, so
+// callers try the nearest replica first. Functions are applied in order, with
+// later ones only breaking ties of earlier ones; remaining ties keep ring order.
+func WithProximity(ps ...Proximity) Option {
+	return func(hr *HashRing) {
+		hr.proximity = ps
+	}
+}
+
+// SameZone ranks nodes in the given zone ahead of all others
+func SameZone(zone string) Proximity {
+	return func(node *Node) float64 {
+		if node.Zone == zone {
+			return 0
+		}
+		return 1
+	}
+}
+
+// LatencyProximity ranks nodes by observed latency as reported by the latency
+// function. Nodes without an observation should be reported with a large value.
+func LatencyProximity(latency func(nodeID string) time.Duration) Proximity {
+	return func(node *Node) float64 {
+		return float64(latency(node.ID))
+	}
+}
+
+// SortByProximity stably sorts nodes from nearest to farthest
+func SortByProximity(nodes []*Node, ps ...Proximity) {
+	if len(ps) == 0 || len(nodes) < 2 {
+		return
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		for _, p := range ps {
+			di, dj := p(nodes[i]), p(nodes[j])
+			if di != dj {
+				return di < dj
+			}
+		}
+		return false
+	})
+}
+
+// WithWeightedReplicaOrder orders each GetNodes replica set so heavier nodes
+// are more likely to come first: a replica is first with probability
+// proportional to its weight among the set. The order is a deterministic
+// function of the key, and proximity, if configured, still takes precedence.
+// GetNode keeps returning the ring owner, which may differ from the first
+// replica.
+func WithWeightedReplicaOrder() Option {
+	return func(hr *HashRing) {
+		hr.weightedOrder = true
+	}
+}
+
+// sortByWeightLocked orders replicas by weighted rendezvous scores, the
+// exponential race -ln(u)/w where u is uniform per key and node. The lowest
+// score wins with probability w/sum(w). mix64 spreads the hash over all 64
+// bits, so u stays uniform even for hashers with narrow positions, such as
+// digests read with KetamaDigestMapping.
+func (hr *HashRing) sortByWeightLocked(nodes []*Node, key string) {
+	if len(nodes) < 2 {
+		return
+	}
+
+	scores := make([]float64, len(nodes))
+	var buf []byte
+	for i, node := range nodes {
+		buf = append(append(append(buf[:0], key...), 0), node.ID...)
+		u := (float64(mix64(hr.hashBytes(buf))>>11) + 0.5) / (1 << 53)
+		scores[i] = -math.Log(u) / float64(hr.weightLocked(node))
+	}
+	sort.Stable(weightedReplicas{nodes: nodes, scores: scores})
+}
+
+// weightedReplicas sorts replicas by ascending score, keeping each score with
+// its node
+type weightedReplicas struct {
+	nodes  []*Node
+	scores []float64
+}
+
+func (w weightedReplicas) Len() int           { return len(w.nodes) }
+func (w weightedReplicas) Less(i, j int) bool { return w.scores[i] < w.scores[j] }
+
+func (w weightedReplicas) Swap(i, j int) {
+	w.nodes[i], w.nodes[j] = w.nodes[j], w.nodes[i]
+	w.scores[i], w.scores[j] = w.scores[j], w.scores[i]
+}
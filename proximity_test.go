@@ -0,0 +1,129 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSortByProximity(t *testing.T) {
+	nodes := []*Node{
+		{ID: "a", Host: "localhost", Port: 8080, Zone: "us-east-1b"},
+		{ID: "b", Host: "localhost", Port: 8081, Zone: "us-east-1a"},
+		{ID: "c", Host: "localhost", Port: 8082, Zone: "us-east-1a"},
+		{ID: "d", Host: "localhost", Port: 8083, Zone: "us-east-1b"},
+	}
+	latencies := map[string]time.Duration{
+		"a": 1 * time.Millisecond,
+		"b": 5 * time.Millisecond,
+		"c": 2 * time.Millisecond,
+		"d": 3 * time.Millisecond,
+	}
+
+	SortByProximity(nodes, SameZone("us-east-1a"), LatencyProximity(func(nodeID string) time.Duration {
+		return latencies[nodeID]
+	}))
+
+	expectedOrder := []string{"c", "b", "a", "d"}
+	for i, node := range nodes {
+		if node.ID != expectedOrder[i] {
+			t.Errorf("Expected node %s at position %d, got %s", expectedOrder[i], i, node.ID)
+		}
+	}
+}
+
+func TestGetNodesWithProximity(t *testing.T) {
+	ring, err := NewHashRing(10, WithProximity(SameZone("zone-b")))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080, Zone: "zone-a"})
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081, Zone: "zone-a"})
+	ring.AddNode(&Node{ID: "node3", Host: "localhost", Port: 8082, Zone: "zone-b"})
+
+	for _, key := range []string{"key1", "key2", "key3", "key4"} {
+		nodes, err := ring.GetNodes(key, 3)
+		if err != nil {
+			t.Fatalf("Failed to get nodes: %v", err)
+		}
+		if nodes[0].ID != "node3" {
+			t.Errorf("Expected same-zone node3 first for %s, got %s", key, nodes[0].ID)
+		}
+	}
+}
+
+func TestWeightedReplicaOrder(t *testing.T) {
+	// Ketama positions are only 32 bits wide, which must not skew the order
+	for _, hasher := range []HashFunction{&SHA256Hasher{}, &MD5Hasher{Mapping: KetamaDigestMapping}} {
+		testWeightedReplicaOrder(t, hasher)
+	}
+}
+
+func testWeightedReplicaOrder(t *testing.T, hasher HashFunction) {
+	t.Helper()
+	ring, err := NewHashRing(50, WithHashFunction(hasher), WithWeightedReplicaOrder())
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	plain, _ := NewHashRing(50, WithHashFunction(hasher))
+	for _, node := range []*Node{
+		{ID: "light1", Host: "localhost", Port: 8080, Weight: 1},
+		{ID: "light2", Host: "localhost", Port: 8081, Weight: 1},
+		{ID: "heavy", Host: "localhost", Port: 8082, Weight: 4},
+	} {
+		ring.AddNode(node)
+		plain.AddNode(node)
+	}
+
+	const keys = 5000
+	heavyFirst := 0
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("key%d", i)
+		nodes, err := ring.GetNodes(key, 3)
+		if err != nil {
+			t.Fatalf("Failed to get nodes: %v", err)
+		}
+		if nodes[0].ID == "heavy" {
+			heavyFirst++
+		}
+
+		// Only the order changes, and it is stable per key
+		again, _ := ring.GetNodes(key, 3)
+		for j := range nodes {
+			if nodes[j] != again[j] {
+				t.Fatalf("Expected a deterministic order for %s", key)
+			}
+		}
+		if i < 100 {
+			set, _ := plain.GetNodes(key, 2)
+			got, _ := ring.GetNodes(key, 2)
+			if !sameIDs(set, got) {
+				t.Errorf("Expected the same replica set for %s, got %v and %v", key, set, got)
+			}
+		}
+	}
+
+	// The heavy node holds 4 of 6 weight units
+	if share := float64(heavyFirst) / keys; share < 0.63 || share > 0.70 {
+		t.Errorf("%s: expected heavy node first for about 2/3 of keys, got %.3f", hasherName(hasher), share)
+	}
+}
+
+// sameIDs reports whether two node lists hold the same IDs in any order
+func sameIDs(a, b []*Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, node := range a {
+		seen[node.ID]++
+	}
+	for _, node := range b {
+		seen[node.ID]--
+		if seen[node.ID] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package consistenthashing
+
+import (
+	"math/rand"
+	"time"
+)
+
+// WithRandSource sets the source of randomness used by randomized strategies
+// such as key sampling and RandomNode. Passing a seeded source makes their
+// choices reproducible in tests and across restarts. The source is only used
+// under the ring's own locks and must not be shared with other code.
+func WithRandSource(src rand.Source) Option {
+	return func(hr *HashRing) {
+		hr.randSource = src
+	}
+}
+
+// newRand returns a generator over the configured source, or a time-seeded
+// one when none was given
+func (hr *HashRing) newRand() *rand.Rand {
+	if hr.randSource != nil {
+		return rand.New(hr.randSource)
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// lockedRand is the ring's generator, shared by every randomized strategy.
+// A rand.Rand is not safe for concurrent use, and the strategies run under
+// different locks, so it has its own.
+type lockedRand struct {
+	mu  optionalMutex
+	rng *rand.Rand
+}
+
+func (r *lockedRand) Int63() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Int63()
+}
+
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Int63n(n)
+}
+
+// RandomNode returns a member chosen uniformly at random, for work that isn't
+// keyed, such as health pings or sampling nodes for a broadcast
+func (hr *HashRing) RandomNode() (*Node, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	nodes := hr.sortedViewLocked()
+	if len(nodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+	return nodes[hr.random.Int63n(int64(len(nodes)))], nil
+}
+
+// WeightedRandomNode returns a member chosen at random with probability
+// proportional to its effective weight, the weight of its capacity class if
+// it has one
+func (hr *HashRing) WeightedRandomNode() (*Node, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	nodes := hr.sortedViewLocked()
+	if len(nodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	var total int64
+	for _, node := range nodes {
+		total += int64(hr.weightLocked(node))
+	}
+	pick := hr.random.Int63n(total)
+	for _, node := range nodes {
+		if pick -= int64(hr.weightLocked(node)); pick < 0 {
+			return node, nil
+		}
+	}
+	return nodes[len(nodes)-1], nil
+}
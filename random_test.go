@@ -0,0 +1,85 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestWithRandSourceReproducible(t *testing.T) {
+	sample := func(seed int64) map[string][]string {
+		ring, err := NewHashRing(50, WithKeySampling(5), WithRandSource(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+		}
+		for i := 0; i < 1000; i++ {
+			ring.GetNode(fmt.Sprintf("key%d", i))
+		}
+		samples, _ := ring.SampledKeys()
+		return samples
+	}
+
+	first, second := sample(42), sample(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Expected identical samples for the same seed")
+	}
+	if reflect.DeepEqual(first, sample(7)) {
+		t.Error("Expected different samples for a different seed")
+	}
+}
+
+func TestWithRandSourceOptionOrder(t *testing.T) {
+	// The source applies even when given before WithKeySampling
+	ring, err := NewHashRing(10, WithRandSource(rand.NewSource(1)), WithKeySampling(1))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if ring.sampler.rng == nil {
+		t.Fatal("Expected sampler to have a generator")
+	}
+	if got, want := ring.sampler.rng.Int63(), rand.New(rand.NewSource(1)).Int63(); got != want {
+		t.Errorf("Expected the seeded sequence, got %d want %d", got, want)
+	}
+}
+
+func TestRandomNode(t *testing.T) {
+	ring, err := NewHashRing(10, WithRandSource(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if _, err := ring.RandomNode(); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+	if _, err := ring.WeightedRandomNode(); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "light", Host: "localhost", Port: 8080, Weight: 1})
+	ring.AddNode(&Node{ID: "heavy", Host: "localhost", Port: 8081, Weight: 3})
+
+	const picks = 20000
+	uniform := make(map[string]int)
+	weighted := make(map[string]int)
+	for i := 0; i < picks; i++ {
+		node, err := ring.RandomNode()
+		if err != nil {
+			t.Fatalf("Failed to pick node: %v", err)
+		}
+		uniform[node.ID]++
+		if node, err = ring.WeightedRandomNode(); err != nil {
+			t.Fatalf("Failed to pick node: %v", err)
+		}
+		weighted[node.ID]++
+	}
+
+	if share := float64(uniform["heavy"]) / picks; share < 0.47 || share > 0.53 {
+		t.Errorf("Expected heavy to get half the uniform picks, got %.3f", share)
+	}
+	if share := float64(weighted["heavy"]) / picks; share < 0.72 || share > 0.78 {
+		t.Errorf("Expected heavy to get 3/4 of the weighted picks, got %.3f", share)
+	}
+}
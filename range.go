@@ -0,0 +1,117 @@
+package consistenthashing
+
+import "fmt"
+
+// HashRange is an arc of the hash ring covering the hashes in (Start, End],
+// the same half-open interval a virtual node owns. A range with End < Start
+// wraps around zero, and Start == End denotes the whole ring.
+type HashRange struct {
+	Start uint64
+	End   uint64
+}
+
+// Contains reports whether hash falls inside the range
+func (r HashRange) Contains(hash uint64) bool {
+	switch {
+	case r.Start == r.End:
+		return true
+	case r.Start < r.End:
+		return hash > r.Start && hash <= r.End
+	default:
+		return hash > r.Start || hash <= r.End
+	}
+}
+
+// Fraction returns the share of the hash space covered by the range
+func (r HashRange) Fraction() float64 {
+	if r.Start == r.End {
+		return 1
+	}
+	// Unsigned subtraction wraps around, which gives the length of ranges
+	// that cross zero
+	return float64(r.End-r.Start) / hashSpace
+}
+
+// String formats the range in interval notation
+func (r HashRange) String() string {
+	return fmt.Sprintf("(%#016x, %#016x]", r.Start, r.End)
+}
+
+// NodeRange is a range of the hash space together with the node owning it
+type NodeRange struct {
+	Range HashRange
+	Node  *Node
+}
+
+// OwnerOfRange returns the nodes owning the hashes in (start, end], in
+// clockwise order starting at start. Each entry covers a contiguous part of
+// the range; adjacent parts owned by the same node are merged. Passing
+// start == end queries the whole ring.
+func (hr *HashRing) OwnerOfRange(start, end uint64) ([]NodeRange, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	return hr.ownersLocked(start, end), nil
+}
+
+// Ranges partitions the whole ring into contiguous ranges and their owners, in
+// clockwise order
+func (hr *HashRing) Ranges() []NodeRange {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.rangesLocked()
+}
+
+// RangesWithGeneration returns Ranges along with the generation they were
+// read at, both under one lock, so the pair always describes a single ring
+// state
+func (hr *HashRing) RangesWithGeneration() ([]NodeRange, uint64) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	return hr.rangesLocked(), hr.generation
+}
+
+// rangesLocked is Ranges for callers that hold hr.mu
+func (hr *HashRing) rangesLocked() []NodeRange {
+	if len(hr.virtualNodes) == 0 {
+		return nil
+	}
+
+	last := hr.virtualNodes[len(hr.virtualNodes)-1].Hash
+	return hr.ownersLocked(last, last)
+}
+
+// ownersLocked walks (start, end] one owned arc at a time. Callers must hold
+// hr.mu and ensure the ring is not empty.
+func (hr *HashRing) ownersLocked(start, end uint64) []NodeRange {
+	var owners []NodeRange
+	add := func(r HashRange, node *Node) {
+		if n := len(owners); n > 0 && owners[n-1].Node == node {
+			owners[n-1].Range.End = r.End
+			return
+		}
+		owners = append(owners, NodeRange{Range: r, Node: node})
+	}
+
+	for cur := start; ; {
+		// The owner of the next hash is the first virtual node at or after
+		// it; duplicate positions are skipped by search
+		vnode := hr.virtualNodes[hr.search(cur+1)]
+		step := vnode.Hash - cur // 0 when cur is the only position, a full turn away
+		remaining := end - cur   // 0 when a full turn is left
+
+		if step == 0 || (remaining != 0 && step >= remaining) {
+			add(HashRange{Start: cur, End: end}, vnode.Node)
+			return owners
+		}
+
+		add(HashRange{Start: cur, End: vnode.Hash}, vnode.Node)
+		cur = vnode.Hash
+	}
+}
@@ -0,0 +1,182 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestHashRangeContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        HashRange
+		hash     uint64
+		expected bool
+	}{
+		{"start is excluded", HashRange{10, 20}, 10, false},
+		{"end is included", HashRange{10, 20}, 20, true},
+		{"inside", HashRange{10, 20}, 15, true},
+		{"after end", HashRange{10, 20}, 21, false},
+		{"wrapping before zero", HashRange{math.MaxUint64 - 5, 5}, math.MaxUint64, true},
+		{"wrapping at zero", HashRange{math.MaxUint64 - 5, 5}, 0, true},
+		{"wrapping end", HashRange{math.MaxUint64 - 5, 5}, 5, true},
+		{"wrapping outside", HashRange{math.MaxUint64 - 5, 5}, 6, false},
+		{"wrapping start", HashRange{math.MaxUint64 - 5, 5}, math.MaxUint64 - 5, false},
+		{"whole ring", HashRange{7, 7}, 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Contains(tt.hash); got != tt.expected {
+				t.Errorf("%v.Contains(%d) = %v, expected %v", tt.r, tt.hash, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHashRangeFraction(t *testing.T) {
+	if f := (HashRange{5, 5}).Fraction(); f != 1 {
+		t.Errorf("Expected whole ring fraction 1, got %f", f)
+	}
+	if f := (HashRange{0, 1 << 62}).Fraction(); f != 0.25 {
+		t.Errorf("Expected quarter ring fraction 0.25, got %f", f)
+	}
+	if f := (HashRange{math.MaxUint64 - (1<<62 - 1), 1 << 62}).Fraction(); f != 0.5 {
+		t.Errorf("Expected wrapping half ring fraction 0.5, got %f", f)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if ranges := ring.Ranges(); ranges != nil {
+		t.Errorf("Expected no ranges for empty ring, got %v", ranges)
+	}
+	if _, err := ring.OwnerOfRange(0, 1); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "solo", Host: "localhost", Port: 8080})
+	ranges := ring.Ranges()
+	if len(ranges) != 1 || ranges[0].Range.Start != ranges[0].Range.End {
+		t.Fatalf("Expected a single whole-ring range, got %v", ranges)
+	}
+
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 9000 + i})
+	}
+
+	ranges = ring.Ranges()
+	total := 0.0
+	for i, r := range ranges {
+		next := ranges[(i+1)%len(ranges)]
+		if r.Range.End != next.Range.Start {
+			t.Fatalf("Range %v is not followed by %v", r.Range, next.Range)
+		}
+		if r.Node == next.Node {
+			t.Errorf("Expected adjacent ranges of %s to be merged", r.Node.ID)
+		}
+		total += r.Range.Fraction()
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected ranges to cover the ring, got %f", total)
+	}
+
+	// Every hash must be owned by the node GetNode would pick
+	for i := 0; i < 1000; i++ {
+		hash := ring.hash(fmt.Sprintf("key%d", i))
+		expected, _ := ring.getNodeByHash(hash)
+		for _, r := range ranges {
+			if r.Range.Contains(hash) && r.Node != expected {
+				t.Fatalf("Hash %d: range %v owned by %s, expected %s", hash, r.Range, r.Node.ID, expected.ID)
+			}
+		}
+	}
+}
+
+func TestRangesWithGeneration(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "stable", Host: "localhost", Port: 8080})
+	toggled := &Node{ID: "toggled", Host: "localhost", Port: 8081}
+	base := ring.Generation()
+
+	// Every change toggles one node, so a consistent read has the toggled node
+	// exactly at odd generations past the base
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			ring.AddNode(toggled)
+			ring.RemoveNode(toggled.ID)
+		}
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		ranges, generation := ring.RangesWithGeneration()
+		present := false
+		for _, r := range ranges {
+			present = present || r.Node.ID == toggled.ID
+		}
+		if present != ((generation-base)%2 == 1) {
+			t.Fatalf("Ranges at generation %d are from another generation", generation)
+		}
+	}
+}
+
+func TestOwnerOfRange(t *testing.T) {
+	ring, err := NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 9000 + i})
+	}
+
+	queries := []HashRange{
+		{0, 1 << 62},
+		{math.MaxUint64 - 1<<60, 1 << 60},
+		{1 << 40, 1<<40 + 1},
+		{42, 42},
+	}
+	for _, q := range queries {
+		owners, err := ring.OwnerOfRange(q.Start, q.End)
+		if err != nil {
+			t.Fatalf("Failed to get owners of %v: %v", q, err)
+		}
+
+		// The parts must tile the query exactly, in clockwise order
+		if owners[0].Range.Start != q.Start || owners[len(owners)-1].Range.End != q.End {
+			t.Errorf("Owners of %v don't span it: %v", q, owners)
+		}
+		for i := 1; i < len(owners); i++ {
+			if owners[i].Range.Start != owners[i-1].Range.End {
+				t.Errorf("Owners of %v are not contiguous at %d", q, i)
+			}
+		}
+
+		for _, owner := range owners {
+			expected, _ := ring.getNodeByHash(owner.Range.End)
+			if owner.Node != expected {
+				t.Errorf("Range %v owned by %s, expected %s", owner.Range, owner.Node.ID, expected.ID)
+			}
+		}
+	}
+
+	// A single hash range has the same owner as a lookup of that hash
+	hash := ring.hash("some-key")
+	owners, _ := ring.OwnerOfRange(hash-1, hash)
+	node, _ := ring.GetNode("some-key")
+	if len(owners) != 1 || owners[0].Node != node {
+		t.Errorf("Expected %s to own the key's hash, got %v", node.ID, owners)
+	}
+}
@@ -0,0 +1,108 @@
+// Package ratelimit keeps each rate-limit counter on a single node of a
+// consistent hash ring. Buckets, such as one per user, are routed like any
+// other key, so every counter is incremented in one place and distributed
+// limits need no cross-node coordination. When membership changes move a
+// bucket away, the former owner is told to drop its counter, so a bucket that
+// later returns starts from a clean count instead of a stale one.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// Remap lists the buckets the local node stopped owning. Their counters
+// should be reset.
+type Remap struct {
+	Generation uint64
+	Lost       []string // Sorted bucket IDs
+}
+
+// Selector routes buckets to nodes and remembers the buckets counted on the
+// local node
+type Selector struct {
+	ring *consistenthashing.HashRing
+	self string
+
+	mu    sync.Mutex
+	local map[string]struct{}
+}
+
+// New creates a selector for the node selfID, typically the local process
+func New(ring *consistenthashing.HashRing, selfID string) (*Selector, error) {
+	if ring == nil {
+		return nil, errors.New("ring cannot be nil")
+	}
+	if selfID == "" {
+		return nil, consistenthashing.ErrInvalidNodeID
+	}
+
+	return &Selector{ring: ring, self: selfID, local: make(map[string]struct{})}, nil
+}
+
+// Owner returns the node that keeps the counter of a bucket
+func (s *Selector) Owner(bucket string) (*consistenthashing.Node, error) {
+	return s.ring.GetNode(bucket)
+}
+
+// Claim reports whether the local node owns a bucket. Owned buckets are
+// remembered, so the next Reconcile reports them if they move away; requests
+// for other buckets should be forwarded to their Owner.
+func (s *Selector) Claim(bucket string) bool {
+	if !s.ring.IsOwner(s.self, bucket) {
+		return false
+	}
+
+	s.mu.Lock()
+	s.local[bucket] = struct{}{}
+	s.mu.Unlock()
+	return true
+}
+
+// Forget stops tracking a bucket, for example once its counter expired
+func (s *Selector) Forget(bucket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.local, bucket)
+}
+
+// Reconcile checks every claimed bucket against the ring and returns, and
+// stops tracking, the ones the local node no longer owns
+func (s *Selector) Reconcile() Remap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remap := Remap{Generation: s.ring.Generation()}
+	for bucket := range s.local {
+		if !s.ring.IsOwner(s.self, bucket) {
+			remap.Lost = append(remap.Lost, bucket)
+			delete(s.local, bucket)
+		}
+	}
+	sort.Strings(remap.Lost)
+	return remap
+}
+
+// Run reconciles after every membership change and calls onRemap when
+// buckets moved away, until ctx is done. Events only serve as a trigger, so
+// dropped events merge into the next reconciliation.
+func (s *Selector) Run(ctx context.Context, onRemap func(Remap)) {
+	events, unsubscribe := s.ring.Watch(1)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-events:
+			if remap := s.Reconcile(); len(remap.Lost) > 0 {
+				onRemap(remap)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func newTestRing(t *testing.T, nodes int) *consistenthashing.HashRing {
+	t.Helper()
+	// FNV clusters sequential bucket IDs like user:1, user:2, so spread
+	// them with SHA-256 to make every node own some
+	ring, err := consistenthashing.NewHashRing(50, consistenthashing.WithHashFunction(&consistenthashing.SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < nodes; i++ {
+		ring.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	return ring
+}
+
+func TestSelector(t *testing.T) {
+	ring := newTestRing(t, 3)
+	s, err := New(ring, "node0")
+	if err != nil {
+		t.Fatalf("Failed to create selector: %v", err)
+	}
+
+	var claimed []string
+	for i := 0; i < 100; i++ {
+		bucket := fmt.Sprintf("user:%d", i)
+		owner, _ := s.Owner(bucket)
+		if s.Claim(bucket) != (owner.ID == "node0") {
+			t.Fatalf("Claim of %q disagrees with owner %s", bucket, owner.ID)
+		}
+		if owner.ID == "node0" {
+			claimed = append(claimed, bucket)
+		}
+	}
+	if len(claimed) == 0 {
+		t.Fatal("Expected node0 to own some buckets")
+	}
+
+	if remap := s.Reconcile(); len(remap.Lost) != 0 {
+		t.Errorf("Expected no remap without changes, got %v", remap.Lost)
+	}
+
+	// A new node takes over some of node0's buckets
+	ring.AddNode(&consistenthashing.Node{ID: "node3", Host: "localhost", Port: 8083})
+	remap := s.Reconcile()
+	if len(remap.Lost) == 0 || remap.Generation != ring.Generation() {
+		t.Fatalf("Expected lost buckets at generation %d, got %+v", ring.Generation(), remap)
+	}
+	for _, bucket := range remap.Lost {
+		if ring.IsOwner("node0", bucket) {
+			t.Errorf("Bucket %q reported lost but still owned", bucket)
+		}
+	}
+
+	// Lost buckets are only reported once
+	if remap := s.Reconcile(); len(remap.Lost) != 0 {
+		t.Errorf("Expected lost buckets to be forgotten, got %v", remap.Lost)
+	}
+
+	s.Forget(claimed[0])
+	if _, err := New(nil, "node0"); err == nil {
+		t.Error("Expected error for nil ring")
+	}
+}
+
+func TestSelectorRun(t *testing.T) {
+	ring := newTestRing(t, 2)
+	s, _ := New(ring, "node0")
+	for i := 0; i < 100; i++ {
+		s.Claim(fmt.Sprintf("user:%d", i))
+	}
+
+	remaps := make(chan Remap, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx, func(r Remap) { remaps <- r })
+
+	// Run subscribes asynchronously, so keep changing the ring until it reports
+	for i := 2; ; i++ {
+		ring.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+		select {
+		case remap := <-remaps:
+			if len(remap.Lost) == 0 {
+				t.Error("Expected lost buckets")
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+		if i > 100 {
+			t.Fatal("Timed out waiting for a remap")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package consistenthashing
+
+import (
+	"maps"
+	"slices"
+)
+
+// WithVirtualReplicas overrides the number of virtual replicas per node, for
+// changing it with Rebuild
+func WithVirtualReplicas(n int) Option {
+	return func(hr *HashRing) {
+		hr.virtualReplicas = n
+	}
+}
+
+// Rebuild returns a new ring with the same members, node states and drain
+// progress, built with the ring's settings changed by opts, such as
+// WithVirtualReplicas, WithHashFunction or WithWeightCurve. The report gives the share of keys
+// whose owner differs between the two rings, so a parameter change can be
+// measured before traffic is switched over. The new ring continues from the
+// next generation.
+//
+// Routing settings carry over. Per-ring machinery does not: history, load
+// tracking, key sampling, hooks, lock metrics, the rand source and watchers
+// must be passed again as options. The ring itself is unchanged.
+func (hr *HashRing) Rebuild(opts ...Option) (*HashRing, ChurnReport, error) {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	next := &HashRing{
+		virtualNodes:      make([]VirtualNode, 0, len(hr.virtualNodes)),
+		nodes:             make(map[string]*Node, len(hr.nodes)),
+		virtualReplicas:   hr.virtualReplicas,
+		hasher:            hr.hasher,
+		hashSeed:          hr.hashSeed,
+		affinity:          maps.Clone(hr.affinity),
+		classes:           maps.Clone(hr.classes),
+		proximity:         slices.Clone(hr.proximity),
+		weightedOrder:     hr.weightedOrder,
+		shardCount:        hr.shardCount,
+		codec:             hr.codec,
+		clock:             hr.clock,
+		weightCurve:       hr.weightCurve,
+		maxVirtualNodes:   hr.maxVirtualNodes,
+		fallback:          hr.fallback,
+		emptyKeyPolicy:    hr.emptyKeyPolicy,
+		emptyKeyNode:      hr.emptyKeyNode,
+		loadBound:         hr.loadBound,
+		spillover:         hr.spillover,
+		spillCandidates:   hr.spillCandidates,
+		states:            maps.Clone(hr.states),
+		replicationFactor: hr.replicationFactor,
+		conflictPolicy:    hr.conflictPolicy,
+		watchers:          &watchers{subs: make(map[int]chan RingEvent)},
+		generation:        hr.generation,
+	}
+	next.mu.disabled = hr.mu.disabled
+	for id, drain := range hr.drains {
+		if next.drains == nil {
+			next.drains = make(map[string]*drainState, len(hr.drains))
+		}
+		next.drains[id] = drain.clone()
+	}
+	if err := next.configure(opts); err != nil {
+		return nil, ChurnReport{}, err
+	}
+
+	next.mu.Lock()
+	defer next.mu.Unlock()
+
+	members := hr.sortedNodesLocked()
+	for _, node := range members {
+		if _, err := next.virtualCount(node); err != nil {
+			return nil, ChurnReport{}, err
+		}
+		next.nodes[node.ID] = node
+	}
+	if !next.rescaleLocked() {
+		next.placeMembersLocked(members)
+	}
+	next.advanceGenerationLocked()
+
+	return next, ChurnReport{
+		MovedFraction: movedFraction(hr.virtualNodes, next.virtualNodes),
+		Generation:    next.generation,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRebuild(t *testing.T) {
+	hr, err := NewHashRing(50, WithCapacityClasses(map[string]int{"big": 3}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	hr.AddNode(&Node{ID: "big", Host: "localhost", Port: 9000, Class: "big"})
+
+	// Unchanged settings move nothing
+	same, report, err := hr.Rebuild()
+	if err != nil {
+		t.Fatalf("Failed to rebuild: %v", err)
+	}
+	if report.MovedFraction != 0 || same.VirtualSize() != hr.VirtualSize() {
+		t.Errorf("Expected an identical ring, got %.4f moved and %d virtual nodes", report.MovedFraction, same.VirtualSize())
+	}
+	if report.Generation != hr.Generation()+1 || same.Generation() != report.Generation {
+		t.Errorf("Expected generation %d, got report %d and ring %d", hr.Generation()+1, report.Generation, same.Generation())
+	}
+
+	more, report, err := hr.Rebuild(WithVirtualReplicas(200))
+	if err != nil {
+		t.Fatalf("Failed to rebuild: %v", err)
+	}
+	if got := more.VirtualSize(); got != 200*5+200*3 {
+		t.Errorf("Expected %d virtual nodes, got %d", 200*8, got)
+	}
+	if err := more.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+	if report.MovedFraction <= 0 || report.MovedFraction >= 1 {
+		t.Errorf("Expected part of the keys to move, got %.4f", report.MovedFraction)
+	}
+	if fmt.Sprint(nodeIDs(more.GetAllNodes())) != fmt.Sprint(nodeIDs(hr.GetAllNodes())) {
+		t.Errorf("Expected the same members, got %v", nodeIDs(more.GetAllNodes()))
+	}
+	if hr.VirtualSize() != 50*8 {
+		t.Errorf("Expected the original ring to be unchanged, got %d virtual nodes", hr.VirtualSize())
+	}
+
+	if _, _, err := hr.Rebuild(WithVirtualReplicas(0)); !errors.Is(err, ErrInvalidVirtualReplicas) {
+		t.Errorf("Expected ErrInvalidVirtualReplicas, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RendezvousRing places keys with weighted rendezvous (highest random weight)
+// hashing: every node scores every key and the best score wins. It needs no
+// virtual nodes and gives each node exactly its weighted share in
+// expectation, at the cost of O(n) lookups, which suits small clusters. It
+// implements Ring, so it can replace a HashRing behind the interface.
+type RendezvousRing struct {
+	hasher HashFunction
+	nodes  map[string]*rendezvousNode
+	sorted []*rendezvousNode // Sorted by ID, so score ties resolve deterministically
+	mu     sync.RWMutex
+}
+
+// RendezvousRing must satisfy Ring
+var _ Ring = (*RendezvousRing)(nil)
+
+// rendezvousNode is a member with its precomputed ID hash
+type rendezvousNode struct {
+	node   *Node
+	hash   uint64
+	weight float64
+}
+
+// NewRendezvousRing creates an empty rendezvous ring. A nil hasher means
+// FNVHasher, the HashRing default.
+func NewRendezvousRing(hasher HashFunction) *RendezvousRing {
+	if hasher == nil {
+		hasher = &FNVHasher{}
+	}
+	return &RendezvousRing{hasher: hasher, nodes: make(map[string]*rendezvousNode)}
+}
+
+// AddNode adds a node. Adding an existing ID is a no-op, as with HashRing.
+func (r *RendezvousRing) AddNode(node *Node) error {
+	if node == nil {
+		return errors.New("node cannot be nil")
+	}
+	if err := node.Validate(); err != nil {
+		return fmt.Errorf("invalid node: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.nodes[node.ID]; exists {
+		return nil
+	}
+
+	rn := &rendezvousNode{
+		node:   node,
+		hash:   r.hasher.Hash(node.ID),
+		weight: float64(nodeWeight(node)),
+	}
+	r.nodes[node.ID] = rn
+	i := sort.Search(len(r.sorted), func(i int) bool {
+		return r.sorted[i].node.ID > node.ID
+	})
+	r.sorted = append(r.sorted, nil)
+	copy(r.sorted[i+1:], r.sorted[i:])
+	r.sorted[i] = rn
+
+	return nil
+}
+
+// RemoveNode removes a node. Only the keys it owned move.
+func (r *RendezvousRing) RemoveNode(nodeID string) error {
+	if strings.TrimSpace(nodeID) == "" {
+		return ErrInvalidNodeID
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.nodes[nodeID]; !exists {
+		return ErrNodeNotFound
+	}
+	delete(r.nodes, nodeID)
+	r.sorted = slices.DeleteFunc(r.sorted, func(rn *rendezvousNode) bool {
+		return rn.node.ID == nodeID
+	})
+
+	return nil
+}
+
+// GetNode returns the node with the best score for key
+func (r *RendezvousRing) GetNode(key string) (*Node, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.sorted) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	keyHash := r.hasher.Hash(key)
+	best, bestScore := r.sorted[0], math.Inf(1)
+	for _, rn := range r.sorted {
+		if score := rn.score(keyHash); score < bestScore {
+			best, bestScore = rn, score
+		}
+	}
+	return best.node, nil
+}
+
+// GetNodes returns up to count distinct nodes for key, best score first
+func (r *RendezvousRing) GetNodes(key string, count int) ([]*Node, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.sorted) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	keyHash := r.hasher.Hash(key)
+	scored := make([]rendezvousScore, len(r.sorted))
+	for i, rn := range r.sorted {
+		scored[i] = rendezvousScore{node: rn.node, score: rn.score(keyHash)}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score < scored[j].score
+	})
+
+	nodes := make([]*Node, min(count, len(scored)))
+	for i := range nodes {
+		nodes[i] = scored[i].node
+	}
+	return nodes, nil
+}
+
+// GetAllNodes returns all nodes sorted by ID
+func (r *RendezvousRing) GetAllNodes() []*Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	nodes := make([]*Node, len(r.sorted))
+	for i, rn := range r.sorted {
+		nodes[i] = rn.node
+	}
+	return nodes
+}
+
+// GetNodeByID returns a node by its ID
+func (r *RendezvousRing) GetNodeByID(nodeID string) (*Node, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rn, exists := r.nodes[nodeID]
+	if !exists {
+		return nil, ErrNodeNotFound
+	}
+	return rn.node, nil
+}
+
+// HasNode reports whether a node is in the ring
+func (r *RendezvousRing) HasNode(nodeID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.nodes[nodeID]
+	return exists
+}
+
+// Size returns the number of nodes
+func (r *RendezvousRing) Size() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.nodes)
+}
+
+// rendezvousScore is a node's score for one key
+type rendezvousScore struct {
+	node  *Node
+	score float64
+}
+
+// score returns the node's weighted score for a key hash; lower wins. The
+// exponential race -ln(u)/w makes a node win with probability proportional
+// to its weight, where u is uniform per key and node. mix64 keeps scores
+// independent even when FNV hashes of similar keys and IDs are close.
+func (rn *rendezvousNode) score(keyHash uint64) float64 {
+	u := (float64(mix64(keyHash^rn.hash)>>11) + 0.5) / (1 << 53)
+	return -math.Log(u) / rn.weight
+}
@@ -0,0 +1,85 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRendezvousRing(t *testing.T) {
+	ring := NewRendezvousRing(nil)
+	if _, err := ring.GetNode("key"); !errors.Is(err, ErrEmptyRing) {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i}); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+	if ring.Size() != 5 || !ring.HasNode("node3") {
+		t.Fatalf("Expected 5 nodes including node3, got %d", ring.Size())
+	}
+
+	keys := make([]string, 10000)
+	owners := make(map[string]string, len(keys))
+	counts := make(map[string]int)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user:%d", i)
+		node, err := ring.GetNode(keys[i])
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		owners[keys[i]] = node.ID
+		counts[node.ID]++
+
+		nodes, _ := ring.GetNodes(keys[i], 3)
+		if len(nodes) != 3 || nodes[0] != node {
+			t.Fatalf("Expected 3 replicas led by the owner for %s", keys[i])
+		}
+	}
+
+	// Without virtual nodes, each node still gets close to its share
+	for id, count := range counts {
+		if count < 1800 || count > 2200 {
+			t.Errorf("Node %s owns %d of 10000 keys, expected about 2000", id, count)
+		}
+	}
+
+	// Removing a node only moves its own keys
+	if err := ring.RemoveNode("node2"); err != nil {
+		t.Fatalf("Failed to remove node: %v", err)
+	}
+	for _, key := range keys {
+		node, _ := ring.GetNode(key)
+		if owners[key] != "node2" && node.ID != owners[key] {
+			t.Errorf("Key %s moved from %s to %s", key, owners[key], node.ID)
+		}
+	}
+	if err := ring.RemoveNode("node2"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if _, err := ring.GetNodes("key", 0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("Expected ErrInvalidCount, got %v", err)
+	}
+	if nodes, _ := ring.GetNodes("key", 10); len(nodes) != 4 {
+		t.Errorf("Expected GetNodes to cap at the ring size, got %d", len(nodes))
+	}
+}
+
+func TestRendezvousRingWeights(t *testing.T) {
+	ring := NewRendezvousRing(&SHA256Hasher{})
+	ring.AddNode(&Node{ID: "light", Host: "localhost", Port: 8080, Weight: 1})
+	ring.AddNode(&Node{ID: "heavy", Host: "localhost", Port: 8081, Weight: 3})
+
+	heavy := 0
+	for i := 0; i < 10000; i++ {
+		node, _ := ring.GetNode(fmt.Sprintf("key%d", i))
+		if node.ID == "heavy" {
+			heavy++
+		}
+	}
+	if heavy < 7200 || heavy > 7800 {
+		t.Errorf("Expected heavy node to own about 75%% of keys, got %d of 10000", heavy)
+	}
+}
@@ -0,0 +1,111 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNoReplicaHasKey is returned by ReplicatedRing reads when every replica's
+// membership filter rules the key out
+var ErrNoReplicaHasKey = errors.New("no replica may hold the key")
+
+// MembershipFilter reports whether a node may hold a key. It may return false
+// positives, as a Bloom filter does, but false must mean the key is definitely
+// absent.
+type MembershipFilter func(key string) bool
+
+// ReplicatedRing routes keys to replica sets of the ring's replication factor
+// (see WithReplicationFactor). Writes go to every replica; reads can skip
+// replicas whose membership filter says they don't have the key, which cuts
+// the fan-out of reads that miss.
+type ReplicatedRing struct {
+	ring *HashRing
+
+	mu      sync.RWMutex
+	filters map[string]MembershipFilter
+}
+
+// NewReplicatedRing wraps a ring
+func NewReplicatedRing(ring *HashRing) (*ReplicatedRing, error) {
+	if ring == nil {
+		return nil, errors.New("ring cannot be nil")
+	}
+	return &ReplicatedRing{ring: ring, filters: make(map[string]MembershipFilter)}, nil
+}
+
+// Ring returns the underlying ring
+func (r *ReplicatedRing) Ring() *HashRing {
+	return r.ring
+}
+
+// SetFilter registers the membership filter of a node, replacing any earlier
+// one. A nil filter removes it, so every key is read from the node again.
+func (r *ReplicatedRing) SetFilter(nodeID string, filter MembershipFilter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if filter == nil {
+		delete(r.filters, nodeID)
+		return
+	}
+	r.filters[nodeID] = filter
+}
+
+// Replicas returns the full replica set of a key, the nodes writes go to
+func (r *ReplicatedRing) Replicas(key string) ([]*Node, error) {
+	return r.ring.GetNodes(key, r.replicationFactor())
+}
+
+// ReadReplicas returns the replicas of a key that may hold it, in replica
+// order. It returns ErrNoReplicaHasKey when every filter rules the key out.
+func (r *ReplicatedRing) ReadReplicas(key string) ([]*Node, error) {
+	replicas, err := r.Replicas(key)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	candidates := replicas[:0]
+	for _, node := range replicas {
+		if filter, ok := r.filters[node.ID]; !ok || filter(key) {
+			candidates = append(candidates, node)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoReplicaHasKey
+	}
+	return candidates, nil
+}
+
+// Read calls read on the key's read replicas in order until one succeeds and
+// returns that node. When every replica fails, the failures are returned in a
+// *MultiError.
+func (r *ReplicatedRing) Read(ctx context.Context, key string, read func(ctx context.Context, node *Node) error) (*Node, error) {
+	candidates, err := r.ReadReplicas(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures MultiError
+	for _, node := range candidates {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := read(ctx, node); err != nil {
+			failures.add(key, node.ID, err)
+			continue
+		}
+		return node, nil
+	}
+	return nil, &failures
+}
+
+func (r *ReplicatedRing) replicationFactor() int {
+	r.ring.mu.RLock()
+	defer r.ring.mu.RUnlock()
+
+	return r.ring.replicationFactor
+}
@@ -0,0 +1,95 @@
+package consistenthashing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newReplicatedTestRing(t *testing.T) *ReplicatedRing {
+	t.Helper()
+	ring, err := NewHashRing(50, WithReplicationFactor(3))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	r, err := NewReplicatedRing(ring)
+	if err != nil {
+		t.Fatalf("Failed to create replicated ring: %v", err)
+	}
+	return r
+}
+
+func TestReplicatedRingFilters(t *testing.T) {
+	r := newReplicatedTestRing(t)
+	key := "user:42"
+
+	replicas, err := r.Replicas(key)
+	if err != nil || len(replicas) != 3 {
+		t.Fatalf("Expected 3 replicas, got %v (%v)", replicas, err)
+	}
+
+	// The first replica's filter rules the key out
+	r.SetFilter(replicas[0].ID, func(k string) bool { return k != key })
+	reads, err := r.ReadReplicas(key)
+	if err != nil || len(reads) != 2 || reads[0] != replicas[1] || reads[1] != replicas[2] {
+		t.Errorf("Expected reads from %s and %s, got %v (%v)", replicas[1].ID, replicas[2].ID, reads, err)
+	}
+	if reads, _ := r.ReadReplicas("other"); len(reads) != 3 {
+		t.Errorf("Expected filters to pass other keys, got %d replicas", len(reads))
+	}
+
+	// Filters never affect writes
+	if writes, _ := r.Replicas(key); len(writes) != 3 {
+		t.Errorf("Expected writes to every replica, got %d", len(writes))
+	}
+
+	for _, node := range replicas[1:] {
+		r.SetFilter(node.ID, func(string) bool { return false })
+	}
+	if _, err := r.ReadReplicas(key); !errors.Is(err, ErrNoReplicaHasKey) {
+		t.Errorf("Expected ErrNoReplicaHasKey, got %v", err)
+	}
+
+	r.SetFilter(replicas[2].ID, nil)
+	if reads, _ := r.ReadReplicas(key); len(reads) != 1 || reads[0] != replicas[2] {
+		t.Errorf("Expected removing a filter to restore the replica, got %v", reads)
+	}
+}
+
+func TestReplicatedRingRead(t *testing.T) {
+	r := newReplicatedTestRing(t)
+	key := "user:42"
+	replicas, _ := r.Replicas(key)
+
+	unavailable := errors.New("replica unavailable")
+	var tried []string
+	node, err := r.Read(context.Background(), key, func(ctx context.Context, node *Node) error {
+		tried = append(tried, node.ID)
+		if node == replicas[0] {
+			return unavailable
+		}
+		return nil
+	})
+	if err != nil || node != replicas[1] || len(tried) != 2 {
+		t.Errorf("Expected to fall back to %s, got %v after %v (%v)", replicas[1].ID, node, tried, err)
+	}
+
+	_, err = r.Read(context.Background(), key, func(ctx context.Context, node *Node) error {
+		return unavailable
+	})
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Failures) != 3 || !errors.Is(err, unavailable) {
+		t.Errorf("Expected a MultiError with 3 failures, got %v", err)
+	}
+	if multi != nil && multi.Failures[0].NodeID != replicas[0].ID {
+		t.Errorf("Expected failures in replica order, got %v", multi.Failures[0])
+	}
+
+	if _, err := NewReplicatedRing(nil); err == nil {
+		t.Error("Expected error for nil ring")
+	}
+}
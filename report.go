@@ -0,0 +1,136 @@
+package consistenthashing
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReportFormat selects the encoding used by ExportReport
+type ReportFormat int
+
+const (
+	ReportCSV ReportFormat = iota
+	ReportJSON
+)
+
+// ErrInvalidReportFormat is returned for unknown report formats
+var ErrInvalidReportFormat = errors.New("invalid report format")
+
+// NodeReport describes a single node's position in the ring
+type NodeReport struct {
+	ID           string            `json:"id"`
+	Address      string            `json:"address"`
+	Zone         string            `json:"zone,omitempty"`
+	Class        string            `json:"class,omitempty"`
+	State        string            `json:"state"`
+	Weight       int               `json:"weight"`
+	VirtualNodes int               `json:"virtual_nodes"`
+	Ownership    float64           `json:"ownership"`
+	Lookups      uint64            `json:"lookups"`
+	Meta         map[string]string `json:"meta,omitempty"` // A copy of the node's labels
+}
+
+// nodeReports builds one NodeReport per node, sorted by ID. Lookup counts are
+// only filled in when key sampling is enabled.
+func (hr *HashRing) nodeReports() []NodeReport {
+	lookups, _ := hr.LookupCounts()
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	shares := hr.ownershipLocked()
+	vnodes := make(map[string]int, len(hr.nodes))
+	for _, vnode := range hr.virtualNodes {
+		vnodes[vnode.Node.ID]++
+	}
+
+	reports := make([]NodeReport, 0, len(hr.nodes))
+	for _, node := range hr.sortedNodesLocked() {
+		reports = append(reports, NodeReport{
+			ID:           node.ID,
+			Address:      node.String(),
+			Zone:         node.Zone,
+			Class:        node.Class,
+			State:        hr.states[node.ID].String(),
+			Weight:       hr.weightLocked(node),
+			VirtualNodes: vnodes[node.ID],
+			Ownership:    shares[node.ID],
+			Lookups:      lookups[node.ID],
+			Meta:         maps.Clone(node.Meta),
+		})
+	}
+
+	return reports
+}
+
+// ExportReport writes per-node ownership, weight, zone, class, state, labels
+// and lookup counts to w in the given format, for capacity planning
+// spreadsheets and BI tools. CSV reports hold the labels in one column as
+// key=value pairs sorted by key and separated by semicolons.
+func (hr *HashRing) ExportReport(w io.Writer, format ReportFormat) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+
+	reports := hr.nodeReports()
+
+	switch format {
+	case ReportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "address", "weight", "virtual_nodes", "ownership", "lookups", "zone", "state", "class", "meta"}); err != nil {
+			return err
+		}
+		for _, r := range reports {
+			record := []string{
+				r.ID,
+				r.Address,
+				strconv.Itoa(r.Weight),
+				strconv.Itoa(r.VirtualNodes),
+				strconv.FormatFloat(r.Ownership, 'f', 6, 64),
+				strconv.FormatUint(r.Lookups, 10),
+				r.Zone,
+				r.State,
+				r.Class,
+				formatMeta(r.Meta),
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case ReportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidReportFormat, format)
+	}
+}
+
+// formatMeta formats labels as key=value pairs sorted by key and separated by
+// semicolons
+func formatMeta(meta map[string]string) string {
+	pairs := make([]string, 0, len(meta))
+	for _, k := range sortedMetaKeys(meta) {
+		pairs = append(pairs, k+"="+meta[k])
+	}
+	return strings.Join(pairs, ";")
+}
+
+// sortedMetaKeys returns the keys of meta in ascending order
+func sortedMetaKeys(meta map[string]string) []string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestExportReportCSV(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081, Weight: 2, Zone: "us-east-1b",
+		Meta: map[string]string{"rack": "r1", "disk": "ssd"}})
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.SetNodeState("node2", NodeDown)
+
+	var buf bytes.Buffer
+	if err := ring.ExportReport(&buf, ReportCSV); err != nil {
+		t.Fatalf("Failed to export report: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d records", len(records))
+	}
+	if records[1][0] != "node1" || records[2][0] != "node2" {
+		t.Errorf("Expected rows sorted by ID, got %s, %s", records[1][0], records[2][0])
+	}
+	if records[2][2] != "2" || records[2][3] != "20" {
+		t.Errorf("Expected node2 weight 2 with 20 virtual nodes, got %s and %s", records[2][2], records[2][3])
+	}
+	if records[2][6] != "us-east-1b" || records[2][7] != "down" || records[1][7] != "active" {
+		t.Errorf("Expected zone and state columns, got %v and %v", records[1], records[2])
+	}
+	if records[2][9] != "disk=ssd;rack=r1" || records[1][9] != "" {
+		t.Errorf("Expected sorted meta columns, got %q and %q", records[1][9], records[2][9])
+	}
+}
+
+func TestExportReportJSON(t *testing.T) {
+	ring, err := NewHashRing(10, WithKeySampling(10))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080, Meta: map[string]string{"disk": "ssd"}})
+	ring.GetNode("key1")
+	ring.GetNode("key2")
+
+	var buf bytes.Buffer
+	if err := ring.ExportReport(&buf, ReportJSON); err != nil {
+		t.Fatalf("Failed to export report: %v", err)
+	}
+
+	var reports []NodeReport
+	if err := json.Unmarshal(buf.Bytes(), &reports); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 node report, got %d", len(reports))
+	}
+	if reports[0].Ownership != 1 || reports[0].Lookups != 2 {
+		t.Errorf("Expected full ownership and 2 lookups, got %+v", reports[0])
+	}
+	if reports[0].Meta["disk"] != "ssd" {
+		t.Errorf("Expected node labels in the report, got %v", reports[0].Meta)
+	}
+
+	if err := ring.ExportReport(&buf, ReportFormat(42)); !errors.Is(err, ErrInvalidReportFormat) {
+		t.Errorf("Expected ErrInvalidReportFormat, got %v", err)
+	}
+}
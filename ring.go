@@ -0,0 +1,18 @@
+package consistenthashing
+
+// Ring is the set of operations shared by ring implementations. Code that
+// routes keys should accept a Ring so tests can substitute fakes and
+// deployments can switch strategies without changing call sites.
+type Ring interface {
+	AddNode(node *Node) error
+	RemoveNode(nodeID string) error
+	GetNode(key string) (*Node, error)
+	GetNodes(key string, count int) ([]*Node, error)
+	GetAllNodes() []*Node
+	GetNodeByID(nodeID string) (*Node, error)
+	HasNode(nodeID string) bool
+	Size() int
+}
+
+// HashRing must satisfy Ring
+var _ Ring = (*HashRing)(nil)
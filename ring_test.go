@@ -0,0 +1,38 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingImplementations(t *testing.T) {
+	hashRing, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	maglevRing, err := NewMaglevRing(0, nil)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	for _, ring := range []Ring{hashRing, NewRendezvousRing(nil), maglevRing, NewJumpRing(nil)} {
+		ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+		ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+		if node, err := ring.GetNode("key"); err != nil || node == nil {
+			t.Errorf("%T: expected a node, got %v", ring, err)
+		}
+		if ids := fmt.Sprint(nodeIDs(ring.GetAllNodes())); ids != "[node1 node2]" {
+			t.Errorf("%T: expected nodes sorted by ID, got %s", ring, ids)
+		}
+	}
+}
+
+// nodeIDs returns the IDs of nodes in order
+func nodeIDs(nodes []*Node) []string {
+	ids := make([]string, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
+	}
+	return ids
+}
@@ -0,0 +1,170 @@
+package consistenthashing
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrSamplingDisabled is returned by sampling APIs when the ring was created
+// without WithKeySampling
+var ErrSamplingDisabled = errors.New("key sampling is not enabled")
+
+// WithKeySampling enables a reservoir sample of up to size looked-up keys per
+// node, so load analysis can run against live traffic
+func WithKeySampling(size int) Option {
+	return func(hr *HashRing) {
+		if size > 0 {
+			hr.sampler = newKeySampler(size)
+		}
+	}
+}
+
+// reservoir holds a uniform sample of the keys routed to one node
+type reservoir struct {
+	seen uint64
+	keys []string
+}
+
+// keySampler keeps one reservoir per node using Algorithm R. It has its own
+// lock because lookups only hold the ring's read lock.
+type keySampler struct {
+	mu         optionalMutex
+	size       int
+	rng        *lockedRand // The ring's generator, set once all options are applied
+	reservoirs map[string]*reservoir
+}
+
+func newKeySampler(size int) *keySampler {
+	return &keySampler{
+		size:       size,
+		reservoirs: make(map[string]*reservoir),
+	}
+}
+
+// record adds a looked-up key to the reservoir of the node that served it
+func (s *keySampler) record(nodeID, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, exists := s.reservoirs[nodeID]
+	if !exists {
+		r = &reservoir{keys: make([]string, 0, s.size)}
+		s.reservoirs[nodeID] = r
+	}
+
+	r.seen++
+	if len(r.keys) < s.size {
+		r.keys = append(r.keys, key)
+		return
+	}
+
+	// Replace a random slot with probability size/seen
+	if j := s.rng.Int63n(int64(r.seen)); j < int64(s.size) {
+		r.keys[j] = key
+	}
+}
+
+// forget drops the reservoir of a node that left the ring
+func (s *keySampler) forget(nodeID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reservoirs, nodeID)
+}
+
+// SampledKeys returns a copy of the current key sample for each node
+func (hr *HashRing) SampledKeys() (map[string][]string, error) {
+	if hr.sampler == nil {
+		return nil, ErrSamplingDisabled
+	}
+
+	hr.sampler.mu.Lock()
+	defer hr.sampler.mu.Unlock()
+
+	samples := make(map[string][]string, len(hr.sampler.reservoirs))
+	for nodeID, r := range hr.sampler.reservoirs {
+		samples[nodeID] = append([]string(nil), r.keys...)
+	}
+
+	return samples, nil
+}
+
+// LookupCounts returns how many lookups each node has served since sampling
+// started or was last reset
+func (hr *HashRing) LookupCounts() (map[string]uint64, error) {
+	if hr.sampler == nil {
+		return nil, ErrSamplingDisabled
+	}
+
+	hr.sampler.mu.Lock()
+	defer hr.sampler.mu.Unlock()
+
+	counts := make(map[string]uint64, len(hr.sampler.reservoirs))
+	for nodeID, r := range hr.sampler.reservoirs {
+		counts[nodeID] = r.seen
+	}
+
+	return counts, nil
+}
+
+// ResetKeySamples discards all sampled keys and lookup counts
+func (hr *HashRing) ResetKeySamples() {
+	if hr.sampler == nil {
+		return
+	}
+
+	hr.sampler.mu.Lock()
+	defer hr.sampler.mu.Unlock()
+
+	hr.sampler.reservoirs = make(map[string]*reservoir)
+}
+
+// SampledLoadDistribution re-maps the sampled keys onto the current ring and
+// returns the estimated number of lookups each node would serve. Every sampled
+// key stands in for seen/len(sample) lookups of its reservoir, so busy nodes
+// are not under-represented once their reservoir is full.
+func (hr *HashRing) SampledLoadDistribution() (map[string]int, error) {
+	if hr.sampler == nil {
+		return nil, ErrSamplingDisabled
+	}
+
+	type weightedSample struct {
+		keys   []string
+		weight float64
+	}
+
+	hr.sampler.mu.Lock()
+	samples := make([]weightedSample, 0, len(hr.sampler.reservoirs))
+	for _, r := range hr.sampler.reservoirs {
+		if len(r.keys) == 0 {
+			continue
+		}
+		samples = append(samples, weightedSample{
+			keys:   append([]string(nil), r.keys...),
+			weight: float64(r.seen) / float64(len(r.keys)),
+		})
+	}
+	hr.sampler.mu.Unlock()
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if len(hr.virtualNodes) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	estimates := make(map[string]float64)
+	for _, sample := range samples {
+		for _, key := range sample.keys {
+			node := hr.virtualNodes[hr.search(hr.keyHashLocked(key))].Node
+			estimates[node.ID] += sample.weight
+		}
+	}
+
+	distribution := make(map[string]int, len(estimates))
+	for nodeID, estimate := range estimates {
+		distribution[nodeID] = int(math.Round(estimate))
+	}
+
+	return distribution, nil
+}
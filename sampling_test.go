@@ -0,0 +1,88 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestKeySamplingDisabled(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	if _, err := ring.SampledKeys(); err != ErrSamplingDisabled {
+		t.Errorf("Expected ErrSamplingDisabled, got %v", err)
+	}
+	if _, err := ring.SampledLoadDistribution(); err != ErrSamplingDisabled {
+		t.Errorf("Expected ErrSamplingDisabled, got %v", err)
+	}
+}
+
+func TestKeySampling(t *testing.T) {
+	ring, err := NewHashRing(10, WithKeySampling(5))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+
+	lookups := 200
+	for i := 0; i < lookups; i++ {
+		if _, err := ring.GetNode(fmt.Sprintf("key_%d", i)); err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+	}
+
+	samples, err := ring.SampledKeys()
+	if err != nil {
+		t.Fatalf("Failed to get sampled keys: %v", err)
+	}
+	for nodeID, keys := range samples {
+		if len(keys) > 5 {
+			t.Errorf("Expected at most 5 sampled keys for %s, got %d", nodeID, len(keys))
+		}
+		for _, key := range keys {
+			node, _ := ring.GetNode(key)
+			if node.ID != nodeID {
+				t.Errorf("Sampled key %s recorded for %s but maps to %s", key, nodeID, node.ID)
+			}
+		}
+	}
+
+	ring.ResetKeySamples()
+	for i := 0; i < lookups; i++ {
+		ring.GetNode(fmt.Sprintf("key_%d", i))
+	}
+
+	counts, err := ring.LookupCounts()
+	if err != nil {
+		t.Fatalf("Failed to get lookup counts: %v", err)
+	}
+	total := uint64(0)
+	for _, count := range counts {
+		total += count
+	}
+	if total != uint64(lookups) {
+		t.Errorf("Expected %d lookups, got %d", lookups, total)
+	}
+
+	// Without membership changes the re-mapped estimate matches the counters
+	distribution, err := ring.SampledLoadDistribution()
+	if err != nil {
+		t.Fatalf("Failed to get sampled distribution: %v", err)
+	}
+	for nodeID, count := range counts {
+		if uint64(distribution[nodeID]) != count {
+			t.Errorf("Expected %d estimated lookups for %s, got %d", count, nodeID, distribution[nodeID])
+		}
+	}
+
+	// Removing a node drops its reservoir
+	ring.RemoveNode("node1")
+	samples, _ = ring.SampledKeys()
+	if _, exists := samples["node1"]; exists {
+		t.Error("Expected samples of removed node to be discarded")
+	}
+}
@@ -0,0 +1,138 @@
+package consistenthashing
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// WithHashSeed seeds the ring's hash function, so the placement of keys
+// can't be predicted without the seed. It applies to the built-in hashers,
+// whatever the order of the options. Seeded rings record their hash function
+// as Custom in snapshots, keeping the seed out of them, so pass the hasher and
+// seed again when restoring. Seeding makes crafting colliding keys much
+// harder, but only SipHasher is designed to resist it outright.
+func WithHashSeed(seed uint64) Option {
+	return func(hr *HashRing) {
+		hr.hashSeed = seed
+	}
+}
+
+// seedable is implemented by the hashers WithHashSeed applies to
+type seedable interface {
+	seed() uint64
+	withSeed(seed uint64) HashFunction
+}
+
+// applyHashSeed replaces the ring's hasher with a seeded copy. The caller's
+// hasher is left unchanged.
+func (hr *HashRing) applyHashSeed() error {
+	if hr.hashSeed == 0 {
+		return nil
+	}
+	h, ok := hr.hasher.(seedable)
+	if !ok {
+		return errors.New("hash function does not support WithHashSeed")
+	}
+	hr.hasher = h.withSeed(hr.hashSeed)
+	return nil
+}
+
+func (f *FNVHasher) seed() uint64 { return f.Seed }
+
+func (f *FNVHasher) withSeed(seed uint64) HashFunction { return &FNVHasher{Seed: seed} }
+
+func (x *XXHasher) seed() uint64 { return x.Seed }
+
+func (x *XXHasher) withSeed(seed uint64) HashFunction { return &XXHasher{Seed: seed} }
+
+func (m *Murmur3Hasher) seed() uint64 { return m.Seed }
+
+func (m *Murmur3Hasher) withSeed(seed uint64) HashFunction { return &Murmur3Hasher{Seed: seed} }
+
+func (c *CRC32Hasher) seed() uint64 { return c.Seed }
+
+func (c *CRC32Hasher) withSeed(seed uint64) HashFunction { return &CRC32Hasher{Seed: seed} }
+
+func (s *SHA256Hasher) seed() uint64 { return s.Seed }
+
+func (s *SHA256Hasher) withSeed(seed uint64) HashFunction {
+	return &SHA256Hasher{Mapping: s.Mapping, Seed: seed}
+}
+
+func (m *MD5Hasher) seed() uint64 { return m.Seed }
+
+func (m *MD5Hasher) withSeed(seed uint64) HashFunction {
+	return &MD5Hasher{Mapping: m.Mapping, Seed: seed}
+}
+
+func (s *SHA1Hasher) seed() uint64 { return s.Seed }
+
+func (s *SHA1Hasher) withSeed(seed uint64) HashFunction {
+	return &SHA1Hasher{Mapping: s.Mapping, Seed: seed}
+}
+
+// SipHasher implements HashFunction using SipHash-2-4, a keyed hash designed
+// so that without the key nobody can craft keys that collide or land on one
+// node. Use it for rings routing untrusted keys, such as a public-facing
+// cache. Key must be secret and random, for example from crypto/rand, and
+// shared by every router of the ring. Its rings are recorded as Custom in
+// snapshots, so the key never leaves the process.
+type SipHasher struct {
+	Key [16]byte
+}
+
+func (s *SipHasher) Hash(key string) uint64 {
+	return siphash(&s.Key, key)
+}
+
+func (s *SipHasher) HashBytes(key []byte) uint64 {
+	return siphash(&s.Key, key)
+}
+
+// siphash computes SipHash-2-4 of b under a 128-bit key
+func siphash[T string | []byte](key *[16]byte, b T) uint64 {
+	k0, k1 := le64(key[:], 0), le64(key[:], 8)
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	round := func() {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13) ^ v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16) ^ v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21) ^ v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17) ^ v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+
+	n := len(b)
+	for ; len(b) >= 8; b = b[8:] {
+		m := le64(b, 0)
+		v3 ^= m
+		round()
+		round()
+		v0 ^= m
+	}
+
+	// The last block holds the remaining bytes and the length's low byte
+	m := uint64(n) << 56
+	for i := len(b) - 1; i >= 0; i-- {
+		m |= uint64(b[i]) << (8 * i)
+	}
+	v3 ^= m
+	round()
+	round()
+	v0 ^= m
+
+	v2 ^= 0xff
+	round()
+	round()
+	round()
+	round()
+	return v0 ^ v1 ^ v2 ^ v3
+}
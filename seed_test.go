@@ -0,0 +1,106 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSipHasher(t *testing.T) {
+	// Reference vectors from the SipHash paper: key 00..0f, message 00..n-1
+	h := &SipHasher{}
+	for i := range h.Key {
+		h.Key[i] = byte(i)
+	}
+	msg := make([]byte, 16)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	for n, expected := range map[int]uint64{
+		0:  0x726fdb47dd0e0e31,
+		1:  0x74f839c593dc67fd,
+		8:  0x93f5f5799a932462,
+		15: 0xa129ca6149be45e5,
+	} {
+		if got := h.HashBytes(msg[:n]); got != expected {
+			t.Errorf("%d bytes: expected %x, got %x", n, expected, got)
+		}
+		if h.Hash(string(msg[:n])) != h.HashBytes(msg[:n]) {
+			t.Errorf("Hash and HashBytes disagree for %d bytes", n)
+		}
+	}
+
+	// Another key places keys elsewhere
+	other := &SipHasher{Key: [16]byte{1}}
+	if other.Hash("user:42") == h.Hash("user:42") {
+		t.Error("Expected different keys to hash differently")
+	}
+	if name := hasherName(h); name != "Custom" {
+		t.Errorf("Expected SipHasher to be recorded as Custom, got %q", name)
+	}
+}
+
+func TestWithHashSeed(t *testing.T) {
+	hashers := []HashFunction{
+		&FNVHasher{}, &XXHasher{}, &Murmur3Hasher{}, &CRC32Hasher{},
+		&SHA256Hasher{}, &MD5Hasher{}, &SHA1Hasher{},
+	}
+	for _, hasher := range hashers {
+		t.Run(hasherName(hasher), func(t *testing.T) {
+			unseeded := newSeedRing(t, WithHashFunction(hasher))
+			seeded := newSeedRing(t, WithHashFunction(hasher), WithHashSeed(42))
+			again := newSeedRing(t, WithHashSeed(42), WithHashFunction(hasher))
+
+			if seeded.Fingerprint() == unseeded.Fingerprint() {
+				t.Error("Expected the seed to change placement")
+			}
+			if seeded.Fingerprint() != again.Fingerprint() {
+				t.Error("Expected the same seed to place keys alike in any option order")
+			}
+			if hasher.(seedable).seed() != 0 {
+				t.Error("Expected the caller's hasher to be left unseeded")
+			}
+			if info := seeded.GetRingInfo(); info["hash_function"] != "Custom" {
+				t.Errorf("Expected a seeded ring to report Custom, got %v", info["hash_function"])
+			}
+
+			// The seed isn't in snapshots, so it has to be passed again
+			if _, err := RestoreHashRing(seeded.Snapshot()); err == nil {
+				t.Error("Expected restoring without the seed to fail")
+			}
+			restored, err := RestoreHashRing(seeded.Snapshot(), WithHashFunction(hasher), WithHashSeed(42))
+			if err != nil {
+				t.Fatalf("Failed to restore ring: %v", err)
+			}
+			if restored.Fingerprint() != seeded.Fingerprint() {
+				t.Error("Expected the restored ring to route identically")
+			}
+		})
+	}
+
+	// The seed carries over when Rebuild switches hash functions
+	ring := newSeedRing(t, WithHashSeed(7))
+	rebuilt, _, err := ring.Rebuild(WithHashFunction(&XXHasher{}))
+	if err != nil {
+		t.Fatalf("Failed to rebuild ring: %v", err)
+	}
+	if rebuilt.Fingerprint() != newSeedRing(t, WithHashFunction(&XXHasher{Seed: 7})).Fingerprint() {
+		t.Error("Expected the rebuilt ring to stay seeded")
+	}
+
+	if _, err := NewHashRing(10, WithHashFunction(&SipHasher{}), WithHashSeed(1)); err == nil {
+		t.Error("Expected an error seeding a hasher that doesn't support it")
+	}
+}
+
+// newSeedRing returns a ring of three nodes built with opts
+func newSeedRing(t *testing.T, opts ...Option) *HashRing {
+	t.Helper()
+	ring, err := NewHashRing(20, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	return ring
+}
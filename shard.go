@@ -0,0 +1,75 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultShardCount is the number of shards used when WithShardCount is not
+// given. Shard IDs are durable labels, so pick the count explicitly for data
+// that outlives the process: changing it relabels every key.
+const DefaultShardCount = 1024
+
+// ErrInvalidShard is returned for shard IDs outside [0, ShardCount())
+var ErrInvalidShard = errors.New("shard out of range")
+
+// WithShardCount sets the fixed number of shards used by ShardOf
+func WithShardCount(count int) Option {
+	return func(hr *HashRing) {
+		if count > 0 {
+			hr.shardCount = count
+		}
+	}
+}
+
+// ShardCount returns the fixed number of shards
+func (hr *HashRing) ShardCount() int {
+	return hr.shardCount
+}
+
+// ShardOf returns the durable shard ID of a key. It depends only on the key,
+// the hash function and the shard count, never on membership, so data can be
+// labeled with it and survive node churn. Shards are placed on nodes through
+// the ring with GetShardNode.
+func (hr *HashRing) ShardOf(key string) int {
+	return int(hr.hash(key) % uint64(hr.shardCount))
+}
+
+// shardKey is the ring key a shard is placed by
+func shardKey(shard int) string {
+	return "shard:" + strconv.Itoa(shard)
+}
+
+// GetShardNode returns the node currently responsible for a shard
+func (hr *HashRing) GetShardNode(shard int) (*Node, error) {
+	if shard < 0 || shard >= hr.shardCount {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidShard, shard)
+	}
+
+	return hr.GetNode(shardKey(shard))
+}
+
+// ShardsOf returns the shards currently placed on a node, in ascending order
+func (hr *HashRing) ShardsOf(nodeID string) ([]int, error) {
+	if strings.TrimSpace(nodeID) == "" {
+		return nil, ErrInvalidNodeID
+	}
+
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	if _, exists := hr.nodes[nodeID]; !exists {
+		return nil, ErrNodeNotFound
+	}
+
+	var shards []int
+	for shard := 0; shard < hr.shardCount; shard++ {
+		if hr.virtualNodes[hr.search(hr.hash(shardKey(shard)))].Node.ID == nodeID {
+			shards = append(shards, shard)
+		}
+	}
+
+	return shards, nil
+}
@@ -0,0 +1,79 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestShardOf(t *testing.T) {
+	ring, err := NewHashRing(10, WithShardCount(16))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if ring.ShardCount() != 16 {
+		t.Errorf("Expected 16 shards, got %d", ring.ShardCount())
+	}
+
+	// Shard IDs do not depend on membership
+	before := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key_%d", i)
+		shard := ring.ShardOf(key)
+		if shard < 0 || shard >= 16 {
+			t.Fatalf("Shard %d out of range", shard)
+		}
+		before[key] = shard
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+	for key, shard := range before {
+		if ring.ShardOf(key) != shard {
+			t.Errorf("Shard of %s changed after membership change", key)
+		}
+	}
+
+	defaultRing, _ := NewHashRing(10)
+	if defaultRing.ShardCount() != DefaultShardCount {
+		t.Errorf("Expected default shard count %d, got %d", DefaultShardCount, defaultRing.ShardCount())
+	}
+}
+
+func TestShardPlacement(t *testing.T) {
+	ring, err := NewHashRing(10, WithShardCount(32))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	ring.AddNode(&Node{ID: "node1", Host: "localhost", Port: 8080})
+	ring.AddNode(&Node{ID: "node2", Host: "localhost", Port: 8081})
+
+	total := 0
+	for _, id := range []string{"node1", "node2"} {
+		shards, err := ring.ShardsOf(id)
+		if err != nil {
+			t.Fatalf("Failed to get shards of %s: %v", id, err)
+		}
+		for _, shard := range shards {
+			node, err := ring.GetShardNode(shard)
+			if err != nil {
+				t.Fatalf("Failed to get node for shard %d: %v", shard, err)
+			}
+			if node.ID != id {
+				t.Errorf("Shard %d listed for %s but placed on %s", shard, id, node.ID)
+			}
+		}
+		total += len(shards)
+	}
+	if total != 32 {
+		t.Errorf("Expected all 32 shards to be placed, got %d", total)
+	}
+
+	if _, err := ring.GetShardNode(32); !errors.Is(err, ErrInvalidShard) {
+		t.Errorf("Expected ErrInvalidShard, got %v", err)
+	}
+	if _, err := ring.ShardsOf("nonexistent"); err != ErrNodeNotFound {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+}
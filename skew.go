@@ -0,0 +1,66 @@
+package consistenthashing
+
+import (
+	"errors"
+	"sort"
+)
+
+// TrafficSkew compares a node's share of the hash space with its share of
+// the lookups it actually served
+type TrafficSkew struct {
+	NodeID    string
+	Ownership float64 // Share of the hash space
+	Traffic   float64 // Share of the observed lookups
+	Ratio     float64 // Traffic over Ownership; 1 means traffic follows the hash space
+	Divergent bool    // Ratio is beyond the threshold in either direction
+}
+
+// CompareTrafficSkew compares the analytic ownership of each node with the
+// lookup counters kept by WithKeySampling. A node whose traffic share is more
+// than threshold times its hash space share, or less than its inverse, is
+// marked Divergent: its keys are hotter or colder than average, which points
+// at skewed data rather than a badly balanced ring, since the ring itself is
+// what Ownership measures. Results are sorted by Ratio, hottest first.
+// Counters span membership changes, so reset them with ResetKeySamples after
+// one for an accurate comparison.
+func (hr *HashRing) CompareTrafficSkew(threshold float64) ([]TrafficSkew, error) {
+	if threshold < 1 {
+		return nil, errors.New("skew threshold must be at least 1")
+	}
+
+	counts, err := hr.LookupCounts()
+	if err != nil {
+		return nil, err
+	}
+	ownership := hr.Ownership()
+	if len(ownership) == 0 {
+		return nil, ErrEmptyRing
+	}
+
+	var total uint64
+	for id := range ownership {
+		total += counts[id]
+	}
+	if total == 0 {
+		return nil, errors.New("no lookups recorded")
+	}
+
+	skews := make([]TrafficSkew, 0, len(ownership))
+	for id, share := range ownership {
+		traffic := float64(counts[id]) / float64(total)
+		skew := TrafficSkew{NodeID: id, Ownership: share, Traffic: traffic}
+		if share > 0 {
+			skew.Ratio = traffic / share
+		}
+		skew.Divergent = skew.Ratio > threshold || skew.Ratio*threshold < 1
+		skews = append(skews, skew)
+	}
+
+	sort.Slice(skews, func(i, j int) bool {
+		if skews[i].Ratio != skews[j].Ratio {
+			return skews[i].Ratio > skews[j].Ratio
+		}
+		return skews[i].NodeID < skews[j].NodeID
+	})
+	return skews, nil
+}
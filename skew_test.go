@@ -0,0 +1,69 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestCompareTrafficSkew(t *testing.T) {
+	ring, err := NewHashRing(100, WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if _, err := ring.CompareTrafficSkew(1.5); err != ErrSamplingDisabled {
+		t.Errorf("Expected ErrSamplingDisabled, got %v", err)
+	}
+
+	ring, err = NewHashRing(100, WithHashFunction(&SHA256Hasher{}), WithKeySampling(10))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	if _, err := ring.CompareTrafficSkew(1.5); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	if _, err := ring.CompareTrafficSkew(1.5); err == nil {
+		t.Error("Expected an error before any lookups")
+	}
+	if _, err := ring.CompareTrafficSkew(0.5); err == nil {
+		t.Error("Expected an error for a threshold below 1")
+	}
+
+	// Uniform traffic follows the hash space
+	for i := 0; i < 20000; i++ {
+		ring.GetNode(fmt.Sprintf("key%d", i))
+	}
+	skews, err := ring.CompareTrafficSkew(1.5)
+	if err != nil {
+		t.Fatalf("Failed to compare skew: %v", err)
+	}
+	if len(skews) != 4 {
+		t.Fatalf("Expected 4 nodes, got %d", len(skews))
+	}
+	for _, skew := range skews {
+		if skew.Divergent || math.Abs(skew.Ratio-1) > 0.1 {
+			t.Errorf("Expected uniform traffic to follow ownership, got %+v", skew)
+		}
+	}
+
+	// A hot key makes its owner stand out
+	hot, _ := ring.GetNode("hot")
+	for i := 0; i < 20000; i++ {
+		ring.GetNode("hot")
+	}
+	skews, err = ring.CompareTrafficSkew(1.5)
+	if err != nil {
+		t.Fatalf("Failed to compare skew: %v", err)
+	}
+	if skews[0].NodeID != hot.ID || !skews[0].Divergent {
+		t.Errorf("Expected %s to lead as divergent, got %+v", hot.ID, skews[0])
+	}
+	for i := 1; i < len(skews); i++ {
+		if skews[i].Ratio > skews[i-1].Ratio {
+			t.Errorf("Expected skews sorted by ratio, got %+v", skews)
+		}
+	}
+}
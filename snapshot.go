@@ -0,0 +1,207 @@
+package consistenthashing
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+)
+
+// ErrUnknownHasher is returned when restoring a snapshot taken with a custom
+// hash function without supplying it through WithHashFunction
+var ErrUnknownHasher = errors.New("snapshot uses a custom hash function")
+
+// Codec encodes and decodes ring snapshots. Implementations for other formats,
+// such as protobuf or msgpack, can be plugged in with WithCodec.
+type Codec interface {
+	Encode(w io.Writer, v interface{}) error
+	Decode(r io.Reader, v interface{}) error
+}
+
+// JSONCodec encodes snapshots as JSON. It is the default codec.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (JSONCodec) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// GobCodec encodes snapshots with encoding/gob
+type GobCodec struct{}
+
+func (GobCodec) Encode(w io.Writer, v interface{}) error {
+	return gob.NewEncoder(w).Encode(v)
+}
+
+func (GobCodec) Decode(r io.Reader, v interface{}) error {
+	return gob.NewDecoder(r).Decode(v)
+}
+
+// WithCodec sets the codec used by WriteSnapshot
+func WithCodec(codec Codec) Option {
+	return func(hr *HashRing) {
+		if codec != nil {
+			hr.codec = codec
+		}
+	}
+}
+
+// Snapshot is the serializable state of a ring. Virtual node positions are
+// derived from the nodes, so they are not stored.
+type Snapshot struct {
+	VirtualReplicas int            `json:"virtual_replicas"`
+	HashFunction    string         `json:"hash_function"`
+	ShardCount      int            `json:"shard_count"`
+	Generation      uint64         `json:"generation"`
+	Classes         map[string]int `json:"classes,omitempty"`           // Capacity class weights
+	MaxVirtualNodes int            `json:"max_virtual_nodes,omitempty"` // Cap on the total virtual node count
+	Nodes           []Node         `json:"nodes"`                       // Sorted by ID
+}
+
+// Snapshot captures the ring's current state
+func (hr *HashRing) Snapshot() Snapshot {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	nodes := make([]Node, 0, len(hr.nodes))
+	for _, node := range hr.nodes {
+		nodes = append(nodes, node.clone())
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	return Snapshot{
+		VirtualReplicas: hr.virtualReplicas,
+		HashFunction:    hasherName(hr.hasher),
+		ShardCount:      hr.shardCount,
+		Generation:      hr.generation,
+		Classes:         maps.Clone(hr.classes),
+		MaxVirtualNodes: hr.maxVirtualNodes,
+		Nodes:           nodes,
+	}
+}
+
+// WriteSnapshot encodes a snapshot of the ring to w with the ring's codec
+func (hr *HashRing) WriteSnapshot(w io.Writer) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	return hr.codec.Encode(w, hr.Snapshot())
+}
+
+// ReadSnapshot decodes a snapshot from r. A nil codec means JSONCodec.
+func ReadSnapshot(r io.Reader, codec Codec) (Snapshot, error) {
+	if r == nil {
+		return Snapshot{}, errors.New("reader cannot be nil")
+	}
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	var s Snapshot
+	if err := codec.Decode(r, &s); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// RestoreHashRing creates a ring from a snapshot. The snapshot's hash function,
+// shard count, capacity classes and virtual node cap are applied before opts; a snapshot of a
+// ring with a custom hash function needs it passed again with WithHashFunction.
+func RestoreHashRing(s Snapshot, opts ...Option) (*HashRing, error) {
+	hr := new(HashRing)
+	nodes, err := hr.prepareRestore(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	hr.finishRestore(nodes, s.Generation)
+	return hr, nil
+}
+
+// restore turns hr into a ring restored from a snapshot, as RestoreHashRing
+// does, and leaves hr unchanged if the snapshot can't be restored. It must
+// not be in use.
+func (hr *HashRing) restore(s Snapshot, opts []Option) error {
+	// Preparing resets the ring, so validate on an empty scratch ring first.
+	// Nothing is placed until the snapshot is known to be good.
+	if _, err := new(HashRing).prepareRestore(s, opts); err != nil {
+		return err
+	}
+	nodes, err := hr.prepareRestore(s, opts)
+	if err != nil {
+		return err
+	}
+	hr.finishRestore(nodes, s.Generation)
+	return nil
+}
+
+// prepareRestore resets hr with the snapshot's settings and opts, and checks
+// the hash function and every node without placing anything. It returns
+// copies of the nodes to restore; of nodes sharing an ID the first is kept,
+// as AddNode would.
+func (hr *HashRing) prepareRestore(s Snapshot, opts []Option) ([]*Node, error) {
+	base := []Option{
+		WithShardCount(s.ShardCount),
+		WithCapacityClasses(s.Classes),
+		WithMaxVirtualNodes(s.MaxVirtualNodes),
+	}
+	if hasher := hasherByName(s.HashFunction); hasher != nil {
+		base = append(base, WithHashFunction(hasher))
+	}
+
+	if err := hr.reset(s.VirtualReplicas, append(base, opts...)); err != nil {
+		return nil, err
+	}
+
+	// Restoring with a different hash function would silently remap keys
+	if name := hasherName(hr.hasher); name != s.HashFunction {
+		if s.HashFunction == "Custom" {
+			return nil, ErrUnknownHasher
+		}
+		return nil, fmt.Errorf("snapshot uses %s, ring uses %s", s.HashFunction, name)
+	}
+
+	nodes := make([]*Node, 0, len(s.Nodes))
+	seen := make(map[string]bool, len(s.Nodes))
+	for i := range s.Nodes {
+		node := s.Nodes[i].clone()
+		if seen[node.ID] {
+			continue
+		}
+		if err := node.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to restore node %s: invalid node: %w", node.ID, err)
+		}
+		if _, err := hr.curveCount(&node); err != nil {
+			return nil, fmt.Errorf("failed to restore node %s: %w", node.ID, err)
+		}
+		seen[node.ID] = true
+		nodes = append(nodes, &node)
+	}
+	return nodes, nil
+}
+
+// finishRestore places the nodes checked by prepareRestore in one pass and
+// sets the snapshot's generation
+func (hr *HashRing) finishRestore(nodes []*Node, generation uint64) {
+	for _, node := range nodes {
+		hr.nodes[node.ID] = node
+	}
+	if !hr.rescaleLocked() {
+		hr.placeMembersLocked(nodes)
+	}
+
+	hr.generation = generation
+	if hr.history != nil {
+		// The entry recorded by reset was for an empty ring that never
+		// existed on the source
+		hr.history.entries = hr.history.entries[:0]
+		hr.recordHistoryLocked()
+	}
+}
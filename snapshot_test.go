@@ -0,0 +1,114 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// constantHasher is a custom hash function unknown to snapshots
+type constantHasher struct{}
+
+func (constantHasher) Hash(key string) uint64 { return uint64(len(key)) }
+
+func (constantHasher) HashBytes(key []byte) uint64 { return uint64(len(key)) }
+
+func newSnapshotTestRing(t *testing.T, opts ...Option) *HashRing {
+	t.Helper()
+	ring, err := NewHashRing(20, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i, Weight: i + 1, Zone: "zone-a"})
+	}
+	return ring
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	for _, codec := range []Codec{JSONCodec{}, GobCodec{}} {
+		t.Run(fmt.Sprintf("%T", codec), func(t *testing.T) {
+			ring := newSnapshotTestRing(t, WithCodec(codec), WithHashFunction(&SHA256Hasher{}), WithShardCount(64))
+
+			var buf bytes.Buffer
+			if err := ring.WriteSnapshot(&buf); err != nil {
+				t.Fatalf("Failed to write snapshot: %v", err)
+			}
+			snapshot, err := ReadSnapshot(&buf, codec)
+			if err != nil {
+				t.Fatalf("Failed to read snapshot: %v", err)
+			}
+
+			restored, err := RestoreHashRing(snapshot)
+			if err != nil {
+				t.Fatalf("Failed to restore ring: %v", err)
+			}
+			if restored.Generation() != ring.Generation() || restored.ShardCount() != 64 {
+				t.Errorf("Ring settings not restored: generation %d, shards %d", restored.Generation(), restored.ShardCount())
+			}
+			if restored.VirtualSize() != ring.VirtualSize() {
+				t.Errorf("Expected %d virtual nodes, got %d", ring.VirtualSize(), restored.VirtualSize())
+			}
+
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("key%d", i)
+				expected, _ := ring.GetNode(key)
+				actual, _ := restored.GetNode(key)
+				if !actual.Equal(expected) {
+					t.Fatalf("Key %s: restored ring maps to %+v, expected %+v", key, actual, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreHashRingWithVirtualNodeCap(t *testing.T) {
+	ring := newSnapshotTestRing(t, WithMaxVirtualNodes(50))
+	snapshot := ring.Snapshot()
+	snapshot.Nodes = append(snapshot.Nodes, snapshot.Nodes[0])
+
+	restored, err := RestoreHashRing(snapshot)
+	if err != nil {
+		t.Fatalf("Failed to restore ring: %v", err)
+	}
+	if restored.Size() != ring.Size() || restored.VirtualSize() != ring.VirtualSize() {
+		t.Fatalf("Expected %d nodes and %d virtual nodes, got %d and %d",
+			ring.Size(), ring.VirtualSize(), restored.Size(), restored.VirtualSize())
+	}
+	if err := restored.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+	for i := 0; i < 500; i++ {
+		key := fmt.Sprintf("key%d", i)
+		expected, _ := ring.GetNode(key)
+		actual, _ := restored.GetNode(key)
+		if !actual.Equal(expected) {
+			t.Fatalf("Key %s: restored ring maps to %+v, expected %+v", key, actual, expected)
+		}
+	}
+}
+
+func TestRestoreHashRingHasherChecks(t *testing.T) {
+	custom := newSnapshotTestRing(t, WithHashFunction(constantHasher{})).Snapshot()
+	if _, err := RestoreHashRing(custom); !errors.Is(err, ErrUnknownHasher) {
+		t.Errorf("Expected ErrUnknownHasher, got %v", err)
+	}
+	if _, err := RestoreHashRing(custom, WithHashFunction(constantHasher{})); err != nil {
+		t.Errorf("Failed to restore with custom hasher: %v", err)
+	}
+
+	fnv := newSnapshotTestRing(t).Snapshot()
+	if _, err := RestoreHashRing(fnv, WithHashFunction(&SHA256Hasher{})); err == nil {
+		t.Error("Expected error when restoring with a different hash function")
+	}
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	if _, err := ReadSnapshot(nil, nil); err == nil {
+		t.Error("Expected error for nil reader")
+	}
+	if _, err := ReadSnapshot(bytes.NewBufferString("not json"), nil); err == nil {
+		t.Error("Expected error for invalid snapshot")
+	}
+}
@@ -0,0 +1,105 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNodeState(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+
+	if state, err := ring.NodeState("node0"); err != nil || state != NodeActive {
+		t.Errorf("Expected new nodes to be active, got %v, %v", state, err)
+	}
+
+	if err := ring.SetNodeState("node0", NodeDown); err != nil {
+		t.Fatalf("Failed to set state: %v", err)
+	}
+	if state, _ := ring.NodeState("node0"); state != NodeDown {
+		t.Errorf("Expected down, got %v", state)
+	}
+
+	if err := ring.SetNodeState("missing", NodeDown); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if err := ring.SetNodeState("node0", NodeState(7)); !errors.Is(err, ErrInvalidNodeState) {
+		t.Errorf("Expected ErrInvalidNodeState, got %v", err)
+	}
+
+	// State does not survive the node leaving and rejoining
+	ring.RemoveNode("node0")
+	ring.AddNode(&Node{ID: "node0", Host: "localhost", Port: 8080})
+	if state, _ := ring.NodeState("node0"); state != NodeActive {
+		t.Errorf("Expected a rejoined node to be active, got %v", state)
+	}
+
+	if NodeDraining.String() != "draining" || NodeState(9).String() != "NodeState(9)" {
+		t.Errorf("Unexpected state names %q, %q", NodeDraining, NodeState(9))
+	}
+}
+
+func TestCanServe(t *testing.T) {
+	ring, _ := NewHashRing(50, WithReplicationFactor(3))
+	if ring.CanServe("key", 1) {
+		t.Error("Expected an empty ring to refuse")
+	}
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	key := "user:42"
+	replicas, _ := ring.GetNodes(key, 3)
+	if !ring.CanServe(key, 3) || ring.CanServe(key, 4) {
+		t.Error("Expected exactly three active replicas")
+	}
+
+	ring.SetNodeState(replicas[0].ID, NodeDown)
+	ring.SetNodeState(replicas[1].ID, NodeDraining)
+	if !ring.CanServe(key, 1) || ring.CanServe(key, 2) {
+		t.Error("Expected one active replica")
+	}
+
+	// Nodes outside the replica set do not help
+	for _, node := range ring.GetAllNodes() {
+		if node.ID != replicas[2].ID {
+			ring.SetNodeState(node.ID, NodeDown)
+		}
+	}
+	ring.SetNodeState(replicas[2].ID, NodeDown)
+	if ring.CanServe(key, 1) {
+		t.Error("Expected no active replicas")
+	}
+	ring.SetNodeState(replicas[0].ID, NodeActive)
+	if !ring.CanServe(key, 1) {
+		t.Error("Expected the reactivated replica to count")
+	}
+
+	if !ring.CanServe(key, 0) {
+		t.Error("Expected a zero requirement to always pass")
+	}
+}
+
+func TestSetMembersDropsState(t *testing.T) {
+	ring, _ := NewHashRing(10)
+	a := &Node{ID: "a", Host: "localhost", Port: 8080}
+	b := &Node{ID: "b", Host: "localhost", Port: 8081}
+	ring.SetMembers([]*Node{a, b})
+	ring.SetNodeState("a", NodeDraining)
+	ring.SetNodeState("b", NodeDown)
+
+	// An updated node keeps its state; a removed one loses it
+	ring.SetMembers([]*Node{{ID: "a", Host: "localhost", Port: 8080, Weight: 2}})
+	ring.SetMembers([]*Node{{ID: "a", Host: "localhost", Port: 8080, Weight: 2}, b})
+
+	if state, _ := ring.NodeState("a"); state != NodeDraining {
+		t.Errorf("Expected a to stay draining, got %v", state)
+	}
+	if state, _ := ring.NodeState("b"); state != NodeActive {
+		t.Errorf("Expected b to be active after rejoining, got %v", state)
+	}
+}
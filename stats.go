@@ -0,0 +1,50 @@
+package consistenthashing
+
+import "math"
+
+// RingStats is a typed summary of the ring, the fields of GetRingInfo plus
+// the spread of ownership across nodes
+type RingStats struct {
+	PhysicalNodes   int     `json:"physical_nodes"`
+	VirtualNodes    int     `json:"virtual_nodes"`
+	Replicas        int     `json:"replicas"` // Virtual replicas per unit of weight
+	HashFunction    string  `json:"hash_function"`
+	OwnershipStdDev float64 `json:"ownership_std_dev"` // Standard deviation of the nodes' hash space shares
+	MaxOwnership    float64 `json:"max_ownership"`
+	MinOwnership    float64 `json:"min_ownership"`
+}
+
+// Stats returns the ring's statistics, read atomically. Ownership fields are
+// zero for an empty ring.
+func (hr *HashRing) Stats() RingStats {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	stats := RingStats{
+		PhysicalNodes: len(hr.nodes),
+		VirtualNodes:  len(hr.virtualNodes),
+		Replicas:      hr.virtualReplicas,
+		HashFunction:  hasherName(hr.hasher),
+	}
+	if len(hr.nodes) == 0 {
+		return stats
+	}
+
+	shares := hr.ownershipLocked()
+	mean := 1 / float64(len(shares))
+	variance := 0.0
+	first := true
+	for _, share := range shares {
+		variance += (share - mean) * (share - mean)
+		if first || share > stats.MaxOwnership {
+			stats.MaxOwnership = share
+		}
+		if first || share < stats.MinOwnership {
+			stats.MinOwnership = share
+		}
+		first = false
+	}
+	stats.OwnershipStdDev = math.Sqrt(variance / float64(len(shares)))
+
+	return stats
+}
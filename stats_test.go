@@ -0,0 +1,54 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	ring, err := NewHashRing(5, WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	stats := ring.Stats()
+	if stats != (RingStats{Replicas: 5, HashFunction: "SHA-256"}) {
+		t.Errorf("Unexpected stats for an empty ring: %+v", stats)
+	}
+
+	ring.AddNode(&Node{ID: "solo", Host: "localhost", Port: 8080})
+	stats = ring.Stats()
+	if stats.MaxOwnership != 1 || stats.MinOwnership != 1 || stats.OwnershipStdDev != 0 {
+		t.Errorf("Expected a single node to own everything, got %+v", stats)
+	}
+
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 9000 + i})
+	}
+	stats = ring.Stats()
+	if stats.PhysicalNodes != 4 || stats.VirtualNodes != 20 {
+		t.Errorf("Expected 4 nodes and 20 virtual nodes, got %+v", stats)
+	}
+
+	// The spread matches the ownership shares
+	shares := ring.Ownership()
+	high, low, variance := 0.0, 1.0, 0.0
+	for _, share := range shares {
+		high, low = max(high, share), min(low, share)
+		variance += (share - 0.25) * (share - 0.25) / 4
+	}
+	if stats.MaxOwnership != high || stats.MinOwnership != low {
+		t.Errorf("Expected ownership between %f and %f, got %+v", low, high, stats)
+	}
+	if math.Abs(stats.OwnershipStdDev-math.Sqrt(variance)) > 1e-12 || stats.OwnershipStdDev == 0 {
+		t.Errorf("Expected standard deviation %f, got %f", math.Sqrt(variance), stats.OwnershipStdDev)
+	}
+
+	// GetRingInfo reports the same values
+	info := ring.GetRingInfo()
+	if info["physical_nodes"] != stats.PhysicalNodes || info["virtual_nodes"] != stats.VirtualNodes ||
+		info["virtual_replicas"] != stats.Replicas || info["hash_function"] != stats.HashFunction {
+		t.Errorf("Expected GetRingInfo to match %+v, got %v", stats, info)
+	}
+}
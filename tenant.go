@@ -0,0 +1,182 @@
+package consistenthashing
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// DefaultPool is the node pool serving tenants without a dedicated pool
+const DefaultPool = "default"
+
+// ErrPoolNotFound is returned when a tenant ring has no pool with the given name
+var ErrPoolNotFound = errors.New("pool not found")
+
+// TenantPrefix returns a tenant function that treats everything before the
+// first sep as the tenant, so keys like "acme:user:42" belong to "acme". Keys
+// without sep have no tenant and are served by the default pool.
+func TenantPrefix(sep string) func(key string) string {
+	return func(key string) string {
+		tenant, _, found := strings.Cut(key, sep)
+		if !found {
+			return ""
+		}
+		return tenant
+	}
+}
+
+// TenantRing is a two-level ring for tenant isolation. Each key is first
+// mapped to its tenant's node pool and then hashed onto that pool's own ring.
+// Tenants share the default pool until they are assigned a dedicated one, so
+// a noisy tenant can be moved without changing its key format, and moving it
+// leaves every other tenant's keys in place.
+type TenantRing struct {
+	tenantOf        func(key string) string
+	pools           map[string]*HashRing
+	assignments     map[string]string // Tenant to dedicated pool
+	virtualReplicas int
+	opts            []Option
+	mu              sync.RWMutex
+}
+
+// NewTenantRing creates a tenant ring. tenantOf extracts the tenant from a
+// key, and opts configure every pool's ring.
+func NewTenantRing(virtualReplicas int, tenantOf func(key string) string, opts ...Option) (*TenantRing, error) {
+	if tenantOf == nil {
+		return nil, errors.New("tenant function cannot be nil")
+	}
+
+	// Creating the default pool up front validates the ring settings
+	pool, err := NewHashRing(virtualReplicas, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TenantRing{
+		tenantOf:        tenantOf,
+		pools:           map[string]*HashRing{DefaultPool: pool},
+		assignments:     make(map[string]string),
+		virtualReplicas: virtualReplicas,
+		opts:            opts,
+	}, nil
+}
+
+// AddNode adds a node to a pool, creating the pool if needed
+func (t *TenantRing) AddNode(pool string, node *Node) error {
+	if strings.TrimSpace(pool) == "" {
+		return errors.New("pool name cannot be empty")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ring, exists := t.pools[pool]
+	if !exists {
+		var err error
+		if ring, err = NewHashRing(t.virtualReplicas, t.opts...); err != nil {
+			return err
+		}
+	}
+	if err := ring.AddNode(node); err != nil {
+		return err
+	}
+
+	t.pools[pool] = ring
+	return nil
+}
+
+// RemoveNode removes a node from a pool
+func (t *TenantRing) RemoveNode(pool, nodeID string) error {
+	t.mu.RLock()
+	ring, exists := t.pools[pool]
+	t.mu.RUnlock()
+
+	if !exists {
+		return ErrPoolNotFound
+	}
+	return ring.RemoveNode(nodeID)
+}
+
+// AssignTenant moves a tenant to a dedicated pool. Only that tenant's keys
+// move; the pool must already exist.
+func (t *TenantRing) AssignTenant(tenant, pool string) error {
+	if tenant == "" {
+		return errors.New("tenant cannot be empty")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.pools[pool]; !exists {
+		return ErrPoolNotFound
+	}
+
+	if pool == DefaultPool {
+		delete(t.assignments, tenant)
+	} else {
+		t.assignments[tenant] = pool
+	}
+	return nil
+}
+
+// UnassignTenant moves a tenant back to the default pool
+func (t *TenantRing) UnassignTenant(tenant string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.assignments, tenant)
+}
+
+// PoolOf returns the name of the pool serving a tenant
+func (t *TenantRing) PoolOf(tenant string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.poolOfLocked(tenant)
+}
+
+func (t *TenantRing) poolOfLocked(tenant string) string {
+	if pool, exists := t.assignments[tenant]; exists {
+		return pool
+	}
+	return DefaultPool
+}
+
+// Pool returns the ring of a pool
+func (t *TenantRing) Pool(name string) (*HashRing, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ring, exists := t.pools[name]
+	if !exists {
+		return nil, ErrPoolNotFound
+	}
+	return ring, nil
+}
+
+// ringFor returns the pool ring responsible for a key
+func (t *TenantRing) ringFor(key string) *HashRing {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.pools[t.poolOfLocked(t.tenantOf(key))]
+}
+
+// GetNode returns the node responsible for a key within its tenant's pool
+func (t *TenantRing) GetNode(key string) (*Node, error) {
+	return t.ringFor(key).GetNode(key)
+}
+
+// GetNodes returns the count nodes responsible for a key within its tenant's
+// pool
+func (t *TenantRing) GetNodes(key string, count int) ([]*Node, error) {
+	return t.ringFor(key).GetNodes(key, count)
+}
+
+// Pools returns the number of pools, including the default pool
+func (t *TenantRing) Pools() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.pools)
+}
@@ -0,0 +1,111 @@
+package consistenthashing
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTenantPrefix(t *testing.T) {
+	tenantOf := TenantPrefix(":")
+	if tenant := tenantOf("acme:user:42"); tenant != "acme" {
+		t.Errorf("Expected tenant acme, got %q", tenant)
+	}
+	if tenant := tenantOf("no-tenant"); tenant != "" {
+		t.Errorf("Expected no tenant, got %q", tenant)
+	}
+}
+
+func TestTenantRing(t *testing.T) {
+	if _, err := NewTenantRing(10, nil); err == nil {
+		t.Error("Expected error for nil tenant function")
+	}
+
+	ring, err := NewTenantRing(50, TenantPrefix(":"))
+	if err != nil {
+		t.Fatalf("Failed to create tenant ring: %v", err)
+	}
+
+	if _, err := ring.GetNode("acme:key"); err != ErrEmptyRing {
+		t.Errorf("Expected ErrEmptyRing, got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := ring.AddNode(DefaultPool, &Node{ID: fmt.Sprintf("shared%d", i), Host: "localhost", Port: 8080 + i}); err != nil {
+			t.Fatalf("Failed to add shared node: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := ring.AddNode("dedicated", &Node{ID: fmt.Sprintf("dedicated%d", i), Host: "localhost", Port: 9080 + i}); err != nil {
+			t.Fatalf("Failed to add dedicated node: %v", err)
+		}
+	}
+	if ring.Pools() != 2 {
+		t.Errorf("Expected 2 pools, got %d", ring.Pools())
+	}
+
+	if err := ring.AssignTenant("noisy", "missing"); err != ErrPoolNotFound {
+		t.Errorf("Expected ErrPoolNotFound, got %v", err)
+	}
+
+	keys := make([]string, 0, 200)
+	for i := 0; i < 100; i++ {
+		keys = append(keys, fmt.Sprintf("noisy:item%d", i), fmt.Sprintf("quiet:item%d", i))
+	}
+	before := make(map[string]string, len(keys))
+	for _, key := range keys {
+		node, err := ring.GetNode(key)
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		before[key] = node.ID
+	}
+
+	if err := ring.AssignTenant("noisy", "dedicated"); err != nil {
+		t.Fatalf("Failed to assign tenant: %v", err)
+	}
+	if pool := ring.PoolOf("noisy"); pool != "dedicated" {
+		t.Errorf("Expected noisy tenant in dedicated pool, got %s", pool)
+	}
+
+	dedicated, err := ring.Pool("dedicated")
+	if err != nil {
+		t.Fatalf("Failed to get pool: %v", err)
+	}
+	for _, key := range keys {
+		node, _ := ring.GetNode(key)
+		switch ring.PoolOf(TenantPrefix(":")(key)) {
+		case "dedicated":
+			if !dedicated.HasNode(node.ID) {
+				t.Errorf("Key %s served by %s outside its dedicated pool", key, node.ID)
+			}
+		default:
+			// Other tenants must not move
+			if node.ID != before[key] {
+				t.Errorf("Key %s moved from %s to %s", key, before[key], node.ID)
+			}
+		}
+	}
+
+	nodes, err := ring.GetNodes("noisy:item1", 3)
+	if err != nil {
+		t.Fatalf("Failed to get nodes: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Errorf("Expected replicas limited to the 2 dedicated nodes, got %d", len(nodes))
+	}
+
+	// Moving the tenant back restores its original placement
+	ring.UnassignTenant("noisy")
+	for _, key := range keys {
+		if node, _ := ring.GetNode(key); node.ID != before[key] {
+			t.Errorf("Key %s not restored after unassigning: %s != %s", key, node.ID, before[key])
+		}
+	}
+
+	if err := ring.RemoveNode("missing", "node"); err != ErrPoolNotFound {
+		t.Errorf("Expected ErrPoolNotFound, got %v", err)
+	}
+	if err := ring.RemoveNode("dedicated", "dedicated0"); err != nil {
+		t.Errorf("Failed to remove node: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+// Package testutil provides helpers for testing code built on consistent
+// hashing rings, both in this module and in downstream projects.
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// ChurnConfig controls a randomized churn run
+type ChurnConfig struct {
+	Seed     int64 // Seed for the operation sequence, so failures are reproducible
+	Steps    int   // Number of membership changes to apply
+	Readers  int   // Number of goroutines doing concurrent lookups
+	MaxNodes int   // Upper bound on ring size
+	Keys     int   // Number of keys used to measure churn
+}
+
+// withDefaults fills in zero fields with sensible values
+func (c ChurnConfig) withDefaults() ChurnConfig {
+	if c.Steps <= 0 {
+		c.Steps = 100
+	}
+	if c.Readers <= 0 {
+		c.Readers = 4
+	}
+	if c.MaxNodes <= 0 {
+		c.MaxNodes = 16
+	}
+	if c.Keys <= 0 {
+		c.Keys = 1000
+	}
+	return c
+}
+
+// Churn applies a seeded sequence of node additions, removals and weight
+// changes to ring while readers perform lookups concurrently. It fails the
+// test if a lookup returns a nil node, if the ring generation ever goes
+// backwards, or if a change moves keys that did not involve the changed node.
+func Churn(t testing.TB, ring *consistenthashing.HashRing, cfg ChurnConfig) {
+	t.Helper()
+
+	cfg = cfg.withDefaults()
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	keys := make([]string, cfg.Keys)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("churn:%d:%d", cfg.Seed, i)
+	}
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for r := 0; r < cfg.Readers; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			lookupLoop(t, ring, keys, r, &stop)
+		}(r)
+	}
+	// Stop the readers on every path out, including t.Fatalf, so none of
+	// them reports after the test has finished
+	defer func() {
+		stop.Store(true)
+		wg.Wait()
+	}()
+
+	nextID := 0
+	for step := 0; step < cfg.Steps; step++ {
+		nodes := ring.GetAllNodes()
+		before := owners(ring, keys)
+
+		var changed string
+		switch op := rng.Intn(3); {
+		case len(nodes) == 0 || (op == 0 && len(nodes) < cfg.MaxNodes):
+			node := &consistenthashing.Node{
+				ID:     fmt.Sprintf("churn-node-%d", nextID),
+				Host:   "localhost",
+				Port:   10000 + nextID%50000,
+				Weight: rng.Intn(4) + 1,
+			}
+			nextID++
+			if err := ring.AddNode(node); err != nil {
+				t.Fatalf("step %d: failed to add %s: %v", step, node.ID, err)
+			}
+			changed = node.ID
+		case op == 1 && len(nodes) > 1:
+			victim := nodes[rng.Intn(len(nodes))]
+			if err := ring.RemoveNode(victim.ID); err != nil {
+				t.Fatalf("step %d: failed to remove %s: %v", step, victim.ID, err)
+			}
+			changed = victim.ID
+		default:
+			// Change the weight by re-adding a copy of the node
+			target := nodes[rng.Intn(len(nodes))]
+			updated := *target
+			updated.Weight = rng.Intn(4) + 1
+			if err := ring.RemoveNode(target.ID); err != nil {
+				t.Fatalf("step %d: failed to remove %s: %v", step, target.ID, err)
+			}
+			if err := ring.AddNode(&updated); err != nil {
+				t.Fatalf("step %d: failed to re-add %s: %v", step, target.ID, err)
+			}
+			changed = target.ID
+		}
+
+		after := owners(ring, keys)
+		for i, key := range keys {
+			if before[i] != "" && before[i] != after[i] && before[i] != changed && after[i] != changed {
+				t.Errorf("step %d: key %s moved from %s to %s although only %s changed",
+					step, key, before[i], after[i], changed)
+			}
+		}
+
+		if err := ring.ValidateRing(); err != nil {
+			t.Errorf("step %d: ring validation failed: %v", step, err)
+		}
+	}
+}
+
+// lookupLoop performs lookups until stop is set, checking that every lookup
+// returns a node and that the generation never decreases
+func lookupLoop(t testing.TB, ring *consistenthashing.HashRing, keys []string, offset int, stop *atomic.Bool) {
+	var lastGeneration uint64
+	for i := offset; !stop.Load(); i++ {
+		generation := ring.Generation()
+		if generation < lastGeneration {
+			t.Errorf("generation went backwards from %d to %d", lastGeneration, generation)
+			return
+		}
+		lastGeneration = generation
+
+		node, err := ring.GetNode(keys[i%len(keys)])
+		if errors.Is(err, consistenthashing.ErrEmptyRing) {
+			continue
+		}
+		if err != nil {
+			t.Errorf("lookup failed: %v", err)
+			return
+		}
+		if node == nil {
+			t.Error("lookup returned a nil node without an error")
+			return
+		}
+	}
+}
+
+// owners returns the owner ID of each key, or "" when the ring is empty
+func owners(ring *consistenthashing.HashRing, keys []string) []string {
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		if node, err := ring.GetNode(key); err == nil {
+			result[i] = node.ID
+		}
+	}
+	return result
+}
@@ -0,0 +1,20 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func TestChurn(t *testing.T) {
+	ring, err := consistenthashing.NewHashRing(20)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	Churn(t, ring, ChurnConfig{Seed: 42, Steps: 50, Keys: 200})
+
+	if ring.Generation() == 0 {
+		t.Error("Expected generation to advance during churn")
+	}
+}
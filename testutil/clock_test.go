@@ -0,0 +1,55 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	ticker := clock.NewTicker(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("Ticker fired before time advanced")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("Ticker fired before its interval")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case tick := <-ticker.C():
+		if !tick.Equal(start.Add(time.Minute)) {
+			t.Errorf("Expected tick at %v, got %v", start.Add(time.Minute), tick)
+		}
+	default:
+		t.Fatal("Ticker did not fire after its interval")
+	}
+	if !clock.Now().Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected now to be %v, got %v", start.Add(time.Minute), clock.Now())
+	}
+
+	// Unread ticks are dropped rather than queued
+	clock.Advance(5 * time.Minute)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Error("Expected missed ticks to be dropped")
+	default:
+	}
+
+	ticker.Stop()
+	clock.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Error("Stopped ticker fired")
+	default:
+	}
+}
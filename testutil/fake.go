@@ -0,0 +1,195 @@
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// ErrUnmappedKey is returned by FakeRing for keys without a scripted mapping
+// when no default mapping is set
+var ErrUnmappedKey = errors.New("key has no scripted mapping")
+
+// FakeRing is a deterministic Ring implementation whose key to node mapping
+// is fully scripted, so routing logic can be unit tested without real rings
+type FakeRing struct {
+	mu       sync.Mutex
+	nodes    map[string]*consistenthashing.Node
+	mappings map[string][]string
+	fallback []string
+	lookups  []string
+}
+
+// FakeRing must be usable wherever a Ring is expected
+var _ consistenthashing.Ring = (*FakeRing)(nil)
+
+// NewFakeRing creates a fake ring containing the given nodes
+func NewFakeRing(nodes ...*consistenthashing.Node) *FakeRing {
+	f := &FakeRing{
+		nodes:    make(map[string]*consistenthashing.Node),
+		mappings: make(map[string][]string),
+	}
+	for _, node := range nodes {
+		f.nodes[node.ID] = node
+	}
+	return f
+}
+
+// Map scripts key to resolve to the given node IDs, primary first
+func (f *FakeRing) Map(key string, nodeIDs ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.mappings[key] = nodeIDs
+}
+
+// SetDefault scripts the node IDs returned for keys without a mapping
+func (f *FakeRing) SetDefault(nodeIDs ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fallback = nodeIDs
+}
+
+// Lookups returns every key looked up so far, in call order
+func (f *FakeRing) Lookups() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.lookups...)
+}
+
+// AddNode adds a node. Adding an existing node is not an error, as with HashRing.
+func (f *FakeRing) AddNode(node *consistenthashing.Node) error {
+	if node == nil {
+		return errors.New("node cannot be nil")
+	}
+	if err := node.Validate(); err != nil {
+		return fmt.Errorf("invalid node: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.nodes[node.ID]; !exists {
+		f.nodes[node.ID] = node
+	}
+	return nil
+}
+
+// RemoveNode removes a node. Scripted mappings that reference it are kept but
+// skip the node until it is added again.
+func (f *FakeRing) RemoveNode(nodeID string) error {
+	if strings.TrimSpace(nodeID) == "" {
+		return consistenthashing.ErrInvalidNodeID
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.nodes[nodeID]; !exists {
+		return consistenthashing.ErrNodeNotFound
+	}
+	delete(f.nodes, nodeID)
+	return nil
+}
+
+// GetNode returns the first scripted node for key
+func (f *FakeRing) GetNode(key string) (*consistenthashing.Node, error) {
+	nodes, err := f.GetNodes(key, 1)
+	if err != nil {
+		return nil, err
+	}
+	return nodes[0], nil
+}
+
+// GetNodes returns up to count scripted nodes for key
+func (f *FakeRing) GetNodes(key string, count int) ([]*consistenthashing.Node, error) {
+	if key == "" {
+		return nil, errors.New("key cannot be empty")
+	}
+	if count <= 0 {
+		return nil, consistenthashing.ErrInvalidCount
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lookups = append(f.lookups, key)
+
+	if len(f.nodes) == 0 {
+		return nil, consistenthashing.ErrEmptyRing
+	}
+
+	nodeIDs, exists := f.mappings[key]
+	if !exists {
+		nodeIDs = f.fallback
+	}
+
+	nodes := make([]*consistenthashing.Node, 0, count)
+	for _, id := range nodeIDs {
+		if node, exists := f.nodes[id]; exists {
+			nodes = append(nodes, node)
+			if len(nodes) == count {
+				break
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnmappedKey, key)
+	}
+
+	return nodes, nil
+}
+
+// GetAllNodes returns all nodes sorted by ID
+func (f *FakeRing) GetAllNodes() []*consistenthashing.Node {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	nodes := make([]*consistenthashing.Node, 0, len(f.nodes))
+	for _, node := range f.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes
+}
+
+// GetNodeByID returns a node by its ID
+func (f *FakeRing) GetNodeByID(nodeID string) (*consistenthashing.Node, error) {
+	if strings.TrimSpace(nodeID) == "" {
+		return nil, consistenthashing.ErrInvalidNodeID
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	node, exists := f.nodes[nodeID]
+	if !exists {
+		return nil, consistenthashing.ErrNodeNotFound
+	}
+	return node, nil
+}
+
+// HasNode checks if a node exists
+func (f *FakeRing) HasNode(nodeID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, exists := f.nodes[nodeID]
+	return exists
+}
+
+// Size returns the number of nodes
+func (f *FakeRing) Size() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.nodes)
+}
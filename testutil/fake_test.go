@@ -0,0 +1,48 @@
+package testutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func TestFakeRing(t *testing.T) {
+	node1 := &consistenthashing.Node{ID: "node1", Host: "localhost", Port: 8080}
+	node2 := &consistenthashing.Node{ID: "node2", Host: "localhost", Port: 8081}
+	ring := NewFakeRing(node1, node2)
+
+	ring.Map("user:1", "node2", "node1")
+
+	node, err := ring.GetNode("user:1")
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+	if node != node2 {
+		t.Errorf("Expected scripted node2, got %s", node.ID)
+	}
+
+	// Unmapped keys fail until a default is set
+	if _, err := ring.GetNode("user:2"); !errors.Is(err, ErrUnmappedKey) {
+		t.Errorf("Expected ErrUnmappedKey, got %v", err)
+	}
+	ring.SetDefault("node1")
+	if node, _ := ring.GetNode("user:2"); node != node1 {
+		t.Error("Expected default mapping to node1")
+	}
+
+	// Removed nodes are skipped in scripted mappings
+	ring.RemoveNode("node2")
+	nodes, err := ring.GetNodes("user:1", 2)
+	if err != nil {
+		t.Fatalf("Failed to get nodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0] != node1 {
+		t.Errorf("Expected only node1 after removing node2, got %v", nodes)
+	}
+
+	lookups := ring.Lookups()
+	if len(lookups) != 4 || lookups[0] != "user:1" {
+		t.Errorf("Unexpected recorded lookups: %v", lookups)
+	}
+}
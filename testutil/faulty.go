@@ -0,0 +1,181 @@
+package testutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// FaultyRing wraps a real ring and injects delays, errors and stale views into
+// GetNode, GetNodes, AddNode and RemoveNode, so routing layers can exercise
+// their failure handling deterministically. Other methods pass through.
+type FaultyRing struct {
+	ring consistenthashing.Ring
+
+	mu         sync.Mutex
+	delays     map[consistenthashing.Op]time.Duration
+	queued     map[consistenthashing.Op][]error
+	persistent map[consistenthashing.Op]error
+	frozen     bool
+	pending    []func() error // Membership changes held back while frozen
+}
+
+// FaultyRing must be usable wherever a Ring is expected
+var _ consistenthashing.Ring = (*FaultyRing)(nil)
+
+// NewFaultyRing wraps ring without injecting any faults
+func NewFaultyRing(ring consistenthashing.Ring) *FaultyRing {
+	return &FaultyRing{
+		ring:       ring,
+		delays:     make(map[consistenthashing.Op]time.Duration),
+		queued:     make(map[consistenthashing.Op][]error),
+		persistent: make(map[consistenthashing.Op]error),
+	}
+}
+
+// SetDelay makes every call of op sleep for d first. A zero d removes the delay.
+func (f *FaultyRing) SetDelay(op consistenthashing.Op, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.delays[op] = d
+}
+
+// FailNext makes the next calls of op fail with errs, one error per call in
+// order, before the ring is consulted
+func (f *FaultyRing) FailNext(op consistenthashing.Op, errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queued[op] = append(f.queued[op], errs...)
+}
+
+// SetError makes every call of op fail with err once queued errors are used
+// up. A nil err stops the failures.
+func (f *FaultyRing) SetError(op consistenthashing.Op, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.persistent, op)
+		return
+	}
+	f.persistent[op] = err
+}
+
+// Freeze makes the ring serve a stale view: AddNode and RemoveNode succeed but
+// are held back, so lookups keep seeing the membership from before the freeze
+func (f *FaultyRing) Freeze() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.frozen = true
+}
+
+// Thaw applies the held back membership changes in order and stops serving a
+// stale view. It returns the first error a held back change produced.
+func (f *FaultyRing) Thaw() error {
+	f.mu.Lock()
+	pending := f.pending
+	f.pending = nil
+	f.frozen = false
+	f.mu.Unlock()
+
+	var first error
+	for _, apply := range pending {
+		if err := apply(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// inject applies the delay and returns the injected error for op, if any
+func (f *FaultyRing) inject(op consistenthashing.Op) error {
+	f.mu.Lock()
+	delay := f.delays[op]
+	var err error
+	if queued := f.queued[op]; len(queued) > 0 {
+		err = queued[0]
+		f.queued[op] = queued[1:]
+	} else {
+		err = f.persistent[op]
+	}
+	f.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	return err
+}
+
+// holdBack queues a membership change while frozen and reports whether it did
+func (f *FaultyRing) holdBack(apply func() error) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.frozen {
+		return false
+	}
+	f.pending = append(f.pending, apply)
+	return true
+}
+
+// AddNode adds a node unless a fault is injected
+func (f *FaultyRing) AddNode(node *consistenthashing.Node) error {
+	if err := f.inject(consistenthashing.OpAddNode); err != nil {
+		return err
+	}
+	if f.holdBack(func() error { return f.ring.AddNode(node) }) {
+		return nil
+	}
+	return f.ring.AddNode(node)
+}
+
+// RemoveNode removes a node unless a fault is injected
+func (f *FaultyRing) RemoveNode(nodeID string) error {
+	if err := f.inject(consistenthashing.OpRemoveNode); err != nil {
+		return err
+	}
+	if f.holdBack(func() error { return f.ring.RemoveNode(nodeID) }) {
+		return nil
+	}
+	return f.ring.RemoveNode(nodeID)
+}
+
+// GetNode looks up a key unless a fault is injected
+func (f *FaultyRing) GetNode(key string) (*consistenthashing.Node, error) {
+	if err := f.inject(consistenthashing.OpGetNode); err != nil {
+		return nil, err
+	}
+	return f.ring.GetNode(key)
+}
+
+// GetNodes looks up the replicas of a key unless a fault is injected
+func (f *FaultyRing) GetNodes(key string, count int) ([]*consistenthashing.Node, error) {
+	if err := f.inject(consistenthashing.OpGetNodes); err != nil {
+		return nil, err
+	}
+	return f.ring.GetNodes(key, count)
+}
+
+// GetAllNodes returns all nodes of the wrapped ring
+func (f *FaultyRing) GetAllNodes() []*consistenthashing.Node {
+	return f.ring.GetAllNodes()
+}
+
+// GetNodeByID returns a node of the wrapped ring
+func (f *FaultyRing) GetNodeByID(nodeID string) (*consistenthashing.Node, error) {
+	return f.ring.GetNodeByID(nodeID)
+}
+
+// HasNode checks if the wrapped ring has a node
+func (f *FaultyRing) HasNode(nodeID string) bool {
+	return f.ring.HasNode(nodeID)
+}
+
+// Size returns the number of nodes in the wrapped ring
+func (f *FaultyRing) Size() int {
+	return f.ring.Size()
+}
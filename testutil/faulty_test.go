@@ -0,0 +1,87 @@
+package testutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func TestFaultyRingErrors(t *testing.T) {
+	node1 := &consistenthashing.Node{ID: "node1", Host: "localhost", Port: 8080}
+	ring := NewFaultyRing(NewFakeRing(node1))
+	ring.ring.(*FakeRing).SetDefault("node1")
+
+	errTimeout := errors.New("timeout")
+	errRefused := errors.New("refused")
+	ring.FailNext(consistenthashing.OpGetNode, errTimeout, errRefused)
+
+	if _, err := ring.GetNode("key"); err != errTimeout {
+		t.Errorf("Expected first queued error, got %v", err)
+	}
+	if _, err := ring.GetNode("key"); err != errRefused {
+		t.Errorf("Expected second queued error, got %v", err)
+	}
+	if node, err := ring.GetNode("key"); err != nil || node != node1 {
+		t.Errorf("Expected lookups to recover, got %v (%v)", node, err)
+	}
+
+	// Faults are per operation
+	ring.SetError(consistenthashing.OpGetNodes, errTimeout)
+	if _, err := ring.GetNodes("key", 1); err != errTimeout {
+		t.Errorf("Expected persistent error, got %v", err)
+	}
+	if _, err := ring.GetNode("key"); err != nil {
+		t.Errorf("Expected GetNode to be unaffected, got %v", err)
+	}
+	ring.SetError(consistenthashing.OpGetNodes, nil)
+	if _, err := ring.GetNodes("key", 1); err != nil {
+		t.Errorf("Expected persistent error to be cleared, got %v", err)
+	}
+}
+
+func TestFaultyRingDelay(t *testing.T) {
+	ring := NewFaultyRing(NewFakeRing(&consistenthashing.Node{ID: "node1", Host: "localhost", Port: 8080}))
+	ring.SetDelay(consistenthashing.OpGetNode, 20*time.Millisecond)
+
+	start := time.Now()
+	ring.GetNode("key")
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected lookup to be delayed, took %v", elapsed)
+	}
+}
+
+func TestFaultyRingStaleView(t *testing.T) {
+	inner, err := consistenthashing.NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring := NewFaultyRing(inner)
+	ring.AddNode(&consistenthashing.Node{ID: "node1", Host: "localhost", Port: 8080})
+
+	ring.Freeze()
+	if err := ring.AddNode(&consistenthashing.Node{ID: "node2", Host: "localhost", Port: 8081}); err != nil {
+		t.Fatalf("Expected held back add to succeed, got %v", err)
+	}
+	if err := ring.RemoveNode("node1"); err != nil {
+		t.Fatalf("Expected held back remove to succeed, got %v", err)
+	}
+	if node, _ := ring.GetNode("key"); node.ID != "node1" {
+		t.Errorf("Expected stale view to route to node1, got %s", node.ID)
+	}
+
+	if err := ring.Thaw(); err != nil {
+		t.Fatalf("Failed to thaw: %v", err)
+	}
+	if node, _ := ring.GetNode("key"); node.ID != "node2" {
+		t.Errorf("Expected thawed view to route to node2, got %s", node.ID)
+	}
+
+	// Errors from held back changes surface on Thaw
+	ring.Freeze()
+	ring.RemoveNode("missing")
+	if err := ring.Thaw(); !errors.Is(err, consistenthashing.ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound from thaw, got %v", err)
+	}
+}
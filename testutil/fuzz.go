@@ -0,0 +1,65 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// FuzzLookupInvariant exercises AddNode, GetNode, GetNodes and RemoveNode with
+// arbitrary inputs, suitable as the body of a native fuzz target. Invalid
+// input must be rejected with an error rather than a panic, and lookups must
+// return distinct, non-nil nodes that include the primary owner. The ring is
+// left as it was found.
+func FuzzLookupInvariant(t testing.TB, ring consistenthashing.Ring, node consistenthashing.Node, key string, count int) {
+	t.Helper()
+
+	added := false
+	if !ring.HasNode(node.ID) {
+		added = ring.AddNode(&node) == nil
+	}
+	defer func() {
+		if added {
+			if err := ring.RemoveNode(node.ID); err != nil {
+				t.Errorf("failed to remove fuzzed node %q: %v", node.ID, err)
+			}
+		}
+	}()
+
+	primary, err := ring.GetNode(key)
+	if err != nil {
+		if primary != nil {
+			t.Errorf("GetNode(%q) returned a node together with error %v", key, err)
+		}
+		return
+	}
+	if primary == nil {
+		t.Fatalf("GetNode(%q) returned a nil node without an error", key)
+	}
+
+	nodes, err := ring.GetNodes(key, count)
+	if err != nil {
+		if count > 0 {
+			t.Errorf("GetNodes(%q, %d) failed although GetNode succeeded: %v", key, count, err)
+		}
+		return
+	}
+	if len(nodes) == 0 || len(nodes) > count || len(nodes) > ring.Size() {
+		t.Errorf("GetNodes(%q, %d) returned %d nodes for a ring of size %d", key, count, len(nodes), ring.Size())
+	}
+
+	seen := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		if n == nil {
+			t.Fatalf("GetNodes(%q, %d) returned a nil node", key, count)
+		}
+		if seen[n.ID] {
+			t.Errorf("GetNodes(%q, %d) returned %s twice", key, count, n.ID)
+		}
+		seen[n.ID] = true
+	}
+
+	if count >= ring.Size() && !seen[primary.ID] {
+		t.Errorf("GetNodes(%q, %d) does not include primary owner %s", key, count, primary.ID)
+	}
+}
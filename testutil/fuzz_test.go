@@ -0,0 +1,41 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// maxFuzzWeight bounds fuzzed weights to keep iterations fast
+const maxFuzzWeight = 64
+
+func FuzzHashRing(f *testing.F) {
+	f.Add("node-x", "localhost", 8080, 1, "user:1", 2)
+	f.Add("", "", 0, -5, "", 0)
+	f.Add("  ", "host", 65536, maxFuzzWeight, "\x00\xff", 100)
+	f.Add("node\n1", "::1", 1, 3, "😀", -1)
+
+	f.Fuzz(func(t *testing.T, id, host string, port, weight int, key string, count int) {
+		if weight > maxFuzzWeight {
+			// Valid but huge weights only slow the fuzzer down
+			weight %= maxFuzzWeight
+		}
+		if count > 64 {
+			count %= 64
+		}
+
+		ring, err := consistenthashing.NewHashRing(4)
+		if err != nil {
+			t.Fatalf("Failed to create ring: %v", err)
+		}
+		ring.AddNode(&consistenthashing.Node{ID: "node1", Host: "localhost", Port: 8080})
+		ring.AddNode(&consistenthashing.Node{ID: "node2", Host: "localhost", Port: 8081, Weight: 2})
+
+		node := consistenthashing.Node{ID: id, Host: host, Port: port, Weight: weight}
+		FuzzLookupInvariant(t, ring, node, key, count)
+
+		if err := ring.ValidateRing(); err != nil {
+			t.Errorf("Ring invalid after fuzzed operations: %v", err)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+// CheckDeterministic verifies that, for a fixed ring, every key resolves to the
+// same node on repeated lookups
+func CheckDeterministic(t testing.TB, ring consistenthashing.Ring, keys []string) {
+	t.Helper()
+
+	first := ownerIDs(t, ring, keys)
+	for round := 0; round < 3; round++ {
+		again := ownerIDs(t, ring, keys)
+		for i, key := range keys {
+			if first[i] != again[i] {
+				t.Errorf("key %s mapped to %s and then to %s on an unchanged ring", key, first[i], again[i])
+			}
+		}
+	}
+}
+
+// CheckBoundedAddMovement adds node to ring and verifies that moved keys only
+// move to the new node and that the moved fraction is at most the new node's
+// weight share plus epsilon (1/n + epsilon for equal weights). The node is
+// removed again afterwards and the moved fraction is returned.
+func CheckBoundedAddMovement(t testing.TB, ring consistenthashing.Ring, node *consistenthashing.Node, keys []string, epsilon float64) float64 {
+	t.Helper()
+
+	before := ownerIDs(t, ring, keys)
+	if err := ring.AddNode(node); err != nil {
+		t.Fatalf("failed to add %s: %v", node.ID, err)
+	}
+	defer func() {
+		if err := ring.RemoveNode(node.ID); err != nil {
+			t.Errorf("failed to restore ring by removing %s: %v", node.ID, err)
+		}
+	}()
+	after := ownerIDs(t, ring, keys)
+
+	moved := 0
+	for i, key := range keys {
+		if before[i] == after[i] {
+			continue
+		}
+		moved++
+		if after[i] != node.ID {
+			t.Errorf("key %s moved from %s to %s instead of the added node %s", key, before[i], after[i], node.ID)
+		}
+	}
+
+	fraction := float64(moved) / float64(len(keys))
+	if bound := weightShare(ring, node.ID) + epsilon; fraction > bound {
+		t.Errorf("adding %s moved %.2f%% of keys, expected at most %.2f%%", node.ID, fraction*100, bound*100)
+	}
+	return fraction
+}
+
+// CheckRemovalMovesOnlyOwnedKeys removes nodeID from ring and verifies that
+// only keys it owned change owner. The node is added back afterwards.
+func CheckRemovalMovesOnlyOwnedKeys(t testing.TB, ring consistenthashing.Ring, nodeID string, keys []string) {
+	t.Helper()
+
+	node, err := ring.GetNodeByID(nodeID)
+	if err != nil {
+		t.Fatalf("failed to look up %s: %v", nodeID, err)
+	}
+
+	before := ownerIDs(t, ring, keys)
+	if err := ring.RemoveNode(nodeID); err != nil {
+		t.Fatalf("failed to remove %s: %v", nodeID, err)
+	}
+	defer func() {
+		if err := ring.AddNode(node); err != nil {
+			t.Errorf("failed to restore ring by adding %s: %v", nodeID, err)
+		}
+	}()
+	if ring.Size() == 0 {
+		return
+	}
+	after := ownerIDs(t, ring, keys)
+
+	for i, key := range keys {
+		if before[i] != after[i] && before[i] != nodeID {
+			t.Errorf("key %s moved from %s to %s although only %s was removed", key, before[i], after[i], nodeID)
+		}
+	}
+}
+
+// ownerIDs resolves the owner ID of every key, failing the test on errors
+func ownerIDs(t testing.TB, ring consistenthashing.Ring, keys []string) []string {
+	t.Helper()
+
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		node, err := ring.GetNode(key)
+		if err != nil {
+			t.Fatalf("failed to look up %s: %v", key, err)
+		}
+		ids[i] = node.ID
+	}
+	return ids
+}
+
+// entitledRing is implemented by rings that know each node's entitled share,
+// such as HashRing with its capacity classes and weight curve
+type entitledRing interface {
+	Entitlements() map[string]float64
+}
+
+// weightShare returns nodeID's entitled share of ring: the ring's own
+// entitlement where it has one, else its share of the total weight
+func weightShare(ring consistenthashing.Ring, nodeID string) float64 {
+	if r, ok := ring.(entitledRing); ok {
+		return r.Entitlements()[nodeID]
+	}
+
+	total, own := 0, 0
+	for _, node := range ring.GetAllNodes() {
+		weight := node.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		total += weight
+		if node.ID == nodeID {
+			own = weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(own) / float64(total)
+}
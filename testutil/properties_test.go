@@ -0,0 +1,49 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alexnthnz/consistent-hashing"
+)
+
+func TestPropertyHelpers(t *testing.T) {
+	ring, err := consistenthashing.NewHashRing(200)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		ring.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+
+	keys := make([]string, 2000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key_%d", i)
+	}
+
+	CheckDeterministic(t, ring, keys)
+
+	newNode := &consistenthashing.Node{ID: "node5", Host: "localhost", Port: 8085}
+	CheckBoundedAddMovement(t, ring, newNode, keys, 0.1)
+	if ring.HasNode("node5") {
+		t.Error("Expected added node to be removed again")
+	}
+
+	CheckRemovalMovesOnlyOwnedKeys(t, ring, "node2", keys)
+	if !ring.HasNode("node2") {
+		t.Error("Expected removed node to be added back")
+	}
+
+	// A node in a capacity class is entitled to its class's weight
+	classed, err := consistenthashing.NewHashRing(200, consistenthashing.WithCapacityClasses(map[string]int{"big": 4}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		classed.AddNode(&consistenthashing.Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	big := &consistenthashing.Node{ID: "big", Host: "localhost", Port: 9000, Class: "big"}
+	if moved := CheckBoundedAddMovement(t, classed, big, keys, 0.1); moved < 0.25 {
+		t.Errorf("Expected the big node to take about 4/9 of the keys, took %.2f%%", moved*100)
+	}
+}
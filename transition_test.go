@@ -0,0 +1,96 @@
+package consistenthashing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newTransitionRings(t *testing.T) (*HashRing, *HashRing) {
+	t.Helper()
+	old, err := NewHashRing(50)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	new, err := NewHashRing(50, WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		old.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+		new.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	return old, new
+}
+
+func TestTransitionRing(t *testing.T) {
+	old, new := newTransitionRings(t)
+	tr, err := NewTransitionRing(old, new)
+	if err != nil {
+		t.Fatalf("Failed to create transition ring: %v", err)
+	}
+	if !tr.InTransition() || tr.Current() != new {
+		t.Fatal("Expected a transition towards the new ring")
+	}
+
+	split := 0
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("key%d", i)
+		prev, _ := old.GetNode(key)
+		next, _ := new.GetNode(key)
+
+		reads, err := tr.ReadNodes(key)
+		if err != nil {
+			t.Fatalf("Failed to get read nodes: %v", err)
+		}
+		if reads[0].ID != prev.ID || reads[len(reads)-1] != next {
+			t.Errorf("Expected reads %s then %s, got %v", prev.ID, next.ID, reads)
+		}
+		if prev.ID == next.ID && len(reads) != 1 {
+			t.Errorf("Expected a single read node when owners agree, got %v", reads)
+		}
+		if len(reads) == 2 {
+			split++
+		}
+
+		if write, _ := tr.WriteNode(key); write != next {
+			t.Errorf("Expected writes to go to %s, got %s", next.ID, write.ID)
+		}
+	}
+	if split == 0 {
+		t.Error("Expected the hash change to move some keys")
+	}
+
+	if err := tr.Cutover(); err != nil {
+		t.Fatalf("Failed to cut over: %v", err)
+	}
+	if reads, _ := tr.ReadNodes("key1"); len(reads) != 1 || tr.InTransition() {
+		t.Errorf("Expected a single read node after cutover, got %v", reads)
+	}
+	if err := tr.Cutover(); !errors.Is(err, ErrNoTransition) {
+		t.Errorf("Expected ErrNoTransition, got %v", err)
+	}
+}
+
+func TestTransitionRingRollback(t *testing.T) {
+	old, new := newTransitionRings(t)
+	tr, _ := NewTransitionRing(old, new)
+
+	if err := tr.Rollback(); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if tr.Current() != old || tr.InTransition() {
+		t.Error("Expected the old ring to be current again")
+	}
+	expected, _ := old.GetNode("key")
+	if write, _ := tr.WriteNode("key"); write != expected {
+		t.Errorf("Expected writes to return to %s, got %s", expected.ID, write.ID)
+	}
+	if err := tr.Rollback(); !errors.Is(err, ErrNoTransition) {
+		t.Errorf("Expected ErrNoTransition, got %v", err)
+	}
+
+	if _, err := NewTransitionRing(old, nil); err == nil {
+		t.Error("Expected error for nil ring")
+	}
+}
@@ -0,0 +1,67 @@
+package consistenthashing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// TwemproxyPool describes the server pool written by WriteTwemproxyConfig.
+// Empty fields fall back to twemproxy's usual ketama settings.
+type TwemproxyPool struct {
+	Name         string // Pool name, defaults to "ring"
+	Listen       string // Proxy listen address, defaults to "127.0.0.1:22121"
+	Hash         string // twemproxy hash, defaults to "fnv1a_64"
+	Distribution string // twemproxy distribution, defaults to "ketama"
+	Redis        bool   // Speak the redis protocol instead of memcached
+}
+
+// WriteTwemproxyConfig renders the ring's members and weights as a
+// twemproxy (nutcracker) server pool, so legacy proxy configurations can be
+// generated from the ring as the source of truth. Servers are written in ID
+// order as "host:port:weight id". twemproxy places keys with its own
+// continuum, so the proxy shares the ring's membership and weights but not
+// its exact key placement.
+func (hr *HashRing) WriteTwemproxyConfig(w io.Writer, pool TwemproxyPool) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	if pool.Name == "" {
+		pool.Name = "ring"
+	}
+	if pool.Listen == "" {
+		pool.Listen = "127.0.0.1:22121"
+	}
+	if pool.Hash == "" {
+		pool.Hash = "fnv1a_64"
+	}
+	if pool.Distribution == "" {
+		pool.Distribution = "ketama"
+	}
+
+	hr.mu.RLock()
+	nodes := hr.sortedNodesLocked()
+	weights := make([]int, len(nodes))
+	for i, node := range nodes {
+		weights[i] = hr.weightLocked(node)
+	}
+	hr.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s:\n", pool.Name)
+	fmt.Fprintf(bw, "  listen: %s\n", pool.Listen)
+	fmt.Fprintf(bw, "  hash: %s\n", pool.Hash)
+	fmt.Fprintf(bw, "  distribution: %s\n", pool.Distribution)
+	fmt.Fprintf(bw, "  redis: %t\n", pool.Redis)
+	if len(nodes) == 0 {
+		fmt.Fprintf(bw, "  servers: []\n")
+	} else {
+		fmt.Fprintf(bw, "  servers:\n")
+		for i, node := range nodes {
+			fmt.Fprintf(bw, "   - %s:%d:%d %s\n", node.Host, node.Port, weights[i], node.ID)
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,44 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteTwemproxyConfig(t *testing.T) {
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	ring.AddNode(&Node{ID: "cache-b", Host: "10.0.0.2", Port: 6379, Weight: 3})
+	ring.AddNode(&Node{ID: "cache-a", Host: "10.0.0.1", Port: 6379})
+
+	var buf bytes.Buffer
+	if err := ring.WriteTwemproxyConfig(&buf, TwemproxyPool{Name: "sessions", Redis: true}); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	expected := `sessions:
+  listen: 127.0.0.1:22121
+  hash: fnv1a_64
+  distribution: ketama
+  redis: true
+  servers:
+   - 10.0.0.1:6379:1 cache-a
+   - 10.0.0.2:6379:3 cache-b
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected config:\n%s", buf.String())
+	}
+
+	empty, _ := NewHashRing(10)
+	buf.Reset()
+	empty.WriteTwemproxyConfig(&buf, TwemproxyPool{})
+	if !bytes.Contains(buf.Bytes(), []byte("servers: []")) {
+		t.Errorf("Expected an empty server list, got:\n%s", buf.String())
+	}
+
+	if err := ring.WriteTwemproxyConfig(nil, TwemproxyPool{}); err == nil {
+		t.Error("Expected error for nil writer")
+	}
+}
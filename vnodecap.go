@@ -0,0 +1,91 @@
+package consistenthashing
+
+import "sort"
+
+// WithMaxVirtualNodes caps the total number of virtual nodes in the ring.
+// When the members' counts would add up to more than n, every node's count
+// is scaled down by the same factor, which bounds memory and sort cost while
+// keeping ownership proportional. Each node keeps at least one virtual node,
+// so a ring with more than n members exceeds the cap. Zero means no cap.
+//
+// A change of scale resizes every node, so membership changes on a ring over
+// its cap rebuild the whole ring; SetMembers applies many changes with a
+// single rebuild. Replica indexes are kept, so only the replicas added or
+// dropped move keys.
+func WithMaxVirtualNodes(n int) Option {
+	return func(hr *HashRing) {
+		hr.maxVirtualNodes = max(n, 0)
+	}
+}
+
+// MaxVirtualNodes returns the cap on the total number of virtual nodes, or 0
+// if there is none
+func (hr *HashRing) MaxVirtualNodes() int {
+	return hr.maxVirtualNodes
+}
+
+// overCap returns total if curve counts adding up to total exceed the cap,
+// and 0 if they fit
+func (hr *HashRing) overCap(total int) int {
+	if hr.maxVirtualNodes == 0 || total <= hr.maxVirtualNodes {
+		return 0
+	}
+	return total
+}
+
+// scaleCount scales a curve count down to a share of limit, in proportion to
+// total. A zero total means the counts fit and are kept.
+func scaleCount(count, total, limit int) int {
+	if total == 0 {
+		return count
+	}
+	return max(int(int64(count)*int64(limit)/int64(total)), 1)
+}
+
+// rescaleLocked re-applies the cap to the members in hr.nodes and reports
+// whether it rebuilt the virtual nodes because the scale changed. Membership
+// changes update hr.nodes first and only adjust virtual nodes themselves when
+// it returns false, so no node is ever placed at an uncapped count. Callers
+// must hold the write lock.
+func (hr *HashRing) rescaleLocked() bool {
+	if hr.maxVirtualNodes == 0 {
+		return false
+	}
+
+	total := 0
+	for _, node := range hr.nodes {
+		count, _ := hr.curveCount(node)
+		total += count
+	}
+	total = hr.overCap(total)
+	if total == hr.scaledTotal {
+		return false
+	}
+	hr.scaledTotal = total
+
+	// Place members in ID order so hash ties resolve deterministically
+	nodes := make([]*Node, 0, len(hr.nodes))
+	for _, node := range hr.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	placed, _ := hr.placeLocked(nodes)
+
+	clear(hr.virtualNodes)
+	hr.virtualNodes = append(hr.virtualNodes[:0], placed...)
+	return true
+}
+
+// placeMembersLocked adds the virtual nodes of members, already in hr.nodes,
+// in a single merge. Callers must hold the write lock and have checked that
+// rescaleLocked didn't rebuild the ring.
+func (hr *HashRing) placeMembersLocked(members []*Node) {
+	spans := make([]replicaSpan, 0, len(members))
+	for _, node := range members {
+		count, _ := hr.virtualCount(node)
+		spans = append(spans, replicaSpan{node: node, to: count})
+	}
+	hr.addReplicasLocked(spans...)
+}
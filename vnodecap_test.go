@@ -0,0 +1,156 @@
+package consistenthashing
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMaxVirtualNodes(t *testing.T) {
+	hr, err := NewHashRing(500, WithMaxVirtualNodes(10000), WithHashFunction(&SHA256Hasher{}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+
+	// Under the cap, counts are not scaled
+	for i := 0; i < 10; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	if got := hr.VirtualSize(); got != 5000 {
+		t.Errorf("Expected 5000 virtual nodes under the cap, got %d", got)
+	}
+
+	for i := 10; i < 100; i++ {
+		hr.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i, Weight: 1 + i%4})
+	}
+	if got := hr.VirtualSize(); got > 10000 || got < 9900 {
+		t.Errorf("Expected close to 10000 virtual nodes at the cap, got %d", got)
+	}
+	if err := hr.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+	if stats := hr.BalanceStats(); stats.MaxSkew > 1.5 {
+		t.Errorf("Expected ownership to stay proportional, got skew %.2f", stats.MaxSkew)
+	}
+
+	// Dropping back under the cap restores the full counts
+	for i := 10; i < 100; i++ {
+		hr.RemoveNode(fmt.Sprintf("node%d", i))
+	}
+	if got := hr.VirtualSize(); got != 5000 {
+		t.Errorf("Expected 5000 virtual nodes after shrinking, got %d", got)
+	}
+	if err := hr.ValidateRingDeep(); err != nil {
+		t.Fatalf("Invariants violated: %v", err)
+	}
+}
+
+func TestMaxVirtualNodesChanges(t *testing.T) {
+	hr, err := NewHashRing(100, WithMaxVirtualNodes(1000), WithCapacityClasses(map[string]int{"big": 4}))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	var nodes []*Node
+	for i := 0; i < 20; i++ {
+		nodes = append(nodes, &Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i})
+	}
+	if _, err := hr.SetMembers(nodes); err != nil {
+		t.Fatalf("Failed to set members: %v", err)
+	}
+	if got := hr.VirtualSize(); got != 1000 {
+		t.Errorf("Expected 1000 virtual nodes, got %d", got)
+	}
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"update weight", func() error { return hr.UpdateNodeWeight("node0", 5) }},
+		{"add class node", func() error {
+			return hr.AddNode(&Node{ID: "big", Host: "localhost", Port: 9000, Class: "big"})
+		}},
+		{"set class weight", func() error { return hr.SetClassWeight("big", 8) }},
+		{"remove node", func() error { return hr.RemoveNode("node1") }},
+	}
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if err := hr.ValidateRingDeep(); err != nil {
+			t.Fatalf("%s: invariants violated: %v", step.name, err)
+		}
+		if got := hr.VirtualSize(); got > 1000 {
+			t.Errorf("%s: expected at most 1000 virtual nodes, got %d", step.name, got)
+		}
+	}
+
+	// The cap survives a snapshot round trip
+	var buf bytes.Buffer
+	if err := hr.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	snapshot, err := ReadSnapshot(&buf, nil)
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	restored, err := RestoreHashRing(snapshot)
+	if err != nil {
+		t.Fatalf("Failed to restore ring: %v", err)
+	}
+	if restored.MaxVirtualNodes() != 1000 || restored.VirtualSize() != hr.VirtualSize() {
+		t.Errorf("Expected restored ring with cap 1000 and %d virtual nodes, got cap %d and %d",
+			hr.VirtualSize(), restored.MaxVirtualNodes(), restored.VirtualSize())
+	}
+}
+
+func TestMaxVirtualNodesBoundsPlacement(t *testing.T) {
+	hr, err := NewHashRing(10, WithMaxVirtualNodes(1000))
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	hr.AddNode(&Node{ID: "small", Host: "localhost", Port: 8080})
+
+	// The heavy node's uncapped 100000 replicas are never built
+	if err := hr.AddNode(&Node{ID: "heavy", Host: "localhost", Port: 8081, Weight: MaxNodeWeight}); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+	if got := cap(hr.scratch); got > 1000 {
+		t.Errorf("Expected placement to stay within the cap, built %d virtual nodes", got)
+	}
+	if got := hr.VirtualSize(); got > 1001 {
+		t.Errorf("Expected at most 1001 virtual nodes, got %d", got)
+	}
+}
+
+func TestSetMembersMatchesAddNode(t *testing.T) {
+	for _, limit := range []int{0, 500} {
+		batched, _ := NewHashRing(20, WithMaxVirtualNodes(limit))
+		incremental, _ := NewHashRing(20, WithMaxVirtualNodes(limit))
+		var members []*Node
+		for i := 0; i < 40; i++ {
+			node := &Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i, Weight: 1 + i%3}
+			members = append(members, node)
+			incremental.AddNode(node)
+		}
+		if _, err := batched.SetMembers(members); err != nil {
+			t.Fatalf("Failed to set members: %v", err)
+		}
+		if err := batched.ValidateRingDeep(); err != nil {
+			t.Fatalf("cap %d: invariants violated: %v", limit, err)
+		}
+		if batched.Fingerprint() != incremental.Fingerprint() {
+			t.Errorf("cap %d: expected SetMembers to place nodes like AddNode", limit)
+		}
+
+		// Removing and updating in one batch matches too
+		members[0] = &Node{ID: "node0", Host: "localhost", Port: 8080, Weight: 5}
+		batched.SetMembers(members[:30])
+		for i := 30; i < 40; i++ {
+			incremental.RemoveNode(fmt.Sprintf("node%d", i))
+		}
+		incremental.UpdateNodeWeight("node0", 5)
+		if batched.Fingerprint() != incremental.Fingerprint() {
+			t.Errorf("cap %d: expected batched removals and updates to match", limit)
+		}
+	}
+}
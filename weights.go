@@ -0,0 +1,216 @@
+package consistenthashing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// WeightSource supplies the desired weight of each node, keyed by node ID
+type WeightSource interface {
+	Weights(ctx context.Context) (map[string]int, error)
+}
+
+// WeightSourceFunc adapts a function to the WeightSource interface
+type WeightSourceFunc func(ctx context.Context) (map[string]int, error)
+
+// Weights calls f
+func (f WeightSourceFunc) Weights(ctx context.Context) (map[string]int, error) {
+	return f(ctx)
+}
+
+// FileWeightSource reads weights from a JSON object such as {"node1": 3}
+// stored in a file
+type FileWeightSource struct {
+	Path string
+}
+
+// Weights reads and decodes the file
+func (s FileWeightSource) Weights(ctx context.Context) (map[string]int, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWeights(data)
+}
+
+// HTTPWeightSource fetches weights as a JSON object from a URL. Consul KV can
+// be used by pointing it at a key with the ?raw query parameter.
+type HTTPWeightSource struct {
+	URL    string
+	Client *http.Client // Defaults to http.DefaultClient
+}
+
+// Weights fetches and decodes the URL
+func (s HTTPWeightSource) Weights(ctx context.Context) (map[string]int, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weight source returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWeights(data)
+}
+
+func decodeWeights(data []byte) (map[string]int, error) {
+	var weights map[string]int
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return nil, fmt.Errorf("invalid weights: %w", err)
+	}
+	return weights, nil
+}
+
+// WeightSyncConfig bounds how much a WeightSyncer changes the ring per sync
+type WeightSyncConfig struct {
+	Interval   time.Duration // Time between syncs in Run, defaults to 30s
+	MaxUpdates int           // Nodes updated per sync, 0 for no limit
+	MaxStep    int           // Largest weight change per node per sync, 0 for no limit
+	OnError    func(error)   // Called with errors from Run, which keeps going
+}
+
+// WeightSyncer periodically pulls weights from a WeightSource and applies them
+// with UpdateNodeWeight. Changes are bounded per sync, so a bad or abrupt
+// update can only move a limited share of keys before it is noticed; nodes
+// converge on their target weights over several syncs.
+type WeightSyncer struct {
+	ring   *HashRing
+	source WeightSource
+	config WeightSyncConfig
+}
+
+// NewWeightSyncer creates a syncer for ring. Weights for nodes that are not in
+// the ring are ignored, and non-positive weights are rejected.
+func NewWeightSyncer(ring *HashRing, source WeightSource, config WeightSyncConfig) (*WeightSyncer, error) {
+	if ring == nil {
+		return nil, errors.New("ring cannot be nil")
+	}
+	if source == nil {
+		return nil, errors.New("weight source cannot be nil")
+	}
+	if config.MaxUpdates < 0 || config.MaxStep < 0 {
+		return nil, errors.New("weight sync limits cannot be negative")
+	}
+	if config.Interval <= 0 {
+		config.Interval = 30 * time.Second
+	}
+
+	return &WeightSyncer{ring: ring, source: source, config: config}, nil
+}
+
+// weightChange is a pending weight update for one node
+type weightChange struct {
+	id       string
+	current  int
+	target   int
+	distance int
+}
+
+// Sync pulls weights once and applies the bounded set of changes, returning
+// the number of nodes updated. Nodes furthest from their target are updated
+// first, ties broken by ID.
+func (s *WeightSyncer) Sync(ctx context.Context) (int, error) {
+	weights, err := s.source.Weights(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var changes []weightChange
+	for id, target := range weights {
+		// Weights for nodes not in the ring are ignored, valid or not
+		node, err := s.ring.GetNodeByID(id)
+		if err != nil {
+			continue
+		}
+		if target <= 0 || target > MaxNodeWeight {
+			return 0, fmt.Errorf("invalid weight %d for node %s", target, id)
+		}
+		// Nodes in a capacity class are tuned through the class
+		if node.Class != "" {
+			continue
+		}
+		current := nodeWeight(node)
+		if current == target {
+			continue
+		}
+
+		distance := target - current
+		if distance < 0 {
+			distance = -distance
+		}
+		changes = append(changes, weightChange{id: id, current: current, target: target, distance: distance})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].distance != changes[j].distance {
+			return changes[i].distance > changes[j].distance
+		}
+		return changes[i].id < changes[j].id
+	})
+	if s.config.MaxUpdates > 0 && len(changes) > s.config.MaxUpdates {
+		changes = changes[:s.config.MaxUpdates]
+	}
+
+	updated := 0
+	for _, change := range changes {
+		weight := change.target
+		if step := s.config.MaxStep; step > 0 && change.distance > step {
+			if change.target > change.current {
+				weight = change.current + step
+			} else {
+				weight = change.current - step
+			}
+		}
+
+		// A node removed since the lookup is skipped, not an error
+		err := s.ring.UpdateNodeWeight(change.id, weight)
+		if errors.Is(err, ErrNodeNotFound) {
+			continue
+		}
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// Run syncs every interval until ctx is done
+func (s *WeightSyncer) Run(ctx context.Context) {
+	ticker := s.ring.clock.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sync(ctx); err != nil && s.config.OnError != nil {
+			s.config.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
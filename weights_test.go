@@ -0,0 +1,169 @@
+package consistenthashing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newWeightTestRing(t *testing.T) *HashRing {
+	t.Helper()
+	ring, err := NewHashRing(10)
+	if err != nil {
+		t.Fatalf("Failed to create ring: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		ring.AddNode(&Node{ID: fmt.Sprintf("node%d", i), Host: "localhost", Port: 8080 + i, Weight: 1})
+	}
+	return ring
+}
+
+func weightOf(t *testing.T, ring *HashRing, id string) int {
+	t.Helper()
+	node, err := ring.GetNodeByID(id)
+	if err != nil {
+		t.Fatalf("Failed to get node %s: %v", id, err)
+	}
+	return node.Weight
+}
+
+func TestFileWeightSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.json")
+	if err := os.WriteFile(path, []byte(`{"node1": 3}`), 0o644); err != nil {
+		t.Fatalf("Failed to write weights: %v", err)
+	}
+
+	weights, err := FileWeightSource{Path: path}.Weights(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read weights: %v", err)
+	}
+	if weights["node1"] != 3 {
+		t.Errorf("Expected weight 3, got %v", weights)
+	}
+
+	os.WriteFile(path, []byte(`not json`), 0o644)
+	if _, err := (FileWeightSource{Path: path}).Weights(context.Background()); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestHTTPWeightSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/weights" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"node0": 2, "node2": 4}`)
+	}))
+	defer server.Close()
+
+	weights, err := HTTPWeightSource{URL: server.URL + "/weights"}.Weights(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch weights: %v", err)
+	}
+	if weights["node0"] != 2 || weights["node2"] != 4 {
+		t.Errorf("Unexpected weights %v", weights)
+	}
+
+	if _, err := (HTTPWeightSource{URL: server.URL + "/missing"}).Weights(context.Background()); err == nil {
+		t.Error("Expected error for non-200 response")
+	}
+}
+
+func TestWeightSyncerBoundedChurn(t *testing.T) {
+	ring := newWeightTestRing(t)
+	source := WeightSourceFunc(func(ctx context.Context) (map[string]int, error) {
+		return map[string]int{"node0": 4, "node1": 3, "node2": 1, "unknown": 9}, nil
+	})
+
+	if _, err := NewWeightSyncer(ring, source, WeightSyncConfig{MaxStep: -1}); err == nil {
+		t.Error("Expected error for negative limit")
+	}
+
+	syncer, err := NewWeightSyncer(ring, source, WeightSyncConfig{MaxUpdates: 1, MaxStep: 2})
+	if err != nil {
+		t.Fatalf("Failed to create syncer: %v", err)
+	}
+
+	// node0 is furthest from its target, so it moves first, by at most 2
+	if updated, err := syncer.Sync(context.Background()); err != nil || updated != 1 {
+		t.Fatalf("Expected 1 update, got %d (%v)", updated, err)
+	}
+	if w := weightOf(t, ring, "node0"); w != 3 {
+		t.Errorf("Expected node0 weight 3, got %d", w)
+	}
+
+	// The nodes converge over further syncs
+	for i := 0; i < 3; i++ {
+		if _, err := syncer.Sync(context.Background()); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+	}
+	if w0, w1 := weightOf(t, ring, "node0"), weightOf(t, ring, "node1"); w0 != 4 || w1 != 3 {
+		t.Errorf("Expected weights 4 and 3, got %d and %d", w0, w1)
+	}
+	if updated, _ := syncer.Sync(context.Background()); updated != 0 {
+		t.Errorf("Expected no updates once converged, got %d", updated)
+	}
+}
+
+func TestWeightSyncerRejectsInvalidWeights(t *testing.T) {
+	ring := newWeightTestRing(t)
+	syncer, _ := NewWeightSyncer(ring, WeightSourceFunc(func(ctx context.Context) (map[string]int, error) {
+		return map[string]int{"node0": 0}, nil
+	}), WeightSyncConfig{})
+
+	if _, err := syncer.Sync(context.Background()); err == nil {
+		t.Error("Expected error for zero weight")
+	}
+
+	// Invalid weights for nodes outside the ring are ignored like any other
+	syncer, _ = NewWeightSyncer(ring, WeightSourceFunc(func(ctx context.Context) (map[string]int, error) {
+		return map[string]int{"node0": 2, "gone": -1}, nil
+	}), WeightSyncConfig{})
+	if updated, err := syncer.Sync(context.Background()); err != nil || updated != 1 {
+		t.Errorf("Expected 1 update despite the invalid weight of a non-member, got %d (%v)", updated, err)
+	}
+}
+
+func TestWeightSyncerRun(t *testing.T) {
+	ring := newWeightTestRing(t)
+	synced := make(chan struct{}, 1)
+	source := WeightSourceFunc(func(ctx context.Context) (map[string]int, error) {
+		select {
+		case synced <- struct{}{}:
+		default:
+		}
+		return nil, fmt.Errorf("unavailable")
+	})
+
+	errs := make(chan error, 1)
+	syncer, _ := NewWeightSyncer(ring, source, WeightSyncConfig{
+		Interval: time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		syncer.Run(ctx)
+		close(done)
+	}()
+
+	<-synced
+	if err := <-errs; err == nil {
+		t.Error("Expected source error to be reported")
+	}
+	cancel()
+	<-done
+}